@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/kkstas/tr-backend/internal/backup"
+)
+
+// runBackup writes a snapshot of the database while the server may keep running. It
+// doesn't run migrations, so the snapshot has the schema of the live database.
+func runBackup(ctx context.Context, getenv func(string) string, stdout io.Writer) error {
+	dbName, options, err := getBackupConfig(getenv)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := backup.Create(ctx, dbName, options)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "created %s (%d bytes)\n", filepath.Join(options.Dir, snapshot.Name), snapshot.Size)
+	return nil
+}
+
+// runRestore replaces the database with a snapshot. The server has to be stopped first.
+// Encrypted snapshots are decrypted with identities from BACKUP_AGE_IDENTITY_FILE.
+func runRestore(ctx context.Context, getenv func(string) string, snapshotPath string, stdout io.Writer) error {
+	dbName, _, err := getBackupConfig(getenv)
+	if err != nil {
+		return err
+	}
+
+	identities, err := readIdentities(getenv("BACKUP_AGE_IDENTITY_FILE"))
+	if err != nil {
+		return err
+	}
+
+	previous, err := backup.Restore(ctx, snapshotPath, dbName, identities...)
+	if err != nil {
+		return err
+	}
+
+	if previous == "" {
+		fmt.Fprintf(stdout, "restored %s from %s\n", dbName, snapshotPath)
+		return nil
+	}
+	fmt.Fprintf(stdout, "restored %s from %s, previous database kept as %s\n", dbName, snapshotPath, previous)
+	return nil
+}
+
+func readIdentities(path string) ([]age.Identity, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open BACKUP_AGE_IDENTITY_FILE: %w", err)
+	}
+	defer f.Close()
+
+	identities, err := age.ParseIdentities(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse BACKUP_AGE_IDENTITY_FILE: %w", err)
+	}
+	return identities, nil
+}
+
+func getBackupConfig(getenv func(string) string) (dbName string, options backup.Options, err error) {
+	errs := []string{}
+
+	dbName = getenv("DB_NAME")
+	if dbName == "" {
+		errs = append(errs, "DB_NAME (string) is not defined")
+	}
+
+	options = backupEnv(getenv, &errs)
+
+	if len(errs) != 0 {
+		return "", options, errors.New(strings.Join(append([]string{"ERROR: Environment variables failed validation"}, errs...), "\n - "))
+	}
+
+	return dbName, options, nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/kkstas/tr-backend/internal/backup"
+	"github.com/kkstas/tr-backend/internal/database"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestBackupAndRestore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("restores database from snapshot written by backup", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		dbName := filepath.Join(t.TempDir(), "test.db")
+		env := map[string]string{
+			"DB_NAME":     dbName,
+			"BACKUP_DIR":  t.TempDir(),
+			"BACKUP_GZIP": "true",
+		}
+		getenv := func(key string) string { return env[key] }
+
+		db, err := database.OpenDB(ctx, dbName)
+		testutils.AssertNoError(t, err)
+		user := testutils.CreateTestUser(t, db)
+
+		var stdout bytes.Buffer
+		testutils.AssertNoError(t, runCommand(ctx, []string{"backup"}, getenv, &stdout))
+
+		snapshots, err := backup.List(env["BACKUP_DIR"])
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(snapshots), 1)
+
+		_, err = db.ExecContext(ctx, `DELETE FROM users`)
+		testutils.AssertNoError(t, err)
+		testutils.AssertNoError(t, db.Close())
+
+		snapshotPath := filepath.Join(env["BACKUP_DIR"], snapshots[0])
+		testutils.AssertNoError(t, runCommand(ctx, []string{"restore", snapshotPath}, getenv, &stdout))
+
+		previous, err := filepath.Glob(dbName + ".before-restore-*")
+		testutils.AssertNoError(t, err)
+		if len(previous) == 0 {
+			t.Error("expected previous database to be kept")
+		}
+
+		db, err = database.OpenDB(ctx, dbName)
+		testutils.AssertNoError(t, err)
+		defer db.Close()
+		_, err = testutils.NewTestUserService(db).FindOneByID(ctx, user.ID)
+		testutils.AssertNoError(t, err)
+	})
+
+	t.Run("encrypts backup to recipients and restores it with identity file", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		identity, err := age.GenerateX25519Identity()
+		testutils.AssertNoError(t, err)
+		identityFile := filepath.Join(t.TempDir(), "key.txt")
+		testutils.AssertNoError(t, os.WriteFile(identityFile, []byte(identity.String()+"\n"), 0o600))
+
+		dbName := filepath.Join(t.TempDir(), "test.db")
+		env := map[string]string{
+			"DB_NAME":               dbName,
+			"BACKUP_DIR":            t.TempDir(),
+			"BACKUP_AGE_RECIPIENTS": identity.Recipient().String(),
+		}
+		getenv := func(key string) string { return env[key] }
+
+		db, err := database.OpenDB(ctx, dbName)
+		testutils.AssertNoError(t, err)
+		user := testutils.CreateTestUser(t, db)
+		testutils.AssertNoError(t, db.Close())
+
+		var stdout bytes.Buffer
+		testutils.AssertNoError(t, runCommand(ctx, []string{"backup"}, getenv, &stdout))
+
+		snapshots, err := backup.List(env["BACKUP_DIR"])
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(snapshots), 1)
+		snapshotPath := filepath.Join(env["BACKUP_DIR"], snapshots[0])
+
+		err = runCommand(ctx, []string{"restore", snapshotPath}, getenv, &stdout)
+		if !errors.Is(err, backup.ErrIdentityRequired) {
+			t.Fatalf("expected error %q, got %v", backup.ErrIdentityRequired, err)
+		}
+
+		env["BACKUP_AGE_IDENTITY_FILE"] = identityFile
+		testutils.AssertNoError(t, runCommand(ctx, []string{"restore", snapshotPath}, getenv, &stdout))
+
+		db, err = database.OpenDB(ctx, dbName)
+		testutils.AssertNoError(t, err)
+		defer db.Close()
+		_, err = testutils.NewTestUserService(db).FindOneByID(ctx, user.ID)
+		testutils.AssertNoError(t, err)
+	})
+
+	t.Run("returns error for invalid age recipients", func(t *testing.T) {
+		t.Parallel()
+		env := map[string]string{"DB_NAME": "test.db", "BACKUP_AGE_RECIPIENTS": "not-a-recipient"}
+
+		err := runCommand(t.Context(), []string{"backup"}, func(key string) string { return env[key] }, &bytes.Buffer{})
+		if err == nil || !strings.Contains(err.Error(), "BACKUP_AGE_RECIPIENTS") {
+			t.Errorf("expected BACKUP_AGE_RECIPIENTS validation error, got %v", err)
+		}
+	})
+
+	t.Run("returns error for unknown command", func(t *testing.T) {
+		t.Parallel()
+		err := runCommand(t.Context(), []string{"vacuum"}, func(string) string { return "" }, &bytes.Buffer{})
+		if err == nil {
+			t.Error("expected an error but didn't get one")
+		}
+	})
+}
@@ -4,13 +4,40 @@ import (
 	"errors"
 	"strconv"
 	"strings"
+	"time"
 
+	"filippo.io/age"
+
+	"github.com/kkstas/tr-backend/internal/backup"
+	"github.com/kkstas/tr-backend/internal/blobstore"
 	"github.com/kkstas/tr-backend/internal/config"
+	"github.com/kkstas/tr-backend/internal/tracing"
+)
+
+const (
+	defaultTrashRetentionDays = 30
+	defaultReceiptMaxSizeMB   = 10
+	defaultReceiptDir         = "receipts"
+	defaultReadTimeout        = 15 * time.Second
+	defaultWriteTimeout       = 30 * time.Second
+	defaultIdleTimeout        = 60 * time.Second
+	defaultShutdownTimeout    = 10 * time.Second
+	defaultBackupDir          = "backups"
+	defaultBackupKeep         = 7
 )
 
 type cfg struct {
-	port   string
-	dbName string
+	port           string
+	dbName         string
+	receiptStorage string
+	receiptDir     string
+	s3             blobstore.S3Config
+	traceExporter  string
+
+	readTimeout     time.Duration
+	writeTimeout    time.Duration
+	idleTimeout     time.Duration
+	shutdownTimeout time.Duration
 }
 
 func getConfigs(getenv func(string) string) (*cfg, *config.Config, error) {
@@ -33,22 +60,147 @@ func getConfigs(getenv func(string) string) (*cfg, *config.Config, error) {
 		enableRegister = true
 	}
 
+	var enableMetrics bool
+	if getenv("ENABLE_METRICS") == "true" {
+		enableMetrics = true
+	}
+
+	trashRetentionDays := defaultTrashRetentionDays
+	if val := getenv("TRASH_RETENTION_DAYS"); val != "" {
+		days, err := strconv.Atoi(val)
+		if err != nil || days < 1 {
+			errs = append(errs, "TRASH_RETENTION_DAYS is not a valid positive number")
+		}
+		trashRetentionDays = days
+	}
+
 	dbName := getenv("DB_NAME")
 	if dbName == "" {
 		errs = append(errs, "DB_NAME (string) is not defined")
 	}
 
+	receiptMaxSizeMB := defaultReceiptMaxSizeMB
+	if val := getenv("RECEIPT_MAX_SIZE_MB"); val != "" {
+		mb, err := strconv.Atoi(val)
+		if err != nil || mb < 1 {
+			errs = append(errs, "RECEIPT_MAX_SIZE_MB is not a valid positive number")
+		}
+		receiptMaxSizeMB = mb
+	}
+
+	receiptStorage := getenv("RECEIPT_STORAGE")
+	if receiptStorage == "" {
+		receiptStorage = "local"
+	}
+
+	receiptDir := getenv("RECEIPT_DIR")
+	if receiptDir == "" {
+		receiptDir = defaultReceiptDir
+	}
+
+	s3 := blobstore.S3Config{
+		Endpoint:        getenv("S3_ENDPOINT"),
+		Region:          getenv("S3_REGION"),
+		Bucket:          getenv("S3_BUCKET"),
+		AccessKeyID:     getenv("S3_ACCESS_KEY_ID"),
+		SecretAccessKey: getenv("S3_SECRET_ACCESS_KEY"),
+	}
+
+	switch receiptStorage {
+	case "local":
+	case "s3":
+		if s3.Endpoint == "" || s3.Region == "" || s3.Bucket == "" || s3.AccessKeyID == "" || s3.SecretAccessKey == "" {
+			errs = append(errs, "S3_ENDPOINT, S3_REGION, S3_BUCKET, S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY are required when RECEIPT_STORAGE is s3")
+		}
+	default:
+		errs = append(errs, "RECEIPT_STORAGE must be either local or s3")
+	}
+
+	traceExporter := getenv("TRACE_EXPORTER")
+	if traceExporter == "" {
+		traceExporter = tracing.ExporterNone
+	}
+	switch traceExporter {
+	case tracing.ExporterNone, tracing.ExporterOTLP, tracing.ExporterStdout:
+	default:
+		errs = append(errs, "TRACE_EXPORTER must be one of none, otlp or stdout")
+	}
+
+	backupOptions := backupEnv(getenv, &errs)
+
+	readTimeout := durationEnv(getenv, "READ_TIMEOUT", defaultReadTimeout, &errs)
+	writeTimeout := durationEnv(getenv, "WRITE_TIMEOUT", defaultWriteTimeout, &errs)
+	idleTimeout := durationEnv(getenv, "IDLE_TIMEOUT", defaultIdleTimeout, &errs)
+	shutdownTimeout := durationEnv(getenv, "SHUTDOWN_TIMEOUT", defaultShutdownTimeout, &errs)
+
 	if len(errs) != 0 {
 		return nil, nil, errors.New(strings.Join(append([]string{"ERROR: Environment variables failed validation"}, errs...), "\n - "))
 	}
 
 	return &cfg{
-			port:   port,
-			dbName: dbName,
+			port:           port,
+			dbName:         dbName,
+			receiptStorage: receiptStorage,
+			receiptDir:     receiptDir,
+			s3:             s3,
+			traceExporter:  traceExporter,
+
+			readTimeout:     readTimeout,
+			writeTimeout:    writeTimeout,
+			idleTimeout:     idleTimeout,
+			shutdownTimeout: shutdownTimeout,
 		},
 		&config.Config{
-			JWTSecretKey:   []byte(jwtSecretKey),
-			EnableRegister: enableRegister,
+			JWTSecretKey:     []byte(jwtSecretKey),
+			EnableRegister:   enableRegister,
+			EnableMetrics:    enableMetrics,
+			TrashRetention:   time.Duration(trashRetentionDays) * 24 * time.Hour,
+			ReceiptMaxSize:   int64(receiptMaxSizeMB) << 20,
+			AdminToken:       getenv("ADMIN_TOKEN"),
+			BackupDir:        backupOptions.Dir,
+			BackupGzip:       backupOptions.Gzip,
+			BackupKeep:       backupOptions.Keep,
+			BackupRecipients: backupOptions.Recipients,
 		},
 		nil
 }
+
+func durationEnv(getenv func(string) string, name string, defaultValue time.Duration, errs *[]string) time.Duration {
+	val := getenv(name)
+	if val == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil || d <= 0 {
+		*errs = append(*errs, name+" is not a valid positive duration (e.g. 30s)")
+		return defaultValue
+	}
+	return d
+}
+
+func backupEnv(getenv func(string) string, errs *[]string) backup.Options {
+	dir := getenv("BACKUP_DIR")
+	if dir == "" {
+		dir = defaultBackupDir
+	}
+
+	keep := defaultBackupKeep
+	if val := getenv("BACKUP_KEEP"); val != "" {
+		n, err := strconv.Atoi(val)
+		if err != nil || n < 0 {
+			*errs = append(*errs, "BACKUP_KEEP is not a valid non-negative number")
+		}
+		keep = n
+	}
+
+	var recipients []age.Recipient
+	if val := getenv("BACKUP_AGE_RECIPIENTS"); val != "" {
+		var err error
+		recipients, err = age.ParseRecipients(strings.NewReader(strings.ReplaceAll(val, ",", "\n")))
+		if err != nil {
+			*errs = append(*errs, "BACKUP_AGE_RECIPIENTS is not a comma-separated list of age recipients")
+		}
+	}
+
+	return backup.Options{Dir: dir, Gzip: getenv("BACKUP_GZIP") == "true", Keep: keep, Recipients: recipients}
+}
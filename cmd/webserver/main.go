@@ -2,14 +2,36 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 )
 
 func main() {
 	ctx := context.Background()
-	if err := run(ctx, os.Getenv); err != nil {
+	if err := runCommand(ctx, os.Args[1:], os.Getenv, os.Stdout); err != nil {
 		fmt.Fprintf(os.Stderr, "%s\n", err)
 		os.Exit(1)
 	}
 }
+
+// runCommand starts the server when called without arguments, otherwise it runs one of
+// the maintenance commands.
+func runCommand(ctx context.Context, args []string, getenv func(string) string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return run(ctx, getenv)
+	}
+
+	switch args[0] {
+	case "backup":
+		return runBackup(ctx, getenv, stdout)
+	case "restore":
+		if len(args) != 2 {
+			return errors.New("usage: webserver restore <snapshot>")
+		}
+		return runRestore(ctx, getenv, args[1], stdout)
+	default:
+		return fmt.Errorf("unknown command %q, expected backup or restore", args[0])
+	}
+}
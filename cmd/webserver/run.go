@@ -2,21 +2,30 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/kkstas/tr-backend/internal/app"
+	"github.com/kkstas/tr-backend/internal/blobstore"
 	"github.com/kkstas/tr-backend/internal/database"
+	"github.com/kkstas/tr-backend/internal/logging"
+	"github.com/kkstas/tr-backend/internal/tracing"
 	_ "modernc.org/sqlite"
 )
 
-func run(ctx context.Context, getenv func(string) string) error {
-	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+// run starts the server and blocks until ctx is cancelled or SIGINT/SIGTERM is received.
+// It then stops accepting connections, waits up to the shutdown timeout for in-flight
+// requests and background jobs to finish, and closes the database.
+func run(ctx context.Context, getenv func(string) string) (err error) {
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
 	config, appConfig, err := getConfigs(getenv)
@@ -30,24 +39,107 @@ func run(ctx context.Context, getenv func(string) string) error {
 	}
 	defer db.Close()
 
-	app := app.NewApplication(appConfig, db, initLogger(os.Stdout))
+	blobStore, err := newBlobStore(config)
+	if err != nil {
+		return fmt.Errorf("failed to set up receipt storage: %w", err)
+	}
+
+	tracerProvider, shutdownTracing, err := tracing.NewProvider(ctx, config.traceExporter, os.Stdout)
+	if err != nil {
+		return fmt.Errorf("failed to set up tracing: %w", err)
+	}
+	defer func() {
+		// Buffered spans are flushed on every exit path, so ctx may be cancelled already.
+		flushCtx, cancelFlush := context.WithTimeout(context.WithoutCancel(ctx), config.shutdownTimeout)
+		defer cancelFlush()
+		if flushErr := shutdownTracing(flushCtx); flushErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to flush traces: %w", flushErr))
+		}
+	}()
+
+	logger := initLogger(os.Stdout)
+	app := app.NewApplication(appConfig, db, logger, blobStore, tracerProvider)
 
 	server := &http.Server{ // nolint: exhaustruct
 		Addr:              ":" + config.port,
 		ReadHeaderTimeout: 3 * time.Second,
+		ReadTimeout:       config.readTimeout,
+		WriteTimeout:      config.writeTimeout,
+		IdleTimeout:       config.idleTimeout,
 		Handler:           app,
 	}
 
-	if err := server.ListenAndServe(); err != nil {
-		return fmt.Errorf("failed to ListenAndServe: %w", err)
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", server.Addr, err)
+	}
+
+	jobsCtx, stopJobs := context.WithCancel(context.WithoutCancel(ctx))
+	defer stopJobs()
+	jobsDone := make(chan struct{})
+	go func() {
+		app.RunJobs(jobsCtx)
+		close(jobsDone)
+	}()
+
+	serveErr := serve(ctx, server, listener, config.shutdownTimeout, logger)
+
+	stopJobs()
+	select {
+	case <-jobsDone:
+	case <-time.After(config.shutdownTimeout):
+		serveErr = errors.Join(serveErr, errors.New("background jobs did not stop in time"))
 	}
 
+	if serveErr != nil {
+		return serveErr
+	}
+
+	logger.Info("server stopped")
 	return nil
 }
 
+// serve handles requests on listener until ctx is cancelled. It then stops accepting
+// connections and waits up to timeout for in-flight requests to finish.
+func serve(ctx context.Context, server *http.Server, listener net.Listener, timeout time.Duration, logger *slog.Logger) error {
+	serverErr := make(chan error, 1)
+	go func() {
+		logger.Info("server started", "addr", listener.Addr().String())
+		serverErr <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-serverErr:
+		return fmt.Errorf("failed to serve: %w", err)
+	case <-ctx.Done():
+	}
+
+	logger.Info("shutting down", "timeout", timeout.String())
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), timeout)
+	defer cancelShutdown()
+
+	shutdownErr := server.Shutdown(shutdownCtx)
+	if err := <-serverErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		shutdownErr = errors.Join(shutdownErr, err)
+	}
+
+	if shutdownErr != nil {
+		return fmt.Errorf("failed to shut down gracefully: %w", shutdownErr)
+	}
+	return nil
+}
+
+func newBlobStore(config *cfg) (blobstore.BlobStore, error) {
+	if config.receiptStorage == "s3" {
+		return blobstore.NewS3Store(config.s3, &http.Client{Timeout: 30 * time.Second}) // nolint: exhaustruct
+	}
+	return blobstore.NewLocalStore(config.receiptDir)
+}
+
 func initLogger(w io.Writer) *slog.Logger {
-	return slog.New(slog.NewJSONHandler(
+	return slog.New(logging.NewContextHandler(slog.NewJSONHandler(
 		w,
 		&slog.HandlerOptions{Level: slog.LevelDebug}, // nolint: exhaustruct
-	))
+	)))
 }
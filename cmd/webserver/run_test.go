@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	t.Run("serves requests and shuts down when context is cancelled", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		baseURL, errCh := startServer(t, ctx)
+
+		cancel()
+		testutils.AssertNoError(t, waitForRun(t, errCh))
+
+		if _, err := http.Get(baseURL + "/health-check"); err == nil { // nolint: noctx
+			t.Error("expected server to stop accepting connections")
+		}
+	})
+
+	t.Run("returns error if port is already in use", func(t *testing.T) {
+		t.Parallel()
+		l, err := net.Listen("tcp", ":0")
+		testutils.AssertNoError(t, err)
+		defer l.Close()
+		_, port, err := net.SplitHostPort(l.Addr().String())
+		testutils.AssertNoError(t, err)
+		env := map[string]string{
+			"PORT":           port,
+			"JWT_SECRET_KEY": "secret-key",
+			"DB_NAME":        filepath.Join(t.TempDir(), "test.db"),
+			"RECEIPT_DIR":    t.TempDir(),
+		}
+
+		err = run(t.Context(), func(key string) string { return env[key] })
+		if err == nil || !strings.Contains(err.Error(), "failed to listen") {
+			t.Errorf("expected listen error, got %v", err)
+		}
+	})
+
+	t.Run("returns error for invalid configuration", func(t *testing.T) {
+		t.Parallel()
+		err := run(t.Context(), func(string) string { return "" })
+		if err == nil {
+			t.Error("expected an error but didn't get one")
+		}
+	})
+}
+
+func TestServe(t *testing.T) {
+	t.Parallel()
+
+	t.Run("finishes in-flight requests before shutting down", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		server := &http.Server{ // nolint: exhaustruct
+			ReadHeaderTimeout: time.Second,
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				close(started)
+				<-release
+				w.WriteHeader(http.StatusOK)
+			}),
+		}
+		shuttingDown := make(chan struct{})
+		server.RegisterOnShutdown(func() { close(shuttingDown) })
+
+		listener, err := net.Listen("tcp", "localhost:0")
+		testutils.AssertNoError(t, err)
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- serve(ctx, server, listener, 5*time.Second, slog.New(slog.DiscardHandler))
+		}()
+
+		resCh := make(chan *http.Response, 1)
+		go func() {
+			res, err := http.Get("http://" + listener.Addr().String()) // nolint: noctx
+			if err != nil {
+				t.Errorf("in-flight request failed: %v", err)
+				close(resCh)
+				return
+			}
+			resCh <- res
+		}()
+
+		<-started
+		cancel()
+		<-shuttingDown
+		close(release)
+
+		res, ok := <-resCh
+		if !ok {
+			t.FailNow()
+		}
+		res.Body.Close()
+		testutils.AssertStatus(t, res.StatusCode, http.StatusOK)
+		testutils.AssertNoError(t, waitForRun(t, errCh))
+
+		if _, err := net.Dial("tcp", listener.Addr().String()); err == nil {
+			t.Error("expected server to stop accepting connections")
+		}
+	})
+}
+
+func startServer(t *testing.T, ctx context.Context) (baseURL string, errCh <-chan error) { // nolint: revive
+	t.Helper()
+
+	port := freePort(t)
+	env := map[string]string{
+		"PORT":             port,
+		"JWT_SECRET_KEY":   "secret-key",
+		"DB_NAME":          filepath.Join(t.TempDir(), "test.db"),
+		"RECEIPT_DIR":      t.TempDir(),
+		"SHUTDOWN_TIMEOUT": "5s",
+	}
+
+	ch := make(chan error, 1)
+	go func() {
+		ch <- run(ctx, func(key string) string { return env[key] })
+	}()
+
+	baseURL = "http://localhost:" + port
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		res, err := http.Get(baseURL + "/health-check") // nolint: noctx
+		if err == nil {
+			res.Body.Close()
+			if res.StatusCode == http.StatusOK {
+				return baseURL, ch
+			}
+		}
+		select {
+		case err := <-ch:
+			t.Fatalf("server exited before becoming healthy: %v", err)
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+	t.Fatal("server did not become healthy in time")
+	return "", nil
+}
+
+func waitForRun(t *testing.T, errCh <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-errCh:
+		return err
+	case <-time.After(10 * time.Second):
+		t.Fatal("run did not return after context was cancelled")
+		return nil
+	}
+}
+
+func freePort(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "localhost:0")
+	testutils.AssertNoError(t, err)
+	defer l.Close()
+	_, port, _ := strings.Cut(l.Addr().String(), ":")
+	if _, err := strconv.Atoi(port); err != nil {
+		t.Fatalf("unexpected listener address %s", l.Addr())
+	}
+	return port
+}
@@ -1,25 +1,42 @@
 package app
 
 import (
-	"database/sql"
+	"context"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"sync"
+	"time"
 
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kkstas/tr-backend/internal/backup"
+	"github.com/kkstas/tr-backend/internal/blobstore"
 	"github.com/kkstas/tr-backend/internal/config"
+	"github.com/kkstas/tr-backend/internal/database"
 	"github.com/kkstas/tr-backend/internal/handlers"
+	"github.com/kkstas/tr-backend/internal/handlers/misc"
+	"github.com/kkstas/tr-backend/internal/jobs"
+	"github.com/kkstas/tr-backend/internal/metrics"
 	"github.com/kkstas/tr-backend/internal/middleware"
 	"github.com/kkstas/tr-backend/internal/repositories"
 	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/tracing"
 )
 
+const trashPurgeInterval = time.Hour
+
 type Application struct {
 	http.Handler
+	jobs []func(ctx context.Context)
 }
 
 func NewApplication(
 	config *config.Config,
-	db *sql.DB,
+	db *database.DB,
 	logger *slog.Logger,
+	blobStore blobstore.BlobStore,
+	tracerProvider trace.TracerProvider,
 ) *Application {
 
 	app := new(Application)
@@ -27,12 +44,74 @@ func NewApplication(
 	userRepo := repositories.NewUserRepo(db)
 	userService := services.NewUserService(userRepo)
 	vaultRepo := repositories.NewVaultRepo(db)
-	vaultService := services.NewVaultService(vaultRepo, userService)
+	auditRepo := repositories.NewAuditRepo(db)
+	paymentMethodRepo := repositories.NewPaymentMethodRepo(db)
+	vaultService := services.NewVaultService(db, vaultRepo, auditRepo, paymentMethodRepo, userService)
+	auditService := services.NewAuditService(auditRepo, vaultService)
 	expenseCategoryRepo := repositories.NewExpenseCategoryRepo(db)
-	expenseCategoryService := services.NewExpenseCategoryService(expenseCategoryRepo, vaultService)
+	expenseCategoryService := services.NewExpenseCategoryService(db, expenseCategoryRepo, auditRepo, vaultService)
+	paymentMethodService := services.NewPaymentMethodService(paymentMethodRepo, vaultService)
+	accountRepo := repositories.NewAccountRepo(db)
+	accountService := services.NewAccountService(accountRepo, vaultService)
+	transferRepo := repositories.NewTransferRepo(db)
+	transferService := services.NewTransferService(transferRepo, vaultService, accountService)
+	tagRepo := repositories.NewTagRepo(db)
+	tagService := services.NewTagService(tagRepo, vaultService)
+	incomeRepo := repositories.NewIncomeRepo(db)
+	incomeService := services.NewIncomeService(incomeRepo, vaultService, accountService)
+	expenseRepo := repositories.NewExpenseRepo(db)
+	expenseService := services.NewExpenseService(db, expenseRepo, auditRepo, vaultService, expenseCategoryService, paymentMethodService, accountService, tagService)
+
+	receiptRepo := repositories.NewReceiptRepo(db)
+	receiptService := services.NewReceiptService(receiptRepo, expenseService, blobStore, config.ReceiptMaxSize)
+
+	trashService := services.NewTrashService(expenseRepo, vaultRepo, receiptRepo, blobStore)
+
+	settlementRepo := repositories.NewSettlementRepo(db)
+	settlementService := services.NewSettlementService(settlementRepo, vaultService)
+
+	reportRepo := repositories.NewReportRepo(db)
+	reportService := services.NewReportService(reportRepo, vaultService, expenseCategoryService, paymentMethodService, tagService)
 
-	mux := handlers.SetupRoutes(config, logger, userService, vaultService, expenseCategoryService)
-	app.Handler = middleware.LogHTTP(logger, mux)
+	backupService := services.NewBackupService(db, backup.Options{Dir: config.BackupDir, Gzip: config.BackupGzip, Keep: config.BackupKeep, Recipients: config.BackupRecipients})
+
+	m := metrics.New(db)
+	jobsHealth := jobs.NewHealth()
+
+	app.jobs = append(app.jobs, jobs.PurgeTrash(logger, jobsHealth, trashPurgeInterval, config.TrashRetention, map[string]jobs.Purger{
+		"trash": trashService,
+	}))
+
+	readinessChecks := []misc.ReadinessCheck{
+		{Name: "database", Check: db.PingContext},
+		{Name: "migrations", Check: func(ctx context.Context) error {
+			pending, err := database.PendingMigrations(ctx, db.DB)
+			if err != nil {
+				return err
+			}
+			if pending != 0 {
+				return fmt.Errorf("%d migrations pending", pending)
+			}
+			return nil
+		}},
+		{Name: "workers", Check: func(context.Context) error { return jobsHealth.Check() }},
+	}
+
+	mux := handlers.SetupRoutes(config, logger, userService, vaultService, auditService, expenseCategoryService, paymentMethodService, accountService, transferService, tagService, incomeService, expenseService, receiptService, settlementService, reportService, backupService, m, readinessChecks)
+	app.Handler = middleware.RequestID(middleware.LogHTTP(logger, tracing.Middleware(tracerProvider, m.Middleware(mux))))
 
 	return app
 }
+
+// RunJobs runs background jobs and blocks until all of them return after ctx is cancelled.
+func (a *Application) RunJobs(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, job := range a.jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			job(ctx)
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,337 @@
+package backup
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+	_ "modernc.org/sqlite" // nolint: revive
+)
+
+const (
+	snapshotPrefix = "backup-"
+	snapshotExt    = ".db"
+	gzipExt        = ".gz"
+	ageExt         = ".age"
+	timeFormat     = "20060102T150405.000Z"
+)
+
+var (
+	ErrIntegrityCheckFailed = errors.New("snapshot failed integrity check")
+	ErrIdentityRequired     = errors.New("snapshot is encrypted, an age identity is required to restore it")
+)
+
+// Options configure where snapshots are written. Keep is the number of most recent
+// snapshots retained in Dir, zero keeps all of them. Snapshots are encrypted with age
+// to Recipients, if any are given.
+type Options struct {
+	Dir        string
+	Gzip       bool
+	Keep       int
+	Recipients []age.Recipient
+}
+
+type Snapshot struct {
+	Name      string `json:"name"`
+	Size      int64  `json:"size"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// Create writes a consistent snapshot of the SQLite database at dbPath into opts.Dir and
+// removes snapshots exceeding opts.Keep. The database is opened read-only and VACUUM INTO
+// reads inside a single transaction, so it doesn't block writers of a database in WAL mode.
+func Create(ctx context.Context, dbPath string, opts Options) (*Snapshot, error) {
+	if err := os.MkdirAll(opts.Dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory %s: %w", opts.Dir, err)
+	}
+
+	params := url.Values{}
+	params.Add("mode", "ro")
+	params.Add("_pragma", "busy_timeout(5000)")
+	db, err := sql.Open("sqlite", "file:"+dbPath+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	createdAt := time.Now().UTC()
+	name := snapshotPrefix + createdAt.Format(timeFormat) + snapshotExt
+	tmp := filepath.Join(opts.Dir, "."+name+".tmp")
+	defer os.Remove(tmp) // nolint: errcheck
+
+	if _, err := db.ExecContext(ctx, `VACUUM INTO $1`, tmp); err != nil {
+		return nil, fmt.Errorf("failed to snapshot database: %w", err)
+	}
+
+	if opts.Gzip {
+		name += gzipExt
+		compressed := filepath.Join(opts.Dir, "."+name+".tmp")
+		defer os.Remove(compressed) // nolint: errcheck
+		if err := compress(tmp, compressed); err != nil {
+			return nil, err
+		}
+		tmp = compressed
+	}
+
+	if len(opts.Recipients) != 0 {
+		name += ageExt
+		encrypted := filepath.Join(opts.Dir, "."+name+".tmp")
+		defer os.Remove(encrypted) // nolint: errcheck
+		if err := encrypt(tmp, encrypted, opts.Recipients); err != nil {
+			return nil, err
+		}
+		tmp = encrypted
+	}
+
+	path := filepath.Join(opts.Dir, name)
+	if err := os.Rename(tmp, path); err != nil {
+		return nil, fmt.Errorf("failed to move snapshot into place: %w", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat snapshot: %w", err)
+	}
+
+	if err := prune(opts.Dir, opts.Keep); err != nil {
+		return nil, err
+	}
+
+	return &Snapshot{Name: name, Size: info.Size(), CreatedAt: createdAt.Format(time.RFC3339)}, nil
+}
+
+// Restore replaces the database file at dbPath with the snapshot at snapshotPath, which
+// may be gzipped and encrypted to one of identities. The snapshot is verified with PRAGMA
+// integrity_check before anything is replaced. The current database and its WAL files are
+// kept next to it with a timestamped ".before-restore-" suffix, whose path is returned,
+// or "" if there was no database. It must only run while no process has dbPath open.
+func Restore(ctx context.Context, snapshotPath, dbPath string, identities ...age.Identity) (string, error) {
+	tmp := dbPath + ".restore"
+	defer os.Remove(tmp) // nolint: errcheck
+
+	if err := copySnapshot(snapshotPath, tmp, identities); err != nil {
+		return "", err
+	}
+
+	if err := checkIntegrity(ctx, tmp); err != nil {
+		return "", err
+	}
+
+	previous, err := moveAside(dbPath)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(tmp, dbPath); err != nil {
+		return "", fmt.Errorf("failed to move snapshot into place: %w", err)
+	}
+	return previous, nil
+}
+
+// moveAside renames the database at dbPath and its WAL files to a name that doesn't
+// exist yet, so that earlier copies kept by Restore are never overwritten.
+func moveAside(dbPath string) (string, error) {
+	suffixes := []string{"", "-wal", "-shm"}
+	previous := dbPath + ".before-restore-" + time.Now().UTC().Format(timeFormat)
+
+	found := false
+	for _, suffix := range suffixes {
+		if _, err := os.Lstat(previous + suffix); err == nil {
+			return "", fmt.Errorf("refusing to overwrite %s", previous+suffix)
+		}
+		if _, err := os.Lstat(dbPath + suffix); err == nil {
+			found = true
+		}
+	}
+	if !found {
+		return "", nil
+	}
+
+	for _, suffix := range suffixes {
+		err := os.Rename(dbPath+suffix, previous+suffix)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("failed to move current database file %s aside: %w", dbPath+suffix, err)
+		}
+	}
+	return previous, nil
+}
+
+func checkIntegrity(ctx context.Context, path string) error {
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		return fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx, `PRAGMA integrity_check`)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrIntegrityCheckFailed, err)
+	}
+	defer rows.Close()
+
+	var problems []string
+	for rows.Next() {
+		var result string
+		if err := rows.Scan(&result); err != nil {
+			return fmt.Errorf("failed to scan integrity check result: %w", err)
+		}
+		if result != "ok" {
+			problems = append(problems, result)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrIntegrityCheckFailed, err)
+	}
+
+	if len(problems) != 0 {
+		return fmt.Errorf("%w: %s", ErrIntegrityCheckFailed, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func compress(src, dst string) error {
+	return transform(src, dst, "compress", func(w io.Writer) (io.WriteCloser, error) {
+		return gzip.NewWriter(w), nil
+	})
+}
+
+func encrypt(src, dst string, recipients []age.Recipient) error {
+	return transform(src, dst, "encrypt", func(w io.Writer) (io.WriteCloser, error) {
+		return age.Encrypt(w, recipients...)
+	})
+}
+
+// transform writes src to a new file dst through the writer returned by wrap.
+func transform(src, dst, action string, wrap func(io.Writer) (io.WriteCloser, error)) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", dst, err)
+	}
+	defer func() {
+		if closeErr := out.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close %s: %w", dst, closeErr)
+		}
+	}()
+
+	w, err := wrap(out)
+	if err != nil {
+		return fmt.Errorf("failed to %s snapshot: %w", action, err)
+	}
+	if _, err := io.Copy(w, in); err != nil {
+		return fmt.Errorf("failed to %s snapshot: %w", action, err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to %s snapshot: %w", action, err)
+	}
+	return nil
+}
+
+func copySnapshot(src, dst string, identities []age.Identity) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer in.Close()
+
+	var r io.Reader = in
+	name := src
+	if strings.HasSuffix(name, ageExt) {
+		if len(identities) == 0 {
+			return ErrIdentityRequired
+		}
+		r, err = age.Decrypt(r, identities...)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt snapshot: %w", err)
+		}
+		name = strings.TrimSuffix(name, ageExt)
+	}
+
+	if strings.HasSuffix(name, gzipExt) {
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return fmt.Errorf("failed to decompress snapshot: %w", err)
+		}
+		defer zr.Close()
+		r = zr
+	}
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", dst, err)
+	}
+	defer func() {
+		if closeErr := out.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close %s: %w", dst, closeErr)
+		}
+	}()
+
+	if _, err := io.Copy(out, r); err != nil {
+		return fmt.Errorf("failed to copy snapshot: %w", err)
+	}
+	return nil
+}
+
+// prune removes the oldest snapshots in dir, so that at most keep of them remain.
+// Snapshot names sort by creation time.
+func prune(dir string, keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+
+	snapshots, err := List(dir)
+	if err != nil {
+		return err
+	}
+
+	for len(snapshots) > keep {
+		if err := os.Remove(filepath.Join(dir, snapshots[0])); err != nil {
+			return fmt.Errorf("failed to remove old snapshot %s: %w", snapshots[0], err)
+		}
+		snapshots = snapshots[1:]
+	}
+	return nil
+}
+
+// List returns names of snapshots in dir, oldest first.
+func List(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup directory %s: %w", dir, err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.Type().IsRegular() && isSnapshotName(name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+func isSnapshotName(name string) bool {
+	if !strings.HasPrefix(name, snapshotPrefix) {
+		return false
+	}
+	name = strings.TrimSuffix(name, ageExt)
+	name = strings.TrimSuffix(name, gzipExt)
+	return strings.HasSuffix(name, snapshotExt)
+}
@@ -0,0 +1,260 @@
+package backup_test
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"filippo.io/age"
+	"github.com/kkstas/tr-backend/internal/backup"
+	"github.com/kkstas/tr-backend/internal/database"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestCreate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("writes snapshot of the database", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		user := testutils.CreateTestUser(t, db)
+		dir := t.TempDir()
+
+		snapshot, err := backup.Create(ctx, dbPathOf(t, db), backup.Options{Dir: dir, Gzip: false, Keep: 0})
+		testutils.AssertNoError(t, err)
+		testutils.AssertValidDate(t, snapshot.CreatedAt)
+		if !strings.HasSuffix(snapshot.Name, ".db") {
+			t.Errorf("expected snapshot name to end with .db, got %s", snapshot.Name)
+		}
+
+		restored := openSnapshot(t, ctx, filepath.Join(dir, snapshot.Name))
+		_, err = testutils.NewTestUserService(restored).FindOneByID(ctx, user.ID)
+		testutils.AssertNoError(t, err)
+	})
+
+	t.Run("compresses snapshot with gzip", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		dir := t.TempDir()
+
+		snapshot, err := backup.Create(ctx, dbPathOf(t, db), backup.Options{Dir: dir, Gzip: true, Keep: 0})
+		testutils.AssertNoError(t, err)
+		if !strings.HasSuffix(snapshot.Name, ".db.gz") {
+			t.Errorf("expected snapshot name to end with .db.gz, got %s", snapshot.Name)
+		}
+
+		f, err := os.Open(filepath.Join(dir, snapshot.Name))
+		testutils.AssertNoError(t, err)
+		defer f.Close()
+		zr, err := gzip.NewReader(f)
+		testutils.AssertNoError(t, err)
+		header := make([]byte, 16)
+		_, err = io.ReadFull(zr, header)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, string(header), "SQLite format 3\x00")
+	})
+
+	t.Run("encrypts snapshot with age", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		dir := t.TempDir()
+		identity, err := age.GenerateX25519Identity()
+		testutils.AssertNoError(t, err)
+
+		options := backup.Options{Dir: dir, Gzip: true, Keep: 0, Recipients: []age.Recipient{identity.Recipient()}}
+		snapshot, err := backup.Create(ctx, dbPathOf(t, db), options)
+		testutils.AssertNoError(t, err)
+		if !strings.HasSuffix(snapshot.Name, ".db.gz.age") {
+			t.Errorf("expected snapshot name to end with .db.gz.age, got %s", snapshot.Name)
+		}
+
+		f, err := os.Open(filepath.Join(dir, snapshot.Name))
+		testutils.AssertNoError(t, err)
+		defer f.Close()
+		r, err := age.Decrypt(f, identity)
+		testutils.AssertNoError(t, err)
+		zr, err := gzip.NewReader(r)
+		testutils.AssertNoError(t, err)
+		header := make([]byte, 16)
+		_, err = io.ReadFull(zr, header)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, string(header), "SQLite format 3\x00")
+
+		names, err := backup.List(dir)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, strings.Join(names, ","), snapshot.Name)
+	})
+
+	t.Run("keeps only the most recent snapshots", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		dir := t.TempDir()
+		options := backup.Options{Dir: dir, Gzip: false, Keep: 2}
+
+		var names []string
+		for range 4 {
+			snapshot, err := backup.Create(ctx, dbPathOf(t, db), options)
+			testutils.AssertNoError(t, err)
+			names = append(names, snapshot.Name)
+			time.Sleep(2 * time.Millisecond)
+		}
+
+		remaining, err := backup.List(dir)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, strings.Join(remaining, ","), strings.Join(names[2:], ","))
+	})
+}
+
+func TestRestore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("replaces database with snapshot and keeps previous one", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		user := testutils.CreateTestUser(t, db)
+		dir := t.TempDir()
+		snapshot, err := backup.Create(ctx, dbPathOf(t, db), backup.Options{Dir: dir, Gzip: true, Keep: 0})
+		testutils.AssertNoError(t, err)
+
+		dbPath := filepath.Join(t.TempDir(), "database.db")
+		testutils.AssertNoError(t, os.WriteFile(dbPath, []byte("previous"), 0o600))
+
+		previousPath, err := backup.Restore(ctx, filepath.Join(dir, snapshot.Name), dbPath)
+		testutils.AssertNoError(t, err)
+
+		previous, err := os.ReadFile(previousPath)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, string(previous), "previous")
+
+		restored := openSnapshot(t, ctx, dbPath)
+		_, err = testutils.NewTestUserService(restored).FindOneByID(ctx, user.ID)
+		testutils.AssertNoError(t, err)
+	})
+
+	t.Run("restores encrypted snapshot with matching identity", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		user := testutils.CreateTestUser(t, db)
+		dir := t.TempDir()
+		identity, err := age.GenerateX25519Identity()
+		testutils.AssertNoError(t, err)
+		other, err := age.GenerateX25519Identity()
+		testutils.AssertNoError(t, err)
+
+		options := backup.Options{Dir: dir, Gzip: false, Keep: 0, Recipients: []age.Recipient{identity.Recipient()}}
+		snapshot, err := backup.Create(ctx, dbPathOf(t, db), options)
+		testutils.AssertNoError(t, err)
+		snapshotPath := filepath.Join(dir, snapshot.Name)
+		dbPath := filepath.Join(t.TempDir(), "database.db")
+
+		_, err = backup.Restore(ctx, snapshotPath, dbPath)
+		if !errors.Is(err, backup.ErrIdentityRequired) {
+			t.Fatalf("expected error %q, got %v", backup.ErrIdentityRequired, err)
+		}
+
+		_, err = backup.Restore(ctx, snapshotPath, dbPath, other)
+		if err == nil {
+			t.Fatal("expected error when restoring with wrong identity")
+		}
+
+		previousPath, err := backup.Restore(ctx, snapshotPath, dbPath, identity)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, previousPath, "")
+
+		restored := openSnapshot(t, ctx, dbPath)
+		_, err = testutils.NewTestUserService(restored).FindOneByID(ctx, user.ID)
+		testutils.AssertNoError(t, err)
+	})
+
+	t.Run("keeps database of every restore", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		dir := t.TempDir()
+		snapshot, err := backup.Create(ctx, dbPathOf(t, db), backup.Options{Dir: dir, Gzip: false, Keep: 0})
+		testutils.AssertNoError(t, err)
+		snapshotPath := filepath.Join(dir, snapshot.Name)
+
+		dbPath := filepath.Join(t.TempDir(), "database.db")
+		testutils.AssertNoError(t, os.WriteFile(dbPath, []byte("first"), 0o600))
+		first, err := backup.Restore(ctx, snapshotPath, dbPath)
+		testutils.AssertNoError(t, err)
+
+		time.Sleep(2 * time.Millisecond)
+		second, err := backup.Restore(ctx, snapshotPath, dbPath)
+		testutils.AssertNoError(t, err)
+
+		if first == second {
+			t.Fatalf("expected restores to keep previous databases under different names, got %s twice", first)
+		}
+		previous, err := os.ReadFile(first)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, string(previous), "first")
+		if _, err := os.Stat(second); err != nil {
+			t.Errorf("expected database replaced by second restore to be kept: %v", err)
+		}
+	})
+
+	t.Run("does not replace database if snapshot fails integrity check", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		for range 50 {
+			testutils.CreateTestUser(t, db)
+		}
+		dir := t.TempDir()
+		snapshot, err := backup.Create(ctx, dbPathOf(t, db), backup.Options{Dir: dir, Gzip: false, Keep: 0})
+		testutils.AssertNoError(t, err)
+
+		snapshotPath := filepath.Join(dir, snapshot.Name)
+		f, err := os.OpenFile(snapshotPath, os.O_WRONLY, 0)
+		testutils.AssertNoError(t, err)
+		_, err = f.WriteAt([]byte(strings.Repeat("\xff", 1024)), 4096)
+		testutils.AssertNoError(t, err)
+		testutils.AssertNoError(t, f.Close())
+
+		dbPath := filepath.Join(t.TempDir(), "database.db")
+		testutils.AssertNoError(t, os.WriteFile(dbPath, []byte("previous"), 0o600))
+
+		_, err = backup.Restore(ctx, snapshotPath, dbPath)
+		if !errors.Is(err, backup.ErrIntegrityCheckFailed) {
+			t.Fatalf("expected error %q, got %v", backup.ErrIntegrityCheckFailed, err)
+		}
+
+		current, err := os.ReadFile(dbPath)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, string(current), "previous")
+
+		entries, err := os.ReadDir(filepath.Dir(dbPath))
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(entries), 1)
+	})
+}
+
+func openSnapshot(t testing.TB, ctx context.Context, path string) *database.DB { // nolint: revive
+	t.Helper()
+	db, err := database.OpenDB(ctx, path)
+	testutils.AssertNoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func dbPathOf(t testing.TB, db *database.DB) string {
+	t.Helper()
+	var path string
+	err := db.QueryRowContext(t.Context(), `SELECT file FROM pragma_database_list WHERE name = 'main'`).Scan(&path)
+	testutils.AssertNoError(t, err)
+	return path
+}
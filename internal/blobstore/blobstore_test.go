@@ -0,0 +1,147 @@
+package blobstore_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/blobstore"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestLocalStore(t *testing.T) {
+	t.Parallel()
+
+	store, err := blobstore.NewLocalStore(t.TempDir())
+	testutils.AssertNoError(t, err)
+
+	testBlobStore(t, store)
+
+	t.Run("rejects keys escaping the directory", func(t *testing.T) {
+		t.Parallel()
+		err := store.Put(t.Context(), "../outside", strings.NewReader("x"), 1, "text/plain")
+		if err == nil {
+			t.Error("expected an error but didn't get one")
+		}
+	})
+}
+
+func TestS3Store(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(newFakeS3(t, "receipts-bucket", "access-key"))
+	t.Cleanup(server.Close)
+
+	store, err := blobstore.NewS3Store(blobstore.S3Config{
+		Endpoint:        server.URL,
+		Region:          "eu-central-1",
+		Bucket:          "receipts-bucket",
+		AccessKeyID:     "access-key",
+		SecretAccessKey: "secret-key",
+	}, server.Client())
+	testutils.AssertNoError(t, err)
+
+	testBlobStore(t, store)
+}
+
+func testBlobStore(t *testing.T, store blobstore.BlobStore) {
+	t.Helper()
+
+	t.Run("puts, gets and deletes a blob", func(t *testing.T) {
+		t.Parallel()
+		ctx := t.Context()
+		key := "vault/" + testutils.RandomString(8)
+		content := []byte("%PDF-1.4 receipt")
+
+		err := store.Put(ctx, key, bytes.NewReader(content), int64(len(content)), "application/pdf")
+		testutils.AssertNoError(t, err)
+
+		rc, err := store.Get(ctx, key)
+		testutils.AssertNoError(t, err)
+		got, err := io.ReadAll(rc)
+		rc.Close()
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, string(got), string(content))
+
+		err = store.Delete(ctx, key)
+		testutils.AssertNoError(t, err)
+
+		_, err = store.Get(ctx, key)
+		want := blobstore.ErrNotFound
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+
+	t.Run("returns error when getting blob that does not exist", func(t *testing.T) {
+		t.Parallel()
+		_, err := store.Get(t.Context(), "missing-"+testutils.RandomString(8))
+		want := blobstore.ErrNotFound
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+
+	t.Run("deleting blob that does not exist is not an error", func(t *testing.T) {
+		t.Parallel()
+		err := store.Delete(t.Context(), "missing-"+testutils.RandomString(8))
+		testutils.AssertNoError(t, err)
+	})
+}
+
+// newFakeS3 returns a minimal in-memory stand-in for S3 path-style object endpoints.
+func newFakeS3(t *testing.T, bucket, accessKeyID string) http.Handler {
+	t.Helper()
+
+	var mu sync.Mutex
+	objects := map[string][]byte{}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential="+accessKeyID+"/") ||
+			!strings.Contains(auth, "SignedHeaders=") || !strings.Contains(auth, "Signature=") ||
+			r.Header.Get("X-Amz-Date") == "" || r.Header.Get("X-Amz-Content-Sha256") == "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		key, ok := strings.CutPrefix(r.URL.Path, "/"+bucket+"/")
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.Method {
+		case http.MethodPut:
+			body, err := io.ReadAll(r.Body)
+			if err != nil || r.ContentLength != int64(len(body)) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			objects[key] = body
+			w.WriteHeader(http.StatusOK)
+		case http.MethodGet:
+			body, found := objects[key]
+			if !found {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, "<Error><Code>NoSuchKey</Code></Error>")
+				return
+			}
+			w.Write(body) // nolint: errcheck
+		case http.MethodDelete:
+			delete(objects, key)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+}
@@ -0,0 +1,116 @@
+package categorytemplates
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/kkstas/tr-backend/internal/models"
+)
+
+const DefaultLocale = "en"
+
+var ErrTemplateNotFound = errors.New("category template not found")
+
+type Template struct {
+	Name       string                    `json:"name"`
+	Locale     string                    `json:"locale"`
+	Categories []models.CategoryTemplate `json:"categories"`
+}
+
+func category(name string, subcategories ...string) models.CategoryTemplate {
+	c := models.CategoryTemplate{Name: name, Subcategories: []models.CategoryTemplate{}}
+	for _, subcategory := range subcategories {
+		c.Subcategories = append(c.Subcategories, models.CategoryTemplate{Name: subcategory, Subcategories: []models.CategoryTemplate{}})
+	}
+	return c
+}
+
+var templates = map[string]map[string][]models.CategoryTemplate{
+	"household": {
+		"en": {
+			category("Housing", "Rent", "Utilities", "Repairs"),
+			category("Food", "Groceries", "Restaurants"),
+			category("Transport", "Fuel", "Public transport"),
+			category("Health"),
+			category("Entertainment"),
+			category("Other"),
+		},
+		"pl": {
+			category("Mieszkanie", "Czynsz", "Media", "Naprawy"),
+			category("Jedzenie", "Zakupy spożywcze", "Restauracje"),
+			category("Transport", "Paliwo", "Komunikacja miejska"),
+			category("Zdrowie"),
+			category("Rozrywka"),
+			category("Inne"),
+		},
+	},
+	"travel": {
+		"en": {
+			category("Transport", "Flights", "Local transport"),
+			category("Accommodation"),
+			category("Food"),
+			category("Sightseeing"),
+			category("Souvenirs"),
+		},
+		"pl": {
+			category("Transport", "Loty", "Transport lokalny"),
+			category("Noclegi"),
+			category("Jedzenie"),
+			category("Zwiedzanie"),
+			category("Pamiątki"),
+		},
+	},
+	"business": {
+		"en": {
+			category("Office", "Supplies", "Software"),
+			category("Business travel"),
+			category("Marketing"),
+			category("Salaries"),
+			category("Taxes"),
+		},
+		"pl": {
+			category("Biuro", "Materiały biurowe", "Oprogramowanie"),
+			category("Podróże służbowe"),
+			category("Marketing"),
+			category("Wynagrodzenia"),
+			category("Podatki"),
+		},
+	},
+}
+
+// Names returns names of all available templates in alphabetical order.
+func Names() []string {
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Find returns template with given name translated to locale. Locale may be a language tag
+// or an Accept-Language header value; unsupported locales fall back to DefaultLocale.
+func Find(name, locale string) (*Template, error) {
+	translations, ok := templates[name]
+	if !ok {
+		return nil, ErrTemplateNotFound
+	}
+
+	language := parseLanguage(locale)
+	categories, ok := translations[language]
+	if !ok {
+		language = DefaultLocale
+		categories = translations[language]
+	}
+
+	return &Template{Name: name, Locale: language, Categories: categories}, nil
+}
+
+func parseLanguage(locale string) string {
+	tag, _, _ := strings.Cut(locale, ",")
+	tag, _, _ = strings.Cut(tag, ";")
+	tag, _, _ = strings.Cut(strings.TrimSpace(tag), "-")
+	tag, _, _ = strings.Cut(tag, "_")
+	return strings.ToLower(tag)
+}
@@ -0,0 +1,73 @@
+package categorytemplates_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/categorytemplates"
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		locale     string
+		wantLocale string
+	}{
+		{locale: "pl", wantLocale: "pl"},
+		{locale: "pl-PL,pl;q=0.9,en;q=0.8", wantLocale: "pl"},
+		{locale: "EN_us", wantLocale: "en"},
+		{locale: "de-DE", wantLocale: categorytemplates.DefaultLocale},
+		{locale: "", wantLocale: categorytemplates.DefaultLocale},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.locale, func(t *testing.T) {
+			t.Parallel()
+			template, err := categorytemplates.Find("household", tc.locale)
+			testutils.AssertNoError(t, err)
+			testutils.AssertEqual(t, template.Locale, tc.wantLocale)
+			testutils.AssertNotEmpty(t, template.Categories)
+		})
+	}
+
+	t.Run("every template is available in every locale with unique category names", func(t *testing.T) {
+		t.Parallel()
+		for _, name := range categorytemplates.Names() {
+			for _, locale := range []string{"en", "pl"} {
+				template, err := categorytemplates.Find(name, locale)
+				testutils.AssertNoError(t, err)
+				testutils.AssertEqual(t, template.Locale, locale)
+
+				seen := map[string]bool{}
+				for _, category := range template.Categories {
+					for _, n := range append([]string{category.Name}, subcategoryNames(category.Subcategories)...) {
+						if seen[n] {
+							t.Errorf("duplicated category name %q in template %s/%s", n, name, locale)
+						}
+						seen[n] = true
+					}
+				}
+			}
+		}
+	})
+
+	t.Run("returns error for unknown template", func(t *testing.T) {
+		t.Parallel()
+		_, err := categorytemplates.Find("unknown", "en")
+		want := categorytemplates.ErrTemplateNotFound
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+}
+
+func subcategoryNames(subcategories []models.CategoryTemplate) []string {
+	names := []string{}
+	for _, subcategory := range subcategories {
+		names = append(names, subcategory.Name)
+	}
+	return names
+}
@@ -1,6 +1,20 @@
 package config
 
+import (
+	"time"
+
+	"filippo.io/age"
+)
+
 type Config struct {
-	EnableRegister bool
-	JWTSecretKey   []byte
+	EnableRegister   bool
+	EnableMetrics    bool
+	JWTSecretKey     []byte
+	TrashRetention   time.Duration
+	ReceiptMaxSize   int64
+	AdminToken       string
+	BackupDir        string
+	BackupGzip       bool
+	BackupKeep       int
+	BackupRecipients []age.Recipient
 }
@@ -3,21 +3,92 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"maps"
+	"net/url"
+	"runtime"
+	"time"
 )
 
-func OpenDB(ctx context.Context, dbname string) (*sql.DB, error) {
-	db, err := sql.Open("sqlite", dbname+"?_pragma=foreign_keys(1)&_time_format=sqlite")
+const (
+	busyTimeout = 5 * time.Second
+	maxReaders  = 8
+)
+
+// DB splits access to the database file between a single writer connection and a pool of
+// read-only connections. SQLite allows one writer at a time, so funnelling all writes
+// through one connection serializes them in the connection pool instead of failing with
+// SQLITE_BUSY, while WAL mode lets readers proceed concurrently with the writer.
+//
+// The embedded *sql.DB is the writer, so code that doesn't pick a pool explicitly is
+// always correct. Queries that only read outside of a transaction should use Read.
+// Repositories go through Writer and Reader instead, which use the transaction started
+// by WithTx when there is one.
+type DB struct {
+	*sql.DB
+	Read *sql.DB
+}
+
+func OpenDB(ctx context.Context, dbname string) (*DB, error) {
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", busyTimeout.Milliseconds()))
+	// NORMAL is durable in WAL mode except for the last transactions before a power loss.
+	params.Add("_pragma", "synchronous(NORMAL)")
+	params.Set("_time_format", "sqlite")
+
+	// Transactions take the write lock when they begin rather than on their first write,
+	// so that other processes, e.g. backups, wait for busy_timeout instead of failing on
+	// a lock upgrade.
+	writerParams := maps.Clone(params)
+	writerParams.Set("_txlock", "immediate")
+
+	writer, err := sql.Open(driverName, dbname+"?"+writerParams.Encode())
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
+	writer.SetMaxOpenConns(1)
 
-	err = initDBTables(ctx, db)
+	err = initDBTables(ctx, writer)
 	if err != nil {
+		writer.Close()
 		return nil, fmt.Errorf("failed to init db tables: %w", err)
 	}
 
-	return db, nil
+	err = runMigrations(ctx, writer)
+	if err != nil {
+		writer.Close()
+		return nil, fmt.Errorf("failed to run db migrations: %w", err)
+	}
+
+	readerParams := maps.Clone(params)
+	readerParams.Add("_pragma", "query_only(1)")
+
+	reader, err := sql.Open(driverName, dbname+"?"+readerParams.Encode())
+	if err != nil {
+		writer.Close()
+		return nil, fmt.Errorf("failed to open read-only database: %w", err)
+	}
+	reader.SetMaxOpenConns(max(maxReaders, runtime.NumCPU()))
+
+	return &DB{DB: writer, Read: reader}, nil
+}
+
+func (db *DB) Close() error {
+	return errors.Join(db.Read.Close(), db.DB.Close())
+}
+
+// PingContext checks both the writer and the read-only pool.
+func (db *DB) PingContext(ctx context.Context) error {
+	if err := db.DB.PingContext(ctx); err != nil {
+		return fmt.Errorf("writer: %w", err)
+	}
+	if err := db.Read.PingContext(ctx); err != nil {
+		return fmt.Errorf("reader: %w", err)
+	}
+	return nil
 }
 
 func initDBTables(ctx context.Context, db *sql.DB) error {
@@ -0,0 +1,88 @@
+package database_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestOpenDB(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := testutils.OpenTestDB(t, ctx)
+
+	var journalMode string
+	testutils.AssertNoError(t, db.Read.QueryRowContext(ctx, `PRAGMA journal_mode`).Scan(&journalMode))
+	testutils.AssertEqual(t, journalMode, "wal")
+
+	var busyTimeout int
+	testutils.AssertNoError(t, db.QueryRowContext(ctx, `PRAGMA busy_timeout`).Scan(&busyTimeout))
+	testutils.AssertEqual(t, busyTimeout, 5000)
+
+	var synchronous int
+	testutils.AssertNoError(t, db.Read.QueryRowContext(ctx, `PRAGMA synchronous`).Scan(&synchronous))
+	testutils.AssertEqual(t, synchronous, 1) // NORMAL
+
+	testutils.AssertEqual(t, db.Stats().MaxOpenConnections, 1)
+
+	if _, err := db.Read.ExecContext(ctx, `DELETE FROM users`); err == nil {
+		t.Error("expected write through read-only pool to fail")
+	}
+}
+
+func TestOpenDB_ParallelExpenseInserts(t *testing.T) {
+	t.Parallel()
+
+	const (
+		writers           = 16
+		expensesPerWriter = 25
+	)
+
+	ctx := context.Background()
+	db := testutils.OpenTestDB(t, ctx)
+	expenseService := testutils.NewTestExpenseService(db)
+	_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+	category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+	paymentMethod := testutils.CreateTestPaymentMethod(t, db, user.ID, vault.ID)
+	tag := testutils.CreateTestTag(t, db, user.ID, vault.ID)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, writers*expensesPerWriter*2)
+	for w := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range expensesPerWriter {
+				// every insert runs in a transaction writing the expense and its tags
+				errs <- expenseService.CreateOne(ctx, user.ID, models.Expense{ // nolint: exhaustruct
+					Name:            fmt.Sprintf("expense %d-%d", w, i),
+					Date:            "2025-03-01",
+					CategoryID:      category.ID,
+					Amount:          1,
+					PaymentMethodID: paymentMethod.ID,
+					TagIDs:          []string{tag.ID},
+					VaultID:         vault.ID,
+				})
+
+				_, err := expenseService.FindAll(ctx, user.ID, vault.ID)
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("expected no errors under parallel inserts, got %v", err)
+		}
+	}
+
+	expenses, err := expenseService.FindAll(ctx, user.ID, vault.ID)
+	testutils.AssertNoError(t, err)
+	testutils.AssertEqual(t, len(expenses), writers*expensesPerWriter)
+}
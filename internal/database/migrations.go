@@ -0,0 +1,261 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// migrations are applied in order on top of the tables created in initDBTables.
+// Applied migrations are tracked with PRAGMA user_version, so entries must never
+// be reordered or removed - only appended.
+var migrations = []string{
+	`ALTER TABLE vaults ADD COLUMN deleted_at DATETIME NULL`,
+	`ALTER TABLE expenses ADD COLUMN deleted_at DATETIME NULL`,
+	`ALTER TABLE vaults ADD COLUMN description TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE vaults ADD COLUMN icon TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE vaults ADD COLUMN color TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE vaults ADD COLUMN base_currency TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE vaults ADD COLUMN default_payment_method TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE expense_categories ADD COLUMN parent_id TEXT NULL REFERENCES expense_categories(id) ON DELETE SET NULL`,
+	`CREATE TABLE payment_methods (
+		id         TEXT PRIMARY KEY,
+		name       TEXT NOT NULL,
+		status     TEXT NOT NULL DEFAULT 'active',
+		vault_id   TEXT NOT NULL,
+		created_by TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (vault_id, name),
+		FOREIGN KEY (vault_id) REFERENCES vaults(id) ON DELETE CASCADE,
+		FOREIGN KEY (created_by) REFERENCES users(id)
+	)`,
+	`ALTER TABLE expenses ADD COLUMN payment_method_id TEXT NULL REFERENCES payment_methods(id)`,
+	// Turn free-text payment methods of existing expenses into payment method records.
+	`INSERT INTO payment_methods(id, name, vault_id, created_by)
+		SELECT lower(hex(randomblob(16))), payment_method, vault_id, MIN(created_by)
+		FROM expenses
+		WHERE payment_method != ''
+		GROUP BY vault_id, payment_method`,
+	`UPDATE expenses SET payment_method_id = (
+		SELECT pm.id FROM payment_methods pm
+		WHERE pm.vault_id = expenses.vault_id AND pm.name = expenses.payment_method
+	)`,
+	`CREATE TABLE accounts (
+		id              TEXT PRIMARY KEY,
+		name            TEXT NOT NULL,
+		type            TEXT NOT NULL,
+		opening_balance REAL NOT NULL DEFAULT 0,
+		vault_id        TEXT NOT NULL,
+		created_by      TEXT NOT NULL,
+		created_at      DATETIME DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (vault_id, name),
+		FOREIGN KEY (vault_id) REFERENCES vaults(id) ON DELETE CASCADE,
+		FOREIGN KEY (created_by) REFERENCES users(id)
+	)`,
+	`ALTER TABLE expenses ADD COLUMN account_id TEXT NULL REFERENCES accounts(id)`,
+	`CREATE TABLE transfers (
+		id              TEXT PRIMARY KEY,
+		from_account_id TEXT NOT NULL,
+		to_account_id   TEXT NOT NULL,
+		amount          REAL NOT NULL,
+		date            TEXT NOT NULL,
+		description     TEXT NOT NULL DEFAULT '',
+		vault_id        TEXT NOT NULL,
+		created_by      TEXT NOT NULL,
+		created_at      DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (from_account_id) REFERENCES accounts(id),
+		FOREIGN KEY (to_account_id) REFERENCES accounts(id),
+		FOREIGN KEY (vault_id) REFERENCES vaults(id) ON DELETE CASCADE,
+		FOREIGN KEY (created_by) REFERENCES users(id)
+	)`,
+	`CREATE TABLE incomes (
+		id          TEXT PRIMARY KEY,
+		source      TEXT NOT NULL,
+		date        TEXT NOT NULL,
+		amount      REAL NOT NULL,
+		account_id  TEXT NULL,
+		vault_id    TEXT NOT NULL,
+		created_by  TEXT NOT NULL,
+		created_at  DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (account_id) REFERENCES accounts(id),
+		FOREIGN KEY (vault_id) REFERENCES vaults(id) ON DELETE CASCADE,
+		FOREIGN KEY (created_by) REFERENCES users(id)
+	)`,
+	`ALTER TABLE expenses ADD COLUMN paid_by TEXT NULL REFERENCES users(id)`,
+	`CREATE TABLE expense_splits (
+		expense_id  TEXT NOT NULL,
+		user_id     TEXT NOT NULL,
+		amount      REAL NOT NULL,
+		PRIMARY KEY (expense_id, user_id),
+		FOREIGN KEY (expense_id) REFERENCES expenses(id) ON DELETE CASCADE,
+		FOREIGN KEY (user_id) REFERENCES users(id)
+	)`,
+	`CREATE TABLE settlements (
+		id            TEXT PRIMARY KEY,
+		from_user_id  TEXT NOT NULL,
+		to_user_id    TEXT NOT NULL,
+		amount        REAL NOT NULL,
+		date          TEXT NOT NULL,
+		vault_id      TEXT NOT NULL,
+		created_by    TEXT NOT NULL,
+		created_at    DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (from_user_id) REFERENCES users(id),
+		FOREIGN KEY (to_user_id) REFERENCES users(id),
+		FOREIGN KEY (vault_id) REFERENCES vaults(id) ON DELETE CASCADE,
+		FOREIGN KEY (created_by) REFERENCES users(id)
+	)`,
+	`CREATE TABLE tags (
+		id          TEXT PRIMARY KEY,
+		name        TEXT NOT NULL,
+		vault_id    TEXT NOT NULL,
+		created_by  TEXT NOT NULL,
+		created_at  DATETIME DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (vault_id, name),
+		FOREIGN KEY (vault_id) REFERENCES vaults(id) ON DELETE CASCADE,
+		FOREIGN KEY (created_by) REFERENCES users(id)
+	)`,
+	`CREATE TABLE expense_tags (
+		expense_id  TEXT NOT NULL,
+		tag_id      TEXT NOT NULL,
+		PRIMARY KEY (expense_id, tag_id),
+		FOREIGN KEY (expense_id) REFERENCES expenses(id) ON DELETE CASCADE,
+		FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+	)`,
+	`CREATE TABLE receipts (
+		id            TEXT PRIMARY KEY,
+		expense_id    TEXT NOT NULL,
+		file_name     TEXT NOT NULL,
+		content_type  TEXT NOT NULL,
+		size          INTEGER NOT NULL,
+		checksum      TEXT NOT NULL,
+		storage_key   TEXT NOT NULL,
+		created_by    TEXT NOT NULL,
+		created_at    DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (expense_id) REFERENCES expenses(id) ON DELETE CASCADE,
+		FOREIGN KEY (created_by) REFERENCES users(id)
+	)`,
+	`ALTER TABLE expenses ADD COLUMN notes TEXT NOT NULL DEFAULT ''`,
+	// Full-text index of expenses, kept in sync by the triggers below. Rows share rowid with expenses.
+	`CREATE VIRTUAL TABLE expenses_fts USING fts5(
+		name,
+		notes,
+		category_name,
+		tokenize = 'unicode61 remove_diacritics 2'
+	)`,
+	`INSERT INTO expenses_fts(rowid, name, notes, category_name)
+		SELECT e.rowid, e.name, e.notes, COALESCE(c.name, '')
+		FROM expenses e
+		LEFT JOIN expense_categories c ON c.id = e.category_id`,
+	`CREATE TRIGGER expenses_fts_insert AFTER INSERT ON expenses BEGIN
+		INSERT INTO expenses_fts(rowid, name, notes, category_name)
+		VALUES (new.rowid, new.name, new.notes, COALESCE((SELECT name FROM expense_categories WHERE id = new.category_id), ''));
+	END`,
+	`CREATE TRIGGER expenses_fts_update AFTER UPDATE OF name, notes, category_id ON expenses BEGIN
+		UPDATE expenses_fts
+		SET name = new.name,
+			notes = new.notes,
+			category_name = COALESCE((SELECT name FROM expense_categories WHERE id = new.category_id), '')
+		WHERE rowid = new.rowid;
+	END`,
+	`CREATE TRIGGER expenses_fts_delete AFTER DELETE ON expenses BEGIN
+		DELETE FROM expenses_fts WHERE rowid = old.rowid;
+	END`,
+	`CREATE TRIGGER expense_categories_fts_update AFTER UPDATE OF name ON expense_categories BEGIN
+		UPDATE expenses_fts
+		SET category_name = new.name
+		WHERE rowid IN (SELECT rowid FROM expenses WHERE category_id = new.id);
+	END`,
+	// audit_events intentionally has no foreign key to vaults, so that history outlives purged vaults.
+	`CREATE TABLE audit_events (
+		id           INTEGER PRIMARY KEY AUTOINCREMENT,
+		vault_id     TEXT NOT NULL,
+		actor_id     TEXT NOT NULL,
+		action       TEXT NOT NULL,
+		entity_type  TEXT NOT NULL,
+		entity_id    TEXT NOT NULL,
+		before       TEXT NULL,
+		after        TEXT NULL,
+		created_at   DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (actor_id) REFERENCES users(id)
+	)`,
+	`CREATE INDEX audit_events_vault_id ON audit_events(vault_id, id)`,
+	`CREATE TRIGGER audit_events_no_update BEFORE UPDATE ON audit_events BEGIN
+		SELECT RAISE(ABORT, 'audit_events is append-only');
+	END`,
+	`CREATE TRIGGER audit_events_no_delete BEFORE DELETE ON audit_events BEGIN
+		SELECT RAISE(ABORT, 'audit_events is append-only');
+	END`,
+	// Replace the free-text default payment method of vaults with a reference to one of their
+	// payment methods, keeping those whose name matches an existing payment method.
+	`ALTER TABLE vaults ADD COLUMN default_payment_method_id TEXT NULL REFERENCES payment_methods(id) ON DELETE SET NULL`,
+	`UPDATE vaults SET default_payment_method_id = (
+		SELECT pm.id FROM payment_methods pm
+		WHERE pm.vault_id = vaults.id AND pm.name = vaults.default_payment_method
+	)
+	WHERE default_payment_method != ''`,
+	`ALTER TABLE vaults DROP COLUMN default_payment_method`,
+	// Expenses reference their payment method by payment_method_id since payment methods became records.
+	`ALTER TABLE expenses DROP COLUMN payment_method`,
+	// Keep how splits were computed, so that an amount change can compute them again the same way.
+	// Earlier splits are kept as exact amounts.
+	`ALTER TABLE expenses ADD COLUMN split_method TEXT NULL`,
+	`ALTER TABLE expense_splits ADD COLUMN share REAL NOT NULL DEFAULT 0`,
+	`UPDATE expenses SET split_method = 'exact' WHERE id IN (SELECT expense_id FROM expense_splits)`,
+	`UPDATE expense_splits SET share = amount`,
+}
+
+func runMigrations(ctx context.Context, db *sql.DB) error {
+	version, err := schemaVersion(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	for i := version; i < len(migrations); i++ {
+		if err := applyMigration(ctx, db, i); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func applyMigration(ctx context.Context, db *sql.DB, idx int) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %d transaction: %w", idx+1, err)
+	}
+
+	defer tx.Rollback() // nolint: errcheck
+
+	if _, err := tx.ExecContext(ctx, migrations[idx]); err != nil {
+		return fmt.Errorf("failed to apply migration %d: %w", idx+1, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", idx+1)); err != nil {
+		return fmt.Errorf("failed to set schema version to %d: %w", idx+1, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %d: %w", idx+1, err)
+	}
+
+	return nil
+}
+
+func schemaVersion(ctx context.Context, db *sql.DB) (int, error) {
+	var version int
+	if err := db.QueryRowContext(ctx, `PRAGMA user_version`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// PendingMigrations returns the number of migrations not applied to db yet, e.g. because
+// the database file was replaced with an older one after the application started.
+func PendingMigrations(ctx context.Context, db *sql.DB) (int, error) {
+	version, err := schemaVersion(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+	return len(migrations) - version, nil
+}
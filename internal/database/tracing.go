@@ -0,0 +1,123 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"modernc.org/sqlite"
+
+	"github.com/kkstas/tr-backend/internal/tracing"
+)
+
+const driverName = "sqlite-traced"
+
+func init() {
+	sql.Register(driverName, &tracedDriver{Driver: &sqlite.Driver{}})
+}
+
+// tracedDriver wraps the sqlite driver to record a span for every query executed with a
+// context carrying a span, so that repositories don't have to start spans themselves.
+type tracedDriver struct {
+	driver.Driver
+}
+
+type sqliteConn interface {
+	driver.Conn
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+	driver.SessionResetter
+	driver.Validator
+}
+
+type sqliteStmt interface {
+	driver.Stmt
+	driver.StmtExecContext
+	driver.StmtQueryContext
+}
+
+func (d *tracedDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	c, ok := conn.(sqliteConn)
+	if !ok {
+		_ = conn.Close()
+		return nil, fmt.Errorf("unexpected sqlite connection type %T", conn)
+	}
+	return &tracedConn{sqliteConn: c}, nil
+}
+
+type tracedConn struct {
+	sqliteConn
+}
+
+func (c *tracedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	ctx, span := startQuerySpan(ctx, "sql.exec", query)
+	defer span.End()
+
+	res, err := c.sqliteConn.ExecContext(ctx, query, args)
+	tracing.RecordError(span, err)
+	return res, err
+}
+
+func (c *tracedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	ctx, span := startQuerySpan(ctx, "sql.query", query)
+	defer span.End()
+
+	rows, err := c.sqliteConn.QueryContext(ctx, query, args)
+	tracing.RecordError(span, err)
+	return rows, err
+}
+
+func (c *tracedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	stmt, err := c.sqliteConn.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := stmt.(sqliteStmt)
+	if !ok {
+		return stmt, nil
+	}
+	return &tracedStmt{sqliteStmt: s, query: query}, nil
+}
+
+type tracedStmt struct {
+	sqliteStmt
+	query string
+}
+
+func (s *tracedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	ctx, span := startQuerySpan(ctx, "sql.exec", s.query)
+	defer span.End()
+
+	res, err := s.sqliteStmt.ExecContext(ctx, args)
+	tracing.RecordError(span, err)
+	return res, err
+}
+
+func (s *tracedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	ctx, span := startQuerySpan(ctx, "sql.query", s.query)
+	defer span.End()
+
+	rows, err := s.sqliteStmt.QueryContext(ctx, args)
+	tracing.RecordError(span, err)
+	return rows, err
+}
+
+func startQuerySpan(ctx context.Context, name, query string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "sqlite"),
+			attribute.String("db.query.text", query),
+		),
+	)
+}
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Querier is implemented by both *sql.DB and *sql.Tx.
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type txKey struct{}
+
+type txValue struct {
+	db *DB
+	tx *sql.Tx
+}
+
+// Tx is a transaction returned by Begin. A Tx that joined a transaction started by WithTx
+// leaves committing and rolling back to WithTx, so an error must be returned up to fn
+// for the whole transaction to roll back.
+type Tx struct {
+	*sql.Tx
+	joined bool
+}
+
+func (tx *Tx) Commit() error {
+	if tx.joined {
+		return nil
+	}
+	return tx.Tx.Commit()
+}
+
+func (tx *Tx) Rollback() error {
+	if tx.joined {
+		return nil
+	}
+	return tx.Tx.Rollback()
+}
+
+// Begin starts a transaction on the writer, or joins the one started by WithTx for ctx.
+func (db *DB) Begin(ctx context.Context) (*Tx, error) {
+	if tx := db.tx(ctx); tx != nil {
+		return &Tx{Tx: tx, joined: true}, nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx, joined: false}, nil
+}
+
+// WithTx runs fn in a transaction, which is committed if fn returns nil and rolled back
+// otherwise. Queries run through Writer, Reader and Begin with the context passed to fn
+// are part of the transaction, so fn can make several repository calls atomic.
+func (db *DB) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // nolint: errcheck
+
+	if err := fn(context.WithValue(ctx, txKey{}, txValue{db: db, tx: tx.Tx})); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Writer returns the transaction started by WithTx for ctx, or the writer otherwise.
+// With a single SQLite writer connection, writing past the transaction from inside
+// WithTx would block forever, so repositories always write through Writer.
+func (db *DB) Writer(ctx context.Context) Querier {
+	if tx := db.tx(ctx); tx != nil {
+		return tx
+	}
+	return db.DB
+}
+
+// Reader returns the transaction started by WithTx for ctx, so that reads see its
+// uncommitted writes, or the read-only pool otherwise.
+func (db *DB) Reader(ctx context.Context) Querier {
+	if tx := db.tx(ctx); tx != nil {
+		return tx
+	}
+	return db.Read
+}
+
+func (db *DB) tx(ctx context.Context) *sql.Tx {
+	if v, ok := ctx.Value(txKey{}).(txValue); ok && v.db == db {
+		return v.tx
+	}
+	return nil
+}
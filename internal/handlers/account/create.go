@@ -0,0 +1,73 @@
+package account
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/utils"
+)
+
+var (
+	minAccountNameLength = 2
+	maxAccountNameLength = 50
+	accountTypes         = []any{
+		string(models.AccountTypeChecking),
+		string(models.AccountTypeSavings),
+		string(models.AccountTypeCash),
+		string(models.AccountTypeCreditCard),
+	}
+)
+
+func CreateOne(
+	logger *slog.Logger,
+	accountService *services.AccountService,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	type reqBody struct {
+		Name           string  `json:"name"`
+		Type           string  `json:"type"`
+		OpeningBalance float64 `json:"openingBalance"`
+		VaultID        string  `json:"vaultID"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		body, err := utils.Decode[reqBody](r)
+		if err != nil {
+			problem.Write(w, r, problem.InvalidBody())
+			return
+		}
+
+		err = validation.ValidateStruct(&body,
+			validation.Field(&body.Name, validation.Required, validation.Length(minAccountNameLength, maxAccountNameLength)),
+			validation.Field(&body.Type, validation.Required, validation.In(accountTypes...)),
+			validation.Field(&body.VaultID, validation.Required),
+		)
+		if err != nil {
+			problem.Write(w, r, problem.Validation(err))
+			return
+		}
+
+		err = accountService.CreateOne(r.Context(), user.ID, models.Account{ // nolint: exhaustruct
+			Name:           body.Name,
+			Type:           models.AccountType(body.Type),
+			OpeningBalance: body.OpeningBalance,
+			VaultID:        body.VaultID,
+		})
+		if err != nil {
+			if errors.Is(err, services.ErrAccountWithThatNameAlreadyExists) {
+				problem.Write(w, r, problem.Field("name", err))
+				return
+			}
+
+			problem.Error(w, r, logger, err, "failed to create account", "vaultID", body.VaultID, "userID", user.ID)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,50 @@
+package account_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestCreateOne(t *testing.T) {
+	t.Parallel()
+
+	t.Run("creates account with opening balance", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+
+		reqBody := map[string]any{"name": "savings", "type": "savings", "openingBalance": 1500.5, "vaultID": vault.ID}
+
+		request := httptest.NewRequest("POST", "/accounts", testutils.ToJSONBuffer(t, reqBody))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNoContent)
+
+		accounts, err := testutils.NewTestAccountService(db).FindAll(t.Context(), user.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(accounts), 1)
+		testutils.AssertEqual(t, accounts[0].Type, models.AccountTypeSavings)
+		testutils.AssertEqual(t, accounts[0].Balance, 1500.5)
+	})
+
+	t.Run("returns 400 for unknown account type", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, _, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+
+		reqBody := map[string]any{"name": "stocks", "type": "brokerage", "vaultID": vault.ID}
+
+		request := httptest.NewRequest("POST", "/accounts", testutils.ToJSONBuffer(t, reqBody))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
+	})
+}
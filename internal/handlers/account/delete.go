@@ -0,0 +1,27 @@
+package account
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+)
+
+func DeleteOneByID(
+	logger *slog.Logger,
+	accountService *services.AccountService,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		accountID := r.PathValue("id")
+
+		err := accountService.DeleteOneByID(r.Context(), user.ID, accountID)
+		if err != nil {
+			problem.Error(w, r, logger, err, "failed to delete account", "accountID", accountID, "userID", user.ID)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
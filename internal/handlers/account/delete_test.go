@@ -0,0 +1,55 @@
+package account_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestDeleteOneByID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns 409 if account is used by expenses", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		account := testutils.CreateTestAccount(t, db, user.ID, vault.ID, 0)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		paymentMethod := testutils.CreateTestPaymentMethod(t, db, user.ID, vault.ID)
+
+		err := testutils.NewTestExpenseService(db).CreateOne(t.Context(), user.ID, models.Expense{ // nolint: exhaustruct
+			Name:            "groceries",
+			Date:            "2025-01-05",
+			CategoryID:      category.ID,
+			Amount:          10,
+			PaymentMethodID: paymentMethod.ID,
+			AccountID:       account.ID,
+			VaultID:         vault.ID,
+		})
+		testutils.AssertNoError(t, err)
+
+		request := httptest.NewRequest("DELETE", "/accounts/"+account.ID, nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusConflict)
+	})
+
+	t.Run("deletes unused account", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		account := testutils.CreateTestAccount(t, db, user.ID, vault.ID, 0)
+
+		request := httptest.NewRequest("DELETE", "/accounts/"+account.ID, nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNoContent)
+	})
+}
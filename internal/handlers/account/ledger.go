@@ -0,0 +1,28 @@
+package account
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/utils"
+)
+
+func Ledger(
+	logger *slog.Logger,
+	accountService *services.AccountService,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		accountID := r.PathValue("id")
+
+		ledger, err := accountService.Ledger(r.Context(), user.ID, accountID)
+		if err != nil {
+			problem.Error(w, r, logger, err, "failed to find account ledger", "accountID", accountID, "userID", user.ID)
+			return
+		}
+
+		utils.Encode(w, http.StatusOK, ledger)
+	}
+}
@@ -0,0 +1,56 @@
+package account_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestLedger(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns ledger with running balance", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		from := testutils.CreateTestAccount(t, db, user.ID, vault.ID, 100)
+		to := testutils.CreateTestAccount(t, db, user.ID, vault.ID, 0)
+
+		reqBody := map[string]any{"fromAccountID": from.ID, "toAccountID": to.ID, "amount": 30, "date": "2025-01-05", "vaultID": vault.ID}
+		request := httptest.NewRequest("POST", "/transfers", testutils.ToJSONBuffer(t, reqBody))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+		testutils.AssertStatus(t, response.Code, http.StatusNoContent)
+
+		request = httptest.NewRequest("GET", "/accounts/"+from.ID+"/ledger", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response = httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+
+		ledger := testutils.DecodeJSON[models.AccountLedger](t, response.Body)
+		testutils.AssertEqual(t, ledger.Account.Balance, 70.0)
+		testutils.AssertEqual(t, len(ledger.Entries), 1)
+		testutils.AssertEqual(t, ledger.Entries[0].Kind, models.LedgerEntryKindTransferOut)
+		testutils.AssertEqual(t, ledger.Entries[0].Balance, 70.0)
+	})
+
+	t.Run("returns 404 if account does not exist", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, _ := testutils.CreateTestUserWithToken(t, db)
+
+		request := httptest.NewRequest("GET", "/accounts/"+uuid.New().String()+"/ledger", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNotFound)
+	})
+}
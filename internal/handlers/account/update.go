@@ -0,0 +1,63 @@
+package account
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/utils"
+)
+
+func UpdateOne(
+	logger *slog.Logger,
+	accountService *services.AccountService,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	type reqBody struct {
+		Name           *string  `json:"name"`
+		Type           *string  `json:"type"`
+		OpeningBalance *float64 `json:"openingBalance"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		accountID := r.PathValue("id")
+
+		body, err := utils.Decode[reqBody](r)
+		if err != nil {
+			problem.Write(w, r, problem.InvalidBody())
+			return
+		}
+
+		err = validation.ValidateStruct(&body,
+			validation.Field(&body.Name, validation.NilOrNotEmpty, validation.Length(minAccountNameLength, maxAccountNameLength)),
+			validation.Field(&body.Type, validation.NilOrNotEmpty, validation.In(accountTypes...)),
+		)
+		if err != nil {
+			problem.Write(w, r, problem.Validation(err))
+			return
+		}
+
+		update := models.AccountUpdate{Name: body.Name, OpeningBalance: body.OpeningBalance} // nolint: exhaustruct
+		if body.Type != nil {
+			accountType := models.AccountType(*body.Type)
+			update.Type = &accountType
+		}
+
+		account, err := accountService.UpdateOne(r.Context(), user.ID, accountID, update)
+		if err != nil {
+			if errors.Is(err, services.ErrAccountWithThatNameAlreadyExists) {
+				problem.Write(w, r, problem.Field("name", err))
+				return
+			}
+
+			problem.Error(w, r, logger, err, "failed to update account", "accountID", accountID, "userID", user.ID)
+			return
+		}
+
+		utils.Encode(w, http.StatusOK, account)
+	}
+}
@@ -0,0 +1,26 @@
+package admin
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/utils"
+)
+
+func CreateBackup(
+	logger *slog.Logger,
+	backupService *services.BackupService,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		snapshot, err := backupService.CreateOne(r.Context())
+		if err != nil {
+			problem.Error(w, r, logger, err, "failed to create backup")
+			return
+		}
+
+		logger.InfoContext(r.Context(), "created backup", "name", snapshot.Name, "size", snapshot.Size)
+		utils.Encode(w, http.StatusCreated, snapshot)
+	}
+}
@@ -0,0 +1,72 @@
+package admin_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/backup"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestCreateBackup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns 404 if admin token is not configured", func(t *testing.T) {
+		t.Parallel()
+		serv, _ := testutils.NewTestApplication(t)
+
+		request := httptest.NewRequest("POST", "/admin/backups", nil)
+		request.Header.Set("Authorization", "Bearer ")
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNotFound)
+	})
+
+	t.Run("returns 401 if admin token is invalid", func(t *testing.T) {
+		t.Parallel()
+		cfg := testutils.NewTestConfig()
+		cfg.AdminToken = "admin-token"
+		cfg.BackupDir = t.TempDir()
+		serv, db := testutils.NewTestAppWithConfig(t, cfg)
+		userToken, _ := testutils.CreateTestUserWithToken(t, db)
+
+		for _, token := range []string{"", "wrong-token", userToken} {
+			request := httptest.NewRequest("POST", "/admin/backups", nil)
+			request.Header.Set("Authorization", "Bearer "+token)
+			response := httptest.NewRecorder()
+			serv.ServeHTTP(response, request)
+
+			testutils.AssertStatus(t, response.Code, http.StatusUnauthorized)
+		}
+
+		snapshots, err := backup.List(cfg.BackupDir)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(snapshots), 0)
+	})
+
+	t.Run("creates snapshot", func(t *testing.T) {
+		t.Parallel()
+		cfg := testutils.NewTestConfig()
+		cfg.AdminToken = "admin-token"
+		cfg.BackupDir = t.TempDir()
+		cfg.BackupGzip = true
+		serv, _ := testutils.NewTestAppWithConfig(t, cfg)
+
+		request := httptest.NewRequest("POST", "/admin/backups", nil)
+		request.Header.Set("Authorization", "Bearer admin-token")
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusCreated)
+		snapshot := testutils.DecodeJSON[backup.Snapshot](t, response.Body)
+		testutils.AssertValidDate(t, snapshot.CreatedAt)
+
+		info, err := os.Stat(filepath.Join(cfg.BackupDir, snapshot.Name))
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, info.Size(), snapshot.Size)
+	})
+}
@@ -0,0 +1,135 @@
+package expense
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+
+	"github.com/kkstas/tr-backend/internal/metrics"
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/splits"
+	"github.com/kkstas/tr-backend/internal/utils"
+)
+
+var (
+	minExpenseNameLength  = 2
+	maxExpenseNameLength  = 100
+	maxExpenseNotesLength = 1000
+	expenseDateLayout     = "2006-01-02"
+	splitMethods          = []any{
+		string(models.SplitMethodEqual),
+		string(models.SplitMethodPercent),
+		string(models.SplitMethodExact),
+		string(models.SplitMethodShares),
+	}
+)
+
+func CreateOne(
+	logger *slog.Logger,
+	expenseService *services.ExpenseService,
+	m *metrics.Metrics,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	type splitBody struct {
+		Method string              `json:"method"`
+		Shares []models.SplitShare `json:"shares"`
+	}
+	type reqBody struct {
+		Name            string     `json:"name"`
+		Notes           string     `json:"notes"`
+		Date            string     `json:"date"`
+		CategoryID      string     `json:"categoryID"`
+		Amount          float64    `json:"amount"`
+		PaymentMethodID string     `json:"paymentMethodID"`
+		AccountID       string     `json:"accountID"`
+		PaidBy          string     `json:"paidBy"`
+		Split           *splitBody `json:"split"`
+		TagIDs          []string   `json:"tagIDs"`
+		VaultID         string     `json:"vaultID"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		body, err := utils.Decode[reqBody](r)
+		if err != nil {
+			problem.Write(w, r, problem.InvalidBody())
+			return
+		}
+
+		err = validation.ValidateStruct(&body,
+			validation.Field(&body.Name, validation.Required, validation.Length(minExpenseNameLength, maxExpenseNameLength)),
+			validation.Field(&body.Notes, validation.Length(0, maxExpenseNotesLength)),
+			validation.Field(&body.Date, validation.Required, validation.Date(expenseDateLayout)),
+			validation.Field(&body.CategoryID, validation.Required),
+			validation.Field(&body.Amount, validation.Required, validation.Min(0.01)),
+			validation.Field(&body.PaymentMethodID, validation.Required),
+			validation.Field(&body.VaultID, validation.Required),
+		)
+		if err != nil {
+			problem.Write(w, r, problem.Validation(err))
+			return
+		}
+
+		var splitMethod models.SplitMethod
+		var expenseSplits []models.ExpenseSplit
+		if body.Split != nil {
+			err = validation.ValidateStruct(body.Split, validation.Field(&body.Split.Method, validation.Required, validation.In(splitMethods...)))
+			if err != nil {
+				problem.Write(w, r, problem.Validation(validation.Errors{"split": err}))
+				return
+			}
+
+			splitMethod = models.SplitMethod(body.Split.Method)
+			expenseSplits, err = splits.Compute(body.Amount, splitMethod, body.Split.Shares)
+			if err != nil {
+				problem.Write(w, r, problem.Field("split", err))
+				return
+			}
+		}
+
+		err = expenseService.CreateOne(r.Context(), user.ID, models.Expense{ // nolint: exhaustruct
+			Name:            body.Name,
+			Notes:           body.Notes,
+			Date:            body.Date,
+			CategoryID:      body.CategoryID,
+			Amount:          body.Amount,
+			PaymentMethodID: body.PaymentMethodID,
+			AccountID:       body.AccountID,
+			PaidBy:          body.PaidBy,
+			SplitMethod:     splitMethod,
+			Splits:          expenseSplits,
+			TagIDs:          body.TagIDs,
+			VaultID:         body.VaultID,
+		})
+		if err != nil {
+			if errors.Is(err, services.ErrExpenseCategoryNotFound) || errors.Is(err, services.ErrExpenseCategoryInactive) {
+				problem.Write(w, r, problem.Field("categoryID", err))
+				return
+			}
+			if errors.Is(err, services.ErrPaymentMethodNotFound) || errors.Is(err, services.ErrPaymentMethodArchived) {
+				problem.Write(w, r, problem.Field("paymentMethodID", err))
+				return
+			}
+			if errors.Is(err, services.ErrAccountNotFound) {
+				problem.Write(w, r, problem.Field("accountID", err))
+				return
+			}
+			if errors.Is(err, services.ErrTagNotFound) {
+				problem.Write(w, r, problem.Field("tagIDs", err))
+				return
+			}
+			if errors.Is(err, services.ErrUserNotAssignedToVault) || errors.Is(err, splits.ErrInvalidSplit) {
+				problem.Write(w, r, problem.Field("split", err))
+				return
+			}
+
+			problem.Error(w, r, logger, err, "failed to create expense", "vaultID", body.VaultID, "userID", user.ID)
+			return
+		}
+
+		m.ExpenseCreated()
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,206 @@
+package expense_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+type createReqBody struct {
+	Name            string  `json:"name"`
+	Date            string  `json:"date"`
+	CategoryID      string  `json:"categoryID"`
+	Amount          float64 `json:"amount"`
+	PaymentMethodID string  `json:"paymentMethodID"`
+	VaultID         string  `json:"vaultID"`
+}
+
+func TestCreateOne(t *testing.T) {
+	t.Parallel()
+
+	t.Run("creates expense", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		paymentMethod := testutils.CreateTestPaymentMethod(t, db, user.ID, vault.ID)
+
+		reqBody := createReqBody{
+			Name:            "groceries",
+			Date:            "2025-03-01",
+			CategoryID:      category.ID,
+			Amount:          19.99,
+			PaymentMethodID: paymentMethod.ID,
+			VaultID:         vault.ID,
+		}
+
+		request := httptest.NewRequest("POST", "/expenses", testutils.ToJSONBuffer(t, reqBody))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNoContent)
+
+		expenses, err := testutils.NewTestExpenseService(db).FindAll(context.Background(), user.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(expenses), 1)
+		testutils.AssertEqual(t, expenses[0].Name, reqBody.Name)
+		testutils.AssertEqual(t, expenses[0].Amount, reqBody.Amount)
+	})
+
+	t.Run("returns 400 if request body is invalid", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		paymentMethod := testutils.CreateTestPaymentMethod(t, db, user.ID, vault.ID)
+
+		reqBody := createReqBody{
+			Name:            "groceries",
+			Date:            "01.03.2025",
+			CategoryID:      category.ID,
+			Amount:          -1,
+			PaymentMethodID: paymentMethod.ID,
+			VaultID:         vault.ID,
+		}
+
+		request := httptest.NewRequest("POST", "/expenses", testutils.ToJSONBuffer(t, reqBody))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
+
+		m := testutils.DecodeFieldErrors(t, response.Body)
+		testutils.AssertNotEmpty(t, m["date"])
+		testutils.AssertNotEmpty(t, m["amount"])
+	})
+
+	t.Run("returns 404 if vault does not exist", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, _ := testutils.CreateTestUserWithToken(t, db)
+
+		reqBody := createReqBody{
+			Name:            "groceries",
+			Date:            "2025-03-01",
+			CategoryID:      uuid.New().String(),
+			Amount:          1,
+			PaymentMethodID: uuid.New().String(),
+			VaultID:         uuid.New().String(),
+		}
+
+		request := httptest.NewRequest("POST", "/expenses", testutils.ToJSONBuffer(t, reqBody))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNotFound)
+	})
+
+	t.Run("creates expense split between vault members", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		roommate := testutils.CreateTestUser(t, db)
+		err := testutils.NewTestVaultService(db).AddUser(context.Background(), user.ID, roommate.ID, vault.ID, models.VaultRoleEditor)
+		testutils.AssertNoError(t, err)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		paymentMethod := testutils.CreateTestPaymentMethod(t, db, user.ID, vault.ID)
+
+		reqBody := map[string]any{
+			"name":            "electricity",
+			"date":            "2025-03-01",
+			"categoryID":      category.ID,
+			"amount":          90,
+			"paymentMethodID": paymentMethod.ID,
+			"paidBy":          roommate.ID,
+			"split": map[string]any{
+				"method": "shares",
+				"shares": []map[string]any{{"userID": user.ID, "value": 2}, {"userID": roommate.ID, "value": 1}},
+			},
+			"vaultID": vault.ID,
+		}
+
+		request := httptest.NewRequest("POST", "/expenses", testutils.ToJSONBuffer(t, reqBody))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNoContent)
+
+		expenses, err := testutils.NewTestExpenseService(db).FindAll(context.Background(), user.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(expenses), 1)
+		testutils.AssertEqual(t, expenses[0].PaidBy, roommate.ID)
+		testutils.AssertEqual(t, len(expenses[0].Splits), 2)
+		testutils.AssertEqual(t, expenses[0].SplitMethod, models.SplitMethodShares)
+		testutils.AssertEqual(t, expenses[0].Splits[0], models.ExpenseSplit{UserID: user.ID, Amount: 60, Share: 2})
+		testutils.AssertEqual(t, expenses[0].Splits[1], models.ExpenseSplit{UserID: roommate.ID, Amount: 30, Share: 1})
+	})
+
+	t.Run("returns 400 if split participant is not a vault member", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		stranger := testutils.CreateTestUser(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		paymentMethod := testutils.CreateTestPaymentMethod(t, db, user.ID, vault.ID)
+
+		reqBody := map[string]any{
+			"name":            "electricity",
+			"date":            "2025-03-01",
+			"categoryID":      category.ID,
+			"amount":          90,
+			"paymentMethodID": paymentMethod.ID,
+			"split": map[string]any{
+				"method": "equal",
+				"shares": []map[string]any{{"userID": user.ID}, {"userID": stranger.ID}},
+			},
+			"vaultID": vault.ID,
+		}
+
+		request := httptest.NewRequest("POST", "/expenses", testutils.ToJSONBuffer(t, reqBody))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
+	})
+
+	t.Run("returns 400 if split percentages do not add up", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		paymentMethod := testutils.CreateTestPaymentMethod(t, db, user.ID, vault.ID)
+
+		reqBody := map[string]any{
+			"name":            "electricity",
+			"date":            "2025-03-01",
+			"categoryID":      category.ID,
+			"amount":          90,
+			"paymentMethodID": paymentMethod.ID,
+			"split": map[string]any{
+				"method": "percent",
+				"shares": []map[string]any{{"userID": user.ID, "value": 50}},
+			},
+			"vaultID": vault.ID,
+		}
+
+		request := httptest.NewRequest("POST", "/expenses", testutils.ToJSONBuffer(t, reqBody))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
+
+		errs := testutils.DecodeFieldErrors(t, response.Body)
+		testutils.AssertNotEmpty(t, errs["split"])
+	})
+}
@@ -0,0 +1,27 @@
+package expense
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+)
+
+func DeleteOneByID(
+	logger *slog.Logger,
+	expenseService *services.ExpenseService,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		expenseID := r.PathValue("id")
+
+		err := expenseService.DeleteOneByID(r.Context(), user.ID, expenseID)
+		if err != nil {
+			problem.Error(w, r, logger, err, "failed to delete expense", "expenseID", expenseID, "userID", user.ID)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
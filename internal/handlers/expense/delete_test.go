@@ -0,0 +1,49 @@
+package expense_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestDeleteOneByID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("moves expense to trash", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		request := httptest.NewRequest("DELETE", "/expenses/"+expense.ID, nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNoContent)
+
+		expenses, err := testutils.NewTestExpenseService(db).FindAll(context.Background(), user.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(expenses), 0)
+	})
+
+	t.Run("returns 404 if user does not belong to expense's vault", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		otherToken, _ := testutils.CreateTestUserWithToken(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		request := httptest.NewRequest("DELETE", "/expenses/"+expense.ID, nil)
+		request.Header.Set("Authorization", "Bearer "+otherToken)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNotFound)
+	})
+}
@@ -0,0 +1,89 @@
+package expense_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestFindAll(t *testing.T) {
+	t.Parallel()
+
+	t.Run("finds all expenses in vault", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+		testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		request := httptest.NewRequest("GET", "/expenses/"+vault.ID, nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+
+		expenses := testutils.DecodeJSON[[]models.Expense](t, response.Body)
+		testutils.AssertEqual(t, len(expenses), 2)
+	})
+
+	t.Run("filters expenses by tags", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		vacation := testutils.CreateTestTag(t, db, user.ID, vault.ID)
+		taxDeductible := testutils.CreateTestTag(t, db, user.ID, vault.ID)
+		expenseService := testutils.NewTestExpenseService(db)
+
+		tagged := map[string][]string{
+			"hotel":  {vacation.ID, taxDeductible.ID},
+			"flight": {vacation.ID},
+			"office": {taxDeductible.ID},
+		}
+		for name, tagIDs := range tagged {
+			expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+			_, err := expenseService.UpdateOne(t.Context(), user.ID, expense.ID, models.ExpenseUpdate{Name: &name, TagIDs: &tagIDs}) // nolint: exhaustruct
+			testutils.AssertNoError(t, err)
+		}
+		testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		request := httptest.NewRequest("GET", "/expenses/"+vault.ID+"?tag="+vacation.ID, nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+		expenses := testutils.DecodeJSON[[]models.Expense](t, response.Body)
+		testutils.AssertEqual(t, len(expenses), 2)
+
+		request = httptest.NewRequest("GET", "/expenses/"+vault.ID+"?tag="+vacation.ID+"&tag="+taxDeductible.ID, nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response = httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+		expenses = testutils.DecodeJSON[[]models.Expense](t, response.Body)
+		testutils.AssertEqual(t, len(expenses), 1)
+		testutils.AssertEqual(t, expenses[0].Name, "hotel")
+		testutils.AssertEqual(t, len(expenses[0].TagIDs), 2)
+	})
+
+	t.Run("returns 404 if user does not belong to vault", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, _ := testutils.CreateTestUserWithToken(t, db)
+
+		request := httptest.NewRequest("GET", "/expenses/"+uuid.New().String(), nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNotFound)
+	})
+}
@@ -0,0 +1,29 @@
+package expense
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+)
+
+func RestoreOneByID(
+	logger *slog.Logger,
+	expenseService *services.ExpenseService,
+	retention time.Duration,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		expenseID := r.PathValue("id")
+
+		err := expenseService.RestoreOneByID(r.Context(), user.ID, expenseID, retention)
+		if err != nil {
+			problem.Error(w, r, logger, err, "failed to restore expense", "expenseID", expenseID, "userID", user.ID)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,65 @@
+package expense_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestRestoreOneByID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("lists deleted expense in trash and restores it", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		err := testutils.NewTestExpenseService(db).DeleteOneByID(ctx, user.ID, expense.ID)
+		testutils.AssertNoError(t, err)
+
+		{
+			request := httptest.NewRequest("GET", "/expenses/"+vault.ID+"/trash", nil)
+			request.Header.Set("Authorization", "Bearer "+token)
+			response := httptest.NewRecorder()
+			serv.ServeHTTP(response, request)
+
+			testutils.AssertStatus(t, response.Code, http.StatusOK)
+			trash := testutils.DecodeJSON[[]models.Expense](t, response.Body)
+			testutils.AssertEqual(t, len(trash), 1)
+			testutils.AssertEqual(t, trash[0].ID, expense.ID)
+			testutils.AssertValidDate(t, trash[0].DeletedAt)
+		}
+
+		request := httptest.NewRequest("POST", "/expenses/"+expense.ID+"/restore", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNoContent)
+
+		expenses, err := testutils.NewTestExpenseService(db).FindAll(ctx, user.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(expenses), 1)
+	})
+
+	t.Run("returns 404 if expense is not in trash", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, _ := testutils.CreateTestUserWithToken(t, db)
+
+		request := httptest.NewRequest("POST", "/expenses/"+uuid.New().String()+"/restore", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNotFound)
+	})
+}
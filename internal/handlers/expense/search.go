@@ -0,0 +1,64 @@
+package expense
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/utils"
+)
+
+var (
+	maxSearchQueryLength = 200
+	defaultSearchLimit   = 20
+	maxSearchLimit       = 100
+)
+
+func Search(
+	logger *slog.Logger,
+	expenseService *services.ExpenseService,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	type queryParams struct {
+		Query string `json:"q"`
+		Limit int    `json:"limit"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		vaultID := r.PathValue("vaultID")
+
+		params := queryParams{
+			Query: r.URL.Query().Get("q"),
+			Limit: defaultSearchLimit,
+		}
+		if val := r.URL.Query().Get("limit"); val != "" {
+			limit, err := strconv.Atoi(val)
+			if err != nil {
+				problem.Write(w, r, problem.Invalid("limit", problem.CodeInvalid, "must be a number"))
+				return
+			}
+			params.Limit = limit
+		}
+
+		err := validation.ValidateStruct(&params,
+			validation.Field(&params.Query, validation.Required, validation.Length(1, maxSearchQueryLength)),
+			validation.Field(&params.Limit, validation.Required, validation.Min(1), validation.Max(maxSearchLimit)),
+		)
+		if err != nil {
+			problem.Write(w, r, problem.Validation(err))
+			return
+		}
+
+		results, err := expenseService.Search(r.Context(), user.ID, vaultID, params.Query, params.Limit)
+		if err != nil {
+			problem.Error(w, r, logger, err, "failed to search expenses", "vaultID", vaultID, "userID", user.ID)
+			return
+		}
+
+		utils.Encode(w, http.StatusOK, results)
+	}
+}
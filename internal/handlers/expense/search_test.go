@@ -0,0 +1,75 @@
+package expense_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestSearch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("finds expenses by name prefix", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+		testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		request := httptest.NewRequest("GET", "/vaults/"+vault.ID+"/search?q="+url.QueryEscape(expense.Name[:12]), nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+
+		results := testutils.DecodeJSON[[]models.ExpenseSearchResult](t, response.Body)
+		testutils.AssertEqual(t, len(results), 1)
+		testutils.AssertEqual(t, results[0].ID, expense.ID)
+	})
+
+	t.Run("returns 400 if query is missing", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, _, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+
+		request := httptest.NewRequest("GET", "/vaults/"+vault.ID+"/search", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
+	})
+
+	t.Run("returns 400 if limit is out of range", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, _, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+
+		request := httptest.NewRequest("GET", "/vaults/"+vault.ID+"/search?q=pizza&limit=1000", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
+	})
+
+	t.Run("returns 404 if user is not a member of the vault", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		_, _, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		otherToken, _ := testutils.CreateTestUserWithToken(t, db)
+
+		request := httptest.NewRequest("GET", "/vaults/"+vault.ID+"/search?q=pizza", nil)
+		request.Header.Set("Authorization", "Bearer "+otherToken)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNotFound)
+	})
+}
@@ -0,0 +1,30 @@
+package expense
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/utils"
+)
+
+func FindAllDeleted(
+	logger *slog.Logger,
+	expenseService *services.ExpenseService,
+	retention time.Duration,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		vaultID := r.PathValue("vaultID")
+
+		expenses, err := expenseService.FindAllDeleted(r.Context(), user.ID, vaultID, retention)
+		if err != nil {
+			problem.Error(w, r, logger, err, "failed to find deleted expenses", "vaultID", vaultID, "userID", user.ID)
+			return
+		}
+
+		utils.Encode(w, http.StatusOK, expenses)
+	}
+}
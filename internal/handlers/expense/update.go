@@ -0,0 +1,111 @@
+package expense
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/splits"
+	"github.com/kkstas/tr-backend/internal/utils"
+)
+
+func UpdateOne(
+	logger *slog.Logger,
+	expenseService *services.ExpenseService,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	type splitBody struct {
+		Method string              `json:"method"`
+		Shares []models.SplitShare `json:"shares"`
+	}
+	type reqBody struct {
+		Name            *string    `json:"name"`
+		Notes           *string    `json:"notes"`
+		Date            *string    `json:"date"`
+		CategoryID      *string    `json:"categoryID"`
+		Amount          *float64   `json:"amount"`
+		PaymentMethodID *string    `json:"paymentMethodID"`
+		AccountID       *string    `json:"accountID"`
+		TagIDs          *[]string  `json:"tagIDs"`
+		PaidBy          *string    `json:"paidBy"`
+		Split           *splitBody `json:"split"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		expenseID := r.PathValue("id")
+
+		body, err := utils.Decode[reqBody](r)
+		if err != nil {
+			problem.Write(w, r, problem.InvalidBody())
+			return
+		}
+
+		err = validation.ValidateStruct(&body,
+			validation.Field(&body.Name, validation.NilOrNotEmpty, validation.Length(minExpenseNameLength, maxExpenseNameLength)),
+			validation.Field(&body.Notes, validation.Length(0, maxExpenseNotesLength)),
+			validation.Field(&body.Date, validation.NilOrNotEmpty, validation.Date(expenseDateLayout)),
+			validation.Field(&body.CategoryID, validation.NilOrNotEmpty),
+			validation.Field(&body.Amount, validation.NilOrNotEmpty, validation.Min(0.01)),
+			validation.Field(&body.PaymentMethodID, validation.NilOrNotEmpty),
+			validation.Field(&body.PaidBy, validation.NilOrNotEmpty),
+		)
+		if err != nil {
+			problem.Write(w, r, problem.Validation(err))
+			return
+		}
+
+		var splitUpdate *models.SplitUpdate
+		if body.Split != nil {
+			err = validation.ValidateStruct(body.Split, validation.Field(&body.Split.Method, validation.Required, validation.In(splitMethods...)))
+			if err != nil {
+				problem.Write(w, r, problem.Validation(validation.Errors{"split": err}))
+				return
+			}
+			splitUpdate = &models.SplitUpdate{Method: models.SplitMethod(body.Split.Method), Shares: body.Split.Shares}
+		}
+
+		expense, err := expenseService.UpdateOne(r.Context(), user.ID, expenseID, models.ExpenseUpdate{
+			Name:            body.Name,
+			Notes:           body.Notes,
+			Date:            body.Date,
+			CategoryID:      body.CategoryID,
+			Amount:          body.Amount,
+			PaymentMethodID: body.PaymentMethodID,
+			AccountID:       body.AccountID,
+			TagIDs:          body.TagIDs,
+			PaidBy:          body.PaidBy,
+			Split:           splitUpdate,
+		})
+		if err != nil {
+			if errors.Is(err, services.ErrExpenseCategoryNotFound) || errors.Is(err, services.ErrExpenseCategoryInactive) {
+				problem.Write(w, r, problem.Field("categoryID", err))
+				return
+			}
+			if errors.Is(err, services.ErrPaymentMethodNotFound) || errors.Is(err, services.ErrPaymentMethodArchived) {
+				problem.Write(w, r, problem.Field("paymentMethodID", err))
+				return
+			}
+			if errors.Is(err, services.ErrAccountNotFound) {
+				problem.Write(w, r, problem.Field("accountID", err))
+				return
+			}
+			if errors.Is(err, services.ErrTagNotFound) {
+				problem.Write(w, r, problem.Field("tagIDs", err))
+				return
+			}
+			if errors.Is(err, services.ErrUserNotAssignedToVault) || errors.Is(err, splits.ErrInvalidSplit) {
+				problem.Write(w, r, problem.Field("split", err))
+				return
+			}
+
+			problem.Error(w, r, logger, err, "failed to update expense", "expenseID", expenseID, "userID", user.ID)
+			return
+		}
+
+		utils.Encode(w, http.StatusOK, expense)
+	}
+}
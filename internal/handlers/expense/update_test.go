@@ -0,0 +1,270 @@
+package expense_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/kkstas/tr-backend/internal/database"
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/splits"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestUpdateOne(t *testing.T) {
+	t.Parallel()
+
+	t.Run("updates expense fields and tags", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		tag := testutils.CreateTestTag(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		reqBody := map[string]any{"name": "new name", "amount": 42.5, "tagIDs": []string{tag.ID}}
+
+		request := httptest.NewRequest("PATCH", "/expenses/"+expense.ID, testutils.ToJSONBuffer(t, reqBody))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+
+		updated := testutils.DecodeJSON[models.Expense](t, response.Body)
+		testutils.AssertEqual(t, updated.Name, "new name")
+		testutils.AssertEqual(t, updated.Amount, 42.5)
+		testutils.AssertEqual(t, updated.Date, expense.Date)
+		testutils.AssertEqual(t, len(updated.TagIDs), 1)
+		testutils.AssertEqual(t, updated.TagIDs[0], tag.ID)
+	})
+
+	t.Run("returns 400 if tag belongs to another vault", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		_, otherUser, otherVault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		foreignTag := testutils.CreateTestTag(t, db, otherUser.ID, otherVault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		reqBody := map[string]any{"tagIDs": []string{foreignTag.ID}}
+
+		request := httptest.NewRequest("PATCH", "/expenses/"+expense.ID, testutils.ToJSONBuffer(t, reqBody))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
+	})
+
+	t.Run("computes equal split again when amount changes", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		participants := addTestVaultMembers(t, db, user.ID, vault.ID, 2)
+		expense := createTestSplitExpense(t, db, user.ID, vault.ID, 10, models.SplitUpdate{
+			Method: models.SplitMethodEqual,
+			Shares: []models.SplitShare{{UserID: user.ID}, {UserID: participants[0]}, {UserID: participants[1]}},
+		})
+		testutils.AssertEqual(t, expense.Splits[0].Amount, 3.34)
+
+		request := httptest.NewRequest("PATCH", "/expenses/"+expense.ID, testutils.ToJSONBuffer(t, map[string]any{"amount": 100}))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+
+		updated := testutils.DecodeJSON[models.Expense](t, response.Body)
+		testutils.AssertEqual(t, updated.SplitMethod, models.SplitMethodEqual)
+		testutils.AssertEqual(t, len(updated.Splits), 3)
+		testutils.AssertEqual(t, updated.Splits[0].Amount, 33.34)
+		testutils.AssertEqual(t, updated.Splits[1].Amount, 33.33)
+		testutils.AssertEqual(t, updated.Splits[2].Amount, 33.33)
+	})
+
+	t.Run("computes equal split with zero part again when amount changes", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		participants := addTestVaultMembers(t, db, user.ID, vault.ID, 2)
+		expense := createTestSplitExpense(t, db, user.ID, vault.ID, 0.02, models.SplitUpdate{
+			Method: models.SplitMethodEqual,
+			Shares: []models.SplitShare{{UserID: user.ID}, {UserID: participants[0]}, {UserID: participants[1]}},
+		})
+		testutils.AssertEqual(t, expense.Splits[2].Amount, 0.0)
+
+		request := httptest.NewRequest("PATCH", "/expenses/"+expense.ID, testutils.ToJSONBuffer(t, map[string]any{"amount": 0.05}))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+
+		updated := testutils.DecodeJSON[models.Expense](t, response.Body)
+		testutils.AssertEqual(t, len(updated.Splits), 3)
+		testutils.AssertEqual(t, updated.Splits[0].Amount, 0.02)
+		testutils.AssertEqual(t, updated.Splits[1].Amount, 0.02)
+		testutils.AssertEqual(t, updated.Splits[2].Amount, 0.01)
+	})
+
+	t.Run("computes shares split again when amount changes", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		participants := addTestVaultMembers(t, db, user.ID, vault.ID, 1)
+		expense := createTestSplitExpense(t, db, user.ID, vault.ID, 10, models.SplitUpdate{
+			Method: models.SplitMethodShares,
+			Shares: []models.SplitShare{{UserID: user.ID, Value: 1}, {UserID: participants[0], Value: 2}},
+		})
+
+		request := httptest.NewRequest("PATCH", "/expenses/"+expense.ID, testutils.ToJSONBuffer(t, map[string]any{"amount": 20.01}))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+
+		updated := testutils.DecodeJSON[models.Expense](t, response.Body)
+		testutils.AssertEqual(t, updated.Amount, 20.01)
+		testutils.AssertEqual(t, updated.Splits[0], models.ExpenseSplit{UserID: user.ID, Amount: 6.67, Share: 1})
+		testutils.AssertEqual(t, updated.Splits[1], models.ExpenseSplit{UserID: participants[0], Amount: 13.34, Share: 2})
+	})
+
+	t.Run("returns 400 if amount of exact split changes without new split", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		participants := addTestVaultMembers(t, db, user.ID, vault.ID, 1)
+		expense := createTestSplitExpense(t, db, user.ID, vault.ID, 10, models.SplitUpdate{
+			Method: models.SplitMethodExact,
+			Shares: []models.SplitShare{{UserID: user.ID, Value: 4}, {UserID: participants[0], Value: 6}},
+		})
+
+		request := httptest.NewRequest("PATCH", "/expenses/"+expense.ID, testutils.ToJSONBuffer(t, map[string]any{"amount": 12}))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
+		errs := testutils.DecodeFieldErrors(t, response.Body)
+		testutils.AssertNotEmpty(t, errs["split"])
+	})
+
+	t.Run("replaces split and payer", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		roommate := testutils.CreateTestUser(t, db)
+		err := testutils.NewTestVaultService(db).AddUser(t.Context(), user.ID, roommate.ID, vault.ID, models.VaultRoleEditor)
+		testutils.AssertNoError(t, err)
+		expense := createTestSplitExpense(t, db, user.ID, vault.ID, 10, models.SplitUpdate{
+			Method: models.SplitMethodEqual,
+			Shares: []models.SplitShare{{UserID: user.ID}},
+		})
+
+		reqBody := map[string]any{
+			"amount": 90,
+			"paidBy": roommate.ID,
+			"split": map[string]any{
+				"method": "shares",
+				"shares": []map[string]any{{"userID": user.ID, "value": 2}, {"userID": roommate.ID, "value": 1}},
+			},
+		}
+
+		request := httptest.NewRequest("PATCH", "/expenses/"+expense.ID, testutils.ToJSONBuffer(t, reqBody))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+
+		updated := testutils.DecodeJSON[models.Expense](t, response.Body)
+		testutils.AssertEqual(t, updated.PaidBy, roommate.ID)
+		testutils.AssertEqual(t, len(updated.Splits), 2)
+		testutils.AssertEqual(t, updated.SplitMethod, models.SplitMethodShares)
+		testutils.AssertEqual(t, updated.Splits[0], models.ExpenseSplit{UserID: user.ID, Amount: 60, Share: 2})
+		testutils.AssertEqual(t, updated.Splits[1], models.ExpenseSplit{UserID: roommate.ID, Amount: 30, Share: 1})
+	})
+
+	t.Run("returns 400 if split participant is not a vault member", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		stranger := testutils.CreateTestUser(t, db)
+		expense := createTestSplitExpense(t, db, user.ID, vault.ID, 10, models.SplitUpdate{
+			Method: models.SplitMethodEqual,
+			Shares: []models.SplitShare{{UserID: user.ID}},
+		})
+
+		reqBody := map[string]any{
+			"split": map[string]any{
+				"method": "equal",
+				"shares": []map[string]any{{"userID": user.ID}, {"userID": stranger.ID}},
+			},
+		}
+
+		request := httptest.NewRequest("PATCH", "/expenses/"+expense.ID, testutils.ToJSONBuffer(t, reqBody))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
+		errs := testutils.DecodeFieldErrors(t, response.Body)
+		testutils.AssertNotEmpty(t, errs["split"])
+	})
+
+	t.Run("returns 404 if expense does not exist", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, _ := testutils.CreateTestUserWithToken(t, db)
+
+		request := httptest.NewRequest("PATCH", "/expenses/"+uuid.New().String(), testutils.ToJSONBuffer(t, map[string]any{"name": "new name"}))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNotFound)
+	})
+}
+
+func createTestSplitExpense(t testing.TB, db *database.DB, userID, vaultID string, amount float64, split models.SplitUpdate) models.Expense {
+	t.Helper()
+	category := testutils.CreateTestExpenseCategory(t, db, userID, vaultID)
+	paymentMethod := testutils.CreateTestPaymentMethod(t, db, userID, vaultID)
+	expenseService := testutils.NewTestExpenseService(db)
+
+	expenseSplits, err := splits.Compute(amount, split.Method, split.Shares)
+	testutils.AssertNoError(t, err)
+
+	err = expenseService.CreateOne(t.Context(), userID, models.Expense{ // nolint: exhaustruct
+		Name:            "split",
+		Date:            "2025-03-01",
+		CategoryID:      category.ID,
+		Amount:          amount,
+		PaymentMethodID: paymentMethod.ID,
+		SplitMethod:     split.Method,
+		Splits:          expenseSplits,
+		VaultID:         vaultID,
+	})
+	testutils.AssertNoError(t, err)
+
+	expenses, err := expenseService.FindAll(t.Context(), userID, vaultID)
+	testutils.AssertNoError(t, err)
+	return expenses[0]
+}
+
+// addTestVaultMembers adds count new users to the vault as editors and returns their IDs.
+func addTestVaultMembers(t testing.TB, db *database.DB, ownerID, vaultID string, count int) []string {
+	t.Helper()
+	userIDs := make([]string, count)
+	for i := range userIDs {
+		member := testutils.CreateTestUser(t, db)
+		err := testutils.NewTestVaultService(db).AddUser(t.Context(), ownerID, member.ID, vaultID, models.VaultRoleEditor)
+		testutils.AssertNoError(t, err)
+		userIDs[i] = member.ID
+	}
+	return userIDs
+}
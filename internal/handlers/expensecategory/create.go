@@ -2,11 +2,13 @@ package expensecategory
 
 import (
 	"errors"
+	"log/slog"
 	"net/http"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 
 	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
 	"github.com/kkstas/tr-backend/internal/services"
 	"github.com/kkstas/tr-backend/internal/utils"
 )
@@ -17,17 +19,19 @@ var (
 )
 
 func CreateOne(
+	logger *slog.Logger,
 	expenseCategoryService *services.ExpenseCategoryService,
 ) func(w http.ResponseWriter, r *http.Request, user *models.User) {
 	type reqBody struct {
-		Name    string `json:"name"`
-		VaultID string `json:"vaultID"`
+		Name     string `json:"name"`
+		VaultID  string `json:"vaultID"`
+		ParentID string `json:"parentID"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
 		body, err := utils.Decode[reqBody](r)
 		if err != nil {
-			utils.Encode(w, http.StatusBadRequest, map[string]string{"message": "failed to decode request body"})
+			problem.Write(w, r, problem.InvalidBody())
 			return
 		}
 
@@ -36,17 +40,18 @@ func CreateOne(
 			validation.Field(&body.VaultID, validation.Required),
 		)
 		if err != nil {
-			utils.Encode(w, http.StatusBadRequest, err)
+			problem.Write(w, r, problem.Validation(err))
 			return
 		}
 
-		err = expenseCategoryService.CreateOne(r.Context(), body.Name, user.ID, body.VaultID)
+		err = expenseCategoryService.CreateOne(r.Context(), body.Name, user.ID, body.VaultID, body.ParentID)
 		if err != nil {
-			if errors.Is(err, services.ErrVaultNotFound) {
-				w.WriteHeader(http.StatusNotFound)
+			if errors.Is(err, services.ErrInvalidExpenseCategoryParent) {
+				problem.Write(w, r, problem.Field("parentID", err))
 				return
 			}
-			utils.Encode(w, http.StatusInternalServerError, err.Error())
+
+			problem.Error(w, r, logger, err, "failed to create expense category", "vaultID", body.VaultID, "userID", user.ID)
 			return
 		}
 
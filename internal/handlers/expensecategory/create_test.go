@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/kkstas/tr-backend/internal/models"
 	"github.com/kkstas/tr-backend/internal/testutils"
 )
 
@@ -84,6 +85,28 @@ func TestCreateOne(t *testing.T) {
 		testutils.AssertStatus(t, response.Code, http.StatusNotFound)
 	})
 
+	t.Run("returns 403 if user cannot manage categories in given vault", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		_, owner, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		token, editor := testutils.CreateTestUserWithToken(t, db)
+
+		err := testutils.NewTestVaultService(db).AddUser(context.Background(), owner.ID, editor.ID, vault.ID, models.VaultRoleEditor)
+		testutils.AssertNoError(t, err)
+
+		reqBody := struct {
+			Name    string `json:"name"`
+			VaultID string `json:"vaultID"`
+		}{Name: testutils.RandomString(10), VaultID: vault.ID}
+
+		request := httptest.NewRequest("POST", "/expensecategories", testutils.ToJSONBuffer(t, reqBody))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusForbidden)
+	})
+
 	t.Run("returns 404 if vault does not exist", func(t *testing.T) {
 		t.Parallel()
 		serv, db := testutils.NewTestApplication(t)
@@ -1,15 +1,17 @@
 package expensecategory
 
 import (
-	"errors"
+	"log/slog"
 	"net/http"
 
 	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
 	"github.com/kkstas/tr-backend/internal/services"
 	"github.com/kkstas/tr-backend/internal/utils"
 )
 
 func FindAll(
+	logger *slog.Logger,
 	expenseCategoryService *services.ExpenseCategoryService,
 ) func(w http.ResponseWriter, r *http.Request, user *models.User) {
 	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
@@ -17,11 +19,7 @@ func FindAll(
 
 		categories, err := expenseCategoryService.FindAll(r.Context(), user.ID, vaultID)
 		if err != nil {
-			if errors.Is(err, services.ErrVaultNotFound) {
-				utils.Encode(w, http.StatusNotFound, map[string]string{"message": "vault not found"})
-				return
-			}
-			w.WriteHeader(http.StatusInternalServerError)
+			problem.Error(w, r, logger, err, "failed to find expense categories", "vaultID", vaultID, "userID", user.ID)
 			return
 		}
 
@@ -20,7 +20,7 @@ func TestFindAll(t *testing.T) {
 		serv, db := testutils.NewTestApplication(t)
 		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
 
-		err := testutils.NewTestExpenseCategoryService(db).CreateOne(ctx, "some name", user.ID, vault.ID)
+		err := testutils.NewTestExpenseCategoryService(db).CreateOne(ctx, "some name", user.ID, vault.ID, "")
 		testutils.AssertNoError(t, err)
 
 		request := httptest.NewRequest("GET", "/expensecategories/"+vault.ID, nil)
@@ -0,0 +1,54 @@
+package expensecategory
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/utils"
+)
+
+func Merge(
+	logger *slog.Logger,
+	expenseCategoryService *services.ExpenseCategoryService,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	type reqBody struct {
+		TargetCategoryID string `json:"targetCategoryID"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		categoryID := r.PathValue("id")
+
+		body, err := utils.Decode[reqBody](r)
+		if err != nil {
+			problem.Write(w, r, problem.InvalidBody())
+			return
+		}
+
+		err = validation.ValidateStruct(&body,
+			validation.Field(&body.TargetCategoryID, validation.Required),
+		)
+		if err != nil {
+			problem.Write(w, r, problem.Validation(err))
+			return
+		}
+
+		err = expenseCategoryService.Merge(r.Context(), user.ID, categoryID, body.TargetCategoryID)
+		if err != nil {
+			if errors.Is(err, services.ErrCannotMergeExpenseCategoryIntoItself) {
+				problem.Write(w, r, problem.Field("targetCategoryID", err))
+				return
+			}
+
+			problem.Error(w, r, logger, err, "failed to merge expense categories", "categoryID", categoryID, "targetCategoryID", body.TargetCategoryID, "userID", user.ID)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,59 @@
+package expensecategory_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	type reqBody struct {
+		TargetCategoryID string `json:"targetCategoryID"`
+	}
+
+	t.Run("moves expenses into target category and deletes merged one", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		source := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		target := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		testutils.CreateTestExpense(t, db, user.ID, vault.ID, source.ID)
+		testutils.CreateTestExpense(t, db, user.ID, vault.ID, source.ID)
+
+		request := httptest.NewRequest("POST", "/expensecategories/"+source.ID+"/merge", testutils.ToJSONBuffer(t, reqBody{TargetCategoryID: target.ID}))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNoContent)
+
+		expenses, err := testutils.NewTestExpenseService(db).FindAll(t.Context(), user.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(expenses), 2)
+		for _, expense := range expenses {
+			testutils.AssertEqual(t, expense.CategoryID, target.ID)
+		}
+
+		categories, err := testutils.NewTestExpenseCategoryService(db).FindAll(t.Context(), user.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(categories), 1)
+	})
+
+	t.Run("returns 400 when merging category into itself", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+
+		request := httptest.NewRequest("POST", "/expensecategories/"+category.ID+"/merge", testutils.ToJSONBuffer(t, reqBody{TargetCategoryID: category.ID}))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,54 @@
+package expensecategory
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/utils"
+)
+
+func Reorder(
+	logger *slog.Logger,
+	expenseCategoryService *services.ExpenseCategoryService,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	type reqBody struct {
+		CategoryIDs []string `json:"categoryIDs"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		vaultID := r.PathValue("vaultID")
+
+		body, err := utils.Decode[reqBody](r)
+		if err != nil {
+			problem.Write(w, r, problem.InvalidBody())
+			return
+		}
+
+		err = validation.ValidateStruct(&body,
+			validation.Field(&body.CategoryIDs, validation.Required),
+		)
+		if err != nil {
+			problem.Write(w, r, problem.Validation(err))
+			return
+		}
+
+		err = expenseCategoryService.Reorder(r.Context(), user.ID, vaultID, body.CategoryIDs)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidExpenseCategoryOrder) {
+				problem.Write(w, r, problem.Field("categoryIDs", err))
+				return
+			}
+
+			problem.Error(w, r, logger, err, "failed to reorder expense categories", "vaultID", vaultID, "userID", user.ID)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,54 @@
+package expensecategory_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestReorder(t *testing.T) {
+	t.Parallel()
+
+	type reqBody struct {
+		CategoryIDs []string `json:"categoryIDs"`
+	}
+
+	t.Run("reorders expense categories", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		first := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		second := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+
+		request := httptest.NewRequest("PUT", "/expensecategories/"+vault.ID+"/order", testutils.ToJSONBuffer(t, reqBody{CategoryIDs: []string{second.ID, first.ID}}))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNoContent)
+
+		categories, err := testutils.NewTestExpenseCategoryService(db).FindAll(t.Context(), user.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, categories[0].ID, second.ID)
+		testutils.AssertEqual(t, categories[0].Priority, 0)
+		testutils.AssertEqual(t, categories[1].ID, first.ID)
+		testutils.AssertEqual(t, categories[1].Priority, 1)
+	})
+
+	t.Run("returns 400 if not every category is listed", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		first := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+
+		request := httptest.NewRequest("PUT", "/expensecategories/"+vault.ID+"/order", testutils.ToJSONBuffer(t, reqBody{CategoryIDs: []string{first.ID}}))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,77 @@
+package expensecategory
+
+import (
+	"cmp"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+
+	"github.com/kkstas/tr-backend/internal/categorytemplates"
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/utils"
+)
+
+func FindTemplates(logger *slog.Logger) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	return func(w http.ResponseWriter, r *http.Request, _ *models.User) {
+		locale := cmp.Or(r.URL.Query().Get("locale"), r.Header.Get("Accept-Language"))
+
+		templates := []categorytemplates.Template{}
+		for _, name := range categorytemplates.Names() {
+			template, err := categorytemplates.Find(name, locale)
+			if err != nil {
+				problem.Error(w, r, logger, err, "failed to find category template", "template", name)
+				return
+			}
+			templates = append(templates, *template)
+		}
+
+		utils.Encode(w, http.StatusOK, templates)
+	}
+}
+
+func ApplyTemplate(
+	logger *slog.Logger,
+	expenseCategoryService *services.ExpenseCategoryService,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	type reqBody struct {
+		Template string `json:"template"`
+		Locale   string `json:"locale"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		vaultID := r.PathValue("vaultID")
+
+		body, err := utils.Decode[reqBody](r)
+		if err != nil {
+			problem.Write(w, r, problem.InvalidBody())
+			return
+		}
+
+		err = validation.ValidateStruct(&body,
+			validation.Field(&body.Template, validation.Required),
+		)
+		if err != nil {
+			problem.Write(w, r, problem.Validation(err))
+			return
+		}
+
+		locale := cmp.Or(body.Locale, r.Header.Get("Accept-Language"))
+
+		err = expenseCategoryService.ApplyTemplate(r.Context(), user.ID, vaultID, body.Template, locale)
+		if err != nil {
+			if errors.Is(err, services.ErrCategoryTemplateNotFound) {
+				problem.Write(w, r, problem.Field("template", err))
+				return
+			}
+
+			problem.Error(w, r, logger, err, "failed to apply category template", "vaultID", vaultID, "userID", user.ID, "template", body.Template)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
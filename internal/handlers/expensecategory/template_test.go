@@ -0,0 +1,72 @@
+package expensecategory_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/categorytemplates"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestFindTemplates(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns templates translated using Accept-Language header", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, _ := testutils.CreateTestUserWithToken(t, db)
+
+		request := httptest.NewRequest("GET", "/expensecategory-templates", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		request.Header.Set("Accept-Language", "pl-PL,pl;q=0.9")
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+
+		templates := testutils.DecodeJSON[[]categorytemplates.Template](t, response.Body)
+		testutils.AssertEqual(t, len(templates), len(categorytemplates.Names()))
+		for _, template := range templates {
+			testutils.AssertEqual(t, template.Locale, "pl")
+		}
+	})
+}
+
+func TestApplyTemplate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("creates template categories in vault", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+
+		request := httptest.NewRequest("POST", "/expensecategories/"+vault.ID+"/apply-template", testutils.ToJSONBuffer(t, map[string]string{"template": "business", "locale": "en"}))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNoContent)
+
+		template, err := categorytemplates.Find("business", "en")
+		testutils.AssertNoError(t, err)
+		tree, err := testutils.NewTestExpenseCategoryService(db).FindTree(t.Context(), user.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(tree), len(template.Categories))
+		testutils.AssertEqual(t, tree[0].Name, template.Categories[0].Name)
+		testutils.AssertEqual(t, len(tree[0].Children), len(template.Categories[0].Subcategories))
+	})
+
+	t.Run("returns 400 for unknown template", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, _, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+
+		request := httptest.NewRequest("POST", "/expensecategories/"+vault.ID+"/apply-template", testutils.ToJSONBuffer(t, map[string]string{"template": "unknown"}))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,65 @@
+package expensecategory_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestFindTree(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns categories nested under their parents", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		parent := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+
+		request := httptest.NewRequest("POST", "/expensecategories", testutils.ToJSONBuffer(t, map[string]string{
+			"name":     "groceries",
+			"vaultID":  vault.ID,
+			"parentID": parent.ID,
+		}))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+		testutils.AssertStatus(t, response.Code, http.StatusNoContent)
+
+		request = httptest.NewRequest("GET", "/expensecategories/"+vault.ID+"/tree", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response = httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+
+		tree := testutils.DecodeJSON[[]models.ExpenseCategoryTreeNode](t, response.Body)
+		testutils.AssertEqual(t, len(tree), 1)
+		testutils.AssertEqual(t, tree[0].ID, parent.ID)
+		testutils.AssertEqual(t, len(tree[0].Children), 1)
+		testutils.AssertEqual(t, tree[0].Children[0].Name, "groceries")
+	})
+
+	t.Run("returns 400 when moving category under its subcategory", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		parent := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		child := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+
+		request := httptest.NewRequest("PATCH", "/expensecategories/"+child.ID, testutils.ToJSONBuffer(t, map[string]string{"parentID": parent.ID}))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+
+		request = httptest.NewRequest("PATCH", "/expensecategories/"+parent.ID, testutils.ToJSONBuffer(t, map[string]string{"parentID": child.ID}))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response = httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,74 @@
+package expensecategory
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/utils"
+)
+
+func UpdateOne(
+	logger *slog.Logger,
+	expenseCategoryService *services.ExpenseCategoryService,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	type reqBody struct {
+		Name     *string `json:"name"`
+		Status   *string `json:"status"`
+		ParentID *string `json:"parentID"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		categoryID := r.PathValue("id")
+
+		body, err := utils.Decode[reqBody](r)
+		if err != nil {
+			problem.Write(w, r, problem.InvalidBody())
+			return
+		}
+
+		err = validation.ValidateStruct(&body,
+			validation.Field(&body.Name, validation.NilOrNotEmpty, validation.Length(minCategoryNameLength, maxCategoryNameLength)),
+			validation.Field(&body.Status, validation.NilOrNotEmpty, validation.In(
+				string(models.ExpenseCategoryStatusActive),
+				string(models.ExpenseCategoryStatusInactive),
+			)),
+		)
+		if err != nil {
+			problem.Write(w, r, problem.Validation(err))
+			return
+		}
+
+		update := models.ExpenseCategoryUpdate{Name: body.Name, ParentID: body.ParentID} // nolint: exhaustruct
+		if body.Status != nil {
+			status := models.ExpenseCategoryStatus(*body.Status)
+			update.Status = &status
+		}
+
+		category, err := expenseCategoryService.UpdateOne(r.Context(), user.ID, categoryID, update)
+		if err != nil {
+			if errors.Is(err, services.ErrExpenseCategoryWithThatNameAlreadyExists) {
+				problem.Write(w, r, problem.Field("name", err))
+				return
+			}
+			if errors.Is(err, services.ErrInvalidExpenseCategoryParent) {
+				problem.Write(w, r, problem.Field("parentID", err))
+				return
+			}
+			if errors.Is(err, services.ErrExpenseCategoryCycle) {
+				problem.Write(w, r, problem.Field("parentID", err))
+				return
+			}
+
+			problem.Error(w, r, logger, err, "failed to update expense category", "categoryID", categoryID, "userID", user.ID)
+			return
+		}
+
+		utils.Encode(w, http.StatusOK, category)
+	}
+}
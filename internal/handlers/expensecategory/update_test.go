@@ -0,0 +1,62 @@
+package expensecategory_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestUpdateOne(t *testing.T) {
+	t.Parallel()
+
+	t.Run("renames and deactivates expense category", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+
+		reqBody := map[string]string{"name": "renamed", "status": string(models.ExpenseCategoryStatusInactive)}
+
+		request := httptest.NewRequest("PATCH", "/expensecategories/"+category.ID, testutils.ToJSONBuffer(t, reqBody))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+
+		updated := testutils.DecodeJSON[models.ExpenseCategory](t, response.Body)
+		testutils.AssertEqual(t, updated.Name, "renamed")
+		testutils.AssertEqual(t, updated.Status, models.ExpenseCategoryStatusInactive)
+	})
+
+	t.Run("returns 400 if status is invalid", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+
+		request := httptest.NewRequest("PATCH", "/expensecategories/"+category.ID, testutils.ToJSONBuffer(t, map[string]string{"status": "archived"}))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
+	})
+
+	t.Run("returns 404 if category does not exist", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, _ := testutils.CreateTestUserWithToken(t, db)
+
+		request := httptest.NewRequest("PATCH", "/expensecategories/"+uuid.New().String(), testutils.ToJSONBuffer(t, map[string]string{"name": "renamed"}))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNotFound)
+	})
+}
@@ -0,0 +1,71 @@
+package income
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/utils"
+)
+
+var (
+	minIncomeSourceLength = 2
+	maxIncomeSourceLength = 100
+	incomeDateLayout      = "2006-01-02"
+)
+
+func CreateOne(
+	logger *slog.Logger,
+	incomeService *services.IncomeService,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	type reqBody struct {
+		Source    string  `json:"source"`
+		Date      string  `json:"date"`
+		Amount    float64 `json:"amount"`
+		AccountID string  `json:"accountID"`
+		VaultID   string  `json:"vaultID"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		body, err := utils.Decode[reqBody](r)
+		if err != nil {
+			problem.Write(w, r, problem.InvalidBody())
+			return
+		}
+
+		err = validation.ValidateStruct(&body,
+			validation.Field(&body.Source, validation.Required, validation.Length(minIncomeSourceLength, maxIncomeSourceLength)),
+			validation.Field(&body.Date, validation.Required, validation.Date(incomeDateLayout)),
+			validation.Field(&body.Amount, validation.Required, validation.Min(0.01)),
+			validation.Field(&body.VaultID, validation.Required),
+		)
+		if err != nil {
+			problem.Write(w, r, problem.Validation(err))
+			return
+		}
+
+		err = incomeService.CreateOne(r.Context(), user.ID, models.Income{ // nolint: exhaustruct
+			Source:    body.Source,
+			Date:      body.Date,
+			Amount:    body.Amount,
+			AccountID: body.AccountID,
+			VaultID:   body.VaultID,
+		})
+		if err != nil {
+			if errors.Is(err, services.ErrAccountNotFound) {
+				problem.Write(w, r, problem.Field("accountID", err))
+				return
+			}
+
+			problem.Error(w, r, logger, err, "failed to create income", "vaultID", body.VaultID, "userID", user.ID)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,80 @@
+package income_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestCreateOne(t *testing.T) {
+	t.Parallel()
+
+	t.Run("creates income", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		account := testutils.CreateTestAccount(t, db, user.ID, vault.ID, 0)
+
+		reqBody := map[string]any{"source": "salary", "date": "2025-02-01", "amount": 2500, "accountID": account.ID, "vaultID": vault.ID}
+
+		request := httptest.NewRequest("POST", "/incomes", testutils.ToJSONBuffer(t, reqBody))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNoContent)
+
+		request = httptest.NewRequest("GET", "/incomes/"+vault.ID, nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response = httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+
+		incomes := testutils.DecodeJSON[[]models.Income](t, response.Body)
+		testutils.AssertEqual(t, len(incomes), 1)
+		testutils.AssertEqual(t, incomes[0].Source, "salary")
+		testutils.AssertEqual(t, incomes[0].Amount, 2500.0)
+		testutils.AssertEqual(t, incomes[0].AccountID, account.ID)
+		testutils.AssertEqual(t, incomes[0].CreatedBy, user.ID)
+	})
+
+	t.Run("returns 400 for invalid body", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, _, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+
+		reqBody := map[string]any{"source": "s", "date": "01/02/2025", "amount": -5, "vaultID": vault.ID}
+
+		request := httptest.NewRequest("POST", "/incomes", testutils.ToJSONBuffer(t, reqBody))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
+
+		errs := testutils.DecodeFieldErrors(t, response.Body)
+		testutils.AssertNotEmpty(t, errs["source"])
+		testutils.AssertNotEmpty(t, errs["date"])
+		testutils.AssertNotEmpty(t, errs["amount"])
+	})
+
+	t.Run("returns 404 if user does not belong to vault", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, _ := testutils.CreateTestUserWithToken(t, db)
+
+		reqBody := map[string]any{"source": "salary", "date": "2025-02-01", "amount": 2500, "vaultID": uuid.New().String()}
+
+		request := httptest.NewRequest("POST", "/incomes", testutils.ToJSONBuffer(t, reqBody))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNotFound)
+	})
+}
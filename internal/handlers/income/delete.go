@@ -0,0 +1,27 @@
+package income
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+)
+
+func DeleteOneByID(
+	logger *slog.Logger,
+	incomeService *services.IncomeService,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		incomeID := r.PathValue("id")
+
+		err := incomeService.DeleteOneByID(r.Context(), user.ID, incomeID)
+		if err != nil {
+			problem.Error(w, r, logger, err, "failed to delete income", "incomeID", incomeID, "userID", user.ID)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,51 @@
+package income_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestDeleteOneByID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("deletes income", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		incomeService := testutils.NewTestIncomeService(db)
+
+		err := incomeService.CreateOne(t.Context(), user.ID, models.Income{Source: "salary", Date: "2025-02-01", Amount: 10, VaultID: vault.ID}) // nolint: exhaustruct
+		testutils.AssertNoError(t, err)
+		incomes, err := incomeService.FindAll(t.Context(), user.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+
+		request := httptest.NewRequest("DELETE", "/incomes/"+incomes[0].ID, nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNoContent)
+
+		incomes, err = incomeService.FindAll(t.Context(), user.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(incomes), 0)
+	})
+
+	t.Run("returns 404 if income does not exist", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, _ := testutils.CreateTestUserWithToken(t, db)
+
+		request := httptest.NewRequest("DELETE", "/incomes/"+uuid.New().String(), nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNotFound)
+	})
+}
@@ -0,0 +1,64 @@
+package income
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/utils"
+)
+
+func UpdateOne(
+	logger *slog.Logger,
+	incomeService *services.IncomeService,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	type reqBody struct {
+		Source    *string  `json:"source"`
+		Date      *string  `json:"date"`
+		Amount    *float64 `json:"amount"`
+		AccountID *string  `json:"accountID"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		incomeID := r.PathValue("id")
+
+		body, err := utils.Decode[reqBody](r)
+		if err != nil {
+			problem.Write(w, r, problem.InvalidBody())
+			return
+		}
+
+		err = validation.ValidateStruct(&body,
+			validation.Field(&body.Source, validation.NilOrNotEmpty, validation.Length(minIncomeSourceLength, maxIncomeSourceLength)),
+			validation.Field(&body.Date, validation.NilOrNotEmpty, validation.Date(incomeDateLayout)),
+			validation.Field(&body.Amount, validation.NilOrNotEmpty, validation.Min(0.01)),
+		)
+		if err != nil {
+			problem.Write(w, r, problem.Validation(err))
+			return
+		}
+
+		income, err := incomeService.UpdateOne(r.Context(), user.ID, incomeID, models.IncomeUpdate{
+			Source:    body.Source,
+			Date:      body.Date,
+			Amount:    body.Amount,
+			AccountID: body.AccountID,
+		})
+		if err != nil {
+			if errors.Is(err, services.ErrAccountNotFound) {
+				problem.Write(w, r, problem.Field("accountID", err))
+				return
+			}
+
+			problem.Error(w, r, logger, err, "failed to update income", "incomeID", incomeID, "userID", user.ID)
+			return
+		}
+
+		utils.Encode(w, http.StatusOK, income)
+	}
+}
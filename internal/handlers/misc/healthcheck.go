@@ -1,7 +1,67 @@
 package misc
 
-import "net/http"
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
 
+	"github.com/kkstas/tr-backend/internal/utils"
+)
+
+const readinessCheckTimeout = 2 * time.Second
+
+// HealthCheckHandler reports liveness: the process is up and serving requests.
 func HealthCheckHandler(w http.ResponseWriter, _ *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
+
+// ReadinessCheck is a named dependency check, e.g. of the database.
+type ReadinessCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+type checkResult struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+type readiness struct {
+	Status string        `json:"status"`
+	Checks []checkResult `json:"checks"`
+}
+
+// ReadinessHandler runs all checks concurrently and responds with 503 if any of them
+// fails, so that no traffic is routed to an instance that can't serve it. The endpoint is
+// unauthenticated, so failure details are only logged.
+func ReadinessHandler(logger *slog.Logger, checks []ReadinessCheck) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), readinessCheckTimeout)
+		defer cancel()
+
+		results := make([]checkResult, len(checks))
+		var wg sync.WaitGroup
+		for i, check := range checks {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i] = checkResult{Name: check.Name, Status: "ok"}
+				if err := check.Check(ctx); err != nil {
+					logger.WarnContext(r.Context(), "readiness check failed", "check", check.Name, "error", err)
+					results[i].Status = "fail"
+				}
+			}()
+		}
+		wg.Wait()
+
+		status, res := http.StatusOK, readiness{Status: "ok", Checks: results}
+		for _, result := range results {
+			if result.Status != "ok" {
+				status, res.Status = http.StatusServiceUnavailable, "unavailable"
+			}
+		}
+		utils.Encode(w, status, res)
+	}
+}
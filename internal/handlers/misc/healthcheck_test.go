@@ -18,3 +18,71 @@ func TestHealthCheck(t *testing.T) {
 
 	testutils.AssertStatus(t, response.Code, http.StatusOK)
 }
+
+func TestReadiness(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns 200 with checks breakdown", func(t *testing.T) {
+		t.Parallel()
+		serv, _ := testutils.NewTestApplication(t)
+
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, httptest.NewRequest("GET", "/health/ready", nil))
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+		body := testutils.DecodeJSON[readinessBody](t, response.Body)
+		testutils.AssertEqual(t, body.Status, "ok")
+		testutils.AssertEqual(t, len(body.Checks), 3)
+		for _, check := range body.Checks {
+			testutils.AssertEqual(t, check.Status, "ok")
+		}
+	})
+
+	t.Run("returns 503 if migrations are pending", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		_, err := db.Exec(`PRAGMA user_version = 1`)
+		testutils.AssertNoError(t, err)
+
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, httptest.NewRequest("GET", "/health/ready", nil))
+
+		testutils.AssertStatus(t, response.Code, http.StatusServiceUnavailable)
+		body := testutils.DecodeJSON[readinessBody](t, response.Body)
+		testutils.AssertEqual(t, body.Status, "unavailable")
+		for _, check := range body.Checks {
+			if check.Name == "migrations" {
+				testutils.AssertEqual(t, check.Status, "fail")
+			} else {
+				testutils.AssertEqual(t, check.Status, "ok")
+			}
+		}
+	})
+
+	t.Run("returns 503 without error details if database is unavailable", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		testutils.AssertNoError(t, db.Close())
+
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, httptest.NewRequest("GET", "/health/ready", nil))
+
+		testutils.AssertStatus(t, response.Code, http.StatusServiceUnavailable)
+		body := testutils.DecodeJSON[readinessBody](t, response.Body)
+		for _, check := range body.Checks {
+			if check.Name == "database" {
+				testutils.AssertEqual(t, check.Status, "fail")
+				testutils.AssertEqual(t, check.Error, "")
+			}
+		}
+	})
+}
+
+type readinessBody struct {
+	Status string `json:"status"`
+	Checks []struct {
+		Name   string `json:"name"`
+		Status string `json:"status"`
+		Error  string `json:"error"`
+	} `json:"checks"`
+}
@@ -0,0 +1,82 @@
+package misc_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns 404 if metrics are disabled", func(t *testing.T) {
+		t.Parallel()
+		serv, _ := testutils.NewTestApplication(t)
+
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, httptest.NewRequest("GET", "/metrics", nil))
+
+		testutils.AssertStatus(t, response.Code, http.StatusNotFound)
+	})
+
+	t.Run("reports requests, logins, created expenses and database pool", func(t *testing.T) {
+		t.Parallel()
+		cfg := testutils.NewTestConfig()
+		cfg.EnableMetrics = true
+		serv, db := testutils.NewTestAppWithConfig(t, cfg)
+
+		password := testutils.RandomString(32)
+		err := testutils.NewTestUserService(db).CreateOne(context.Background(), "John", "Doe", "john@doe.eu", password)
+		testutils.AssertNoError(t, err)
+
+		for _, pwd := range []string{password, testutils.RandomString(32), testutils.RandomString(32)} {
+			body := map[string]string{"email": "john@doe.eu", "password": pwd}
+			serv.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/login", testutils.ToJSONBuffer(t, body)))
+		}
+
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		paymentMethod := testutils.CreateTestPaymentMethod(t, db, user.ID, vault.ID)
+		request := httptest.NewRequest("POST", "/expenses", testutils.ToJSONBuffer(t, map[string]any{
+			"name":            "groceries",
+			"date":            "2025-03-01",
+			"categoryID":      category.ID,
+			"amount":          19.99,
+			"paymentMethodID": paymentMethod.ID,
+			"vaultID":         vault.ID,
+		}))
+		request.Header.Set("Authorization", "Bearer "+token)
+		serv.ServeHTTP(httptest.NewRecorder(), request)
+
+		request = httptest.NewRequest("GET", "/expenses/"+vault.ID, nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		serv.ServeHTTP(httptest.NewRecorder(), request)
+
+		serv.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/does-not-exist", nil))
+
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, httptest.NewRequest("GET", "/metrics", nil))
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+
+		body := response.Body.String()
+		for _, want := range []string{
+			`tr_logins_total{result="success"} 1`,
+			`tr_logins_total{result="failure"} 2`,
+			`tr_expenses_created_total 1`,
+			`tr_http_requests_total{method="POST",route="/login",status="200"} 1`,
+			`tr_http_requests_total{method="POST",route="/login",status="401"} 2`,
+			`tr_http_requests_total{method="POST",route="/expenses",status="204"} 1`,
+			`tr_http_requests_total{method="GET",route="/expenses/{vaultID}",status="200"} 1`,
+			`tr_http_requests_total{method="GET",route="/",status="404"} 1`,
+			`go_sql_max_open_connections{db_name="writer"} 1`,
+		} {
+			if !strings.Contains(body, want) {
+				t.Errorf("expected metrics to contain %q", want)
+			}
+		}
+	})
+}
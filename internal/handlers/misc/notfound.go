@@ -1,7 +1,11 @@
 package misc
 
-import "net/http"
+import (
+	"net/http"
 
-func NotFoundHandler(w http.ResponseWriter, _ *http.Request) {
-	w.WriteHeader(http.StatusNotFound)
+	"github.com/kkstas/tr-backend/internal/problem"
+)
+
+func NotFoundHandler(w http.ResponseWriter, r *http.Request) {
+	problem.Write(w, r, problem.NotFound())
 }
@@ -0,0 +1,20 @@
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+//go:embed openapi.json
+var document []byte
+
+// JSON returns the OpenAPI document served by Document.
+func JSON() []byte {
+	return document
+}
+
+func Document(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(document)
+}
@@ -0,0 +1,140 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestDocument(t *testing.T) {
+	t.Parallel()
+	serv, _ := testutils.NewTestApplication(t)
+
+	request := httptest.NewRequest("GET", "/openapi.json", nil)
+	response := httptest.NewRecorder()
+	serv.ServeHTTP(response, request)
+
+	testutils.AssertStatus(t, response.Code, http.StatusOK)
+	document := testutils.DecodeJSON[map[string]any](t, response.Body)
+	testutils.AssertEqual(t, document["openapi"], any("3.0.3"))
+}
+
+var pathParam = regexp.MustCompile(`\{[^}]+\}`)
+
+// TestRoutesAreDocumented compares routes registered in SetupRoutes with the
+// documented operations, ignoring path parameter names.
+func TestRoutesAreDocumented(t *testing.T) {
+	t.Parallel()
+
+	registered := registeredRoutes(t)
+
+	file, err := os.ReadFile("openapi.json")
+	testutils.AssertNoError(t, err)
+	var document struct {
+		Paths map[string]map[string]any `json:"paths"`
+	}
+	testutils.AssertNoError(t, json.Unmarshal(file, &document))
+
+	var documented []string
+	for path, operations := range document.Paths {
+		for method := range operations {
+			documented = append(documented, strings.ToUpper(method)+" "+pathParam.ReplaceAllString(path, "{}"))
+		}
+	}
+
+	for _, route := range registered {
+		if !slices.Contains(documented, route) {
+			t.Errorf("route %q is not documented", route)
+		}
+	}
+	for _, route := range documented {
+		if !slices.Contains(registered, route) {
+			t.Errorf("documented route %q is not registered", route)
+		}
+	}
+}
+
+func registeredRoutes(t *testing.T) []string {
+	t.Helper()
+
+	file, err := parser.ParseFile(token.NewFileSet(), "../routes.go", nil, 0)
+	testutils.AssertNoError(t, err)
+
+	var routes []string
+	ast.Inspect(file, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || (selector.Sel.Name != "Handle" && selector.Sel.Name != "HandleFunc") {
+			return true
+		}
+		literal, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || literal.Kind != token.STRING {
+			return true
+		}
+
+		pattern, err := strconv.Unquote(literal.Value)
+		testutils.AssertNoError(t, err)
+		if pattern == "/" {
+			return true
+		}
+		routes = append(routes, pathParam.ReplaceAllString(pattern, "{}"))
+		return true
+	})
+	return routes
+}
+
+func TestValidateResponse(t *testing.T) {
+	t.Parallel()
+
+	jsonHeader := http.Header{"Content-Type": {"application/json"}}
+	tag := `{"id":"1","name":"food","vaultID":"2","createdBy":"3","createdAt":"2025-01-01T00:00:00Z"}`
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		status  int
+		header  http.Header
+		body    string
+		wantErr string
+	}{
+		{name: "valid response", method: "GET", path: "/tags/abc", status: 200, header: jsonHeader, body: "[" + tag + "]"},
+		{name: "undocumented property", method: "GET", path: "/tags/abc", status: 200, header: jsonHeader, body: `[{"color":"red",` + tag[1:] + "]", wantErr: `undocumented property "color"`},
+		{name: "missing property", method: "GET", path: "/tags/abc", status: 200, header: jsonHeader, body: `[{"id":"1"}]`, wantErr: "missing required property"},
+		{name: "wrong type", method: "GET", path: "/tags/abc", status: 200, header: jsonHeader, body: `{}`, wantErr: "expected array"},
+		{name: "undocumented status", method: "GET", path: "/tags/abc", status: 409, header: jsonHeader, body: `{}`, wantErr: "status 409 is not documented"},
+		{name: "undocumented route", method: "GET", path: "/budgets", status: 200, header: jsonHeader, body: `{}`, wantErr: "is not documented"},
+		{name: "unexpected body", method: "DELETE", path: "/tags/abc", status: 204, header: http.Header{}, body: "{}", wantErr: "must not have a body"},
+		{name: "unknown route falls through to not found", method: "GET", path: "/budgets", status: 404, header: http.Header{"Content-Type": {"application/problem+json"}}, body: `{"type":"urn:x","title":"Not Found","status":404,"code":"not_found"}`},
+		{name: "literal segments win over parameters", method: "GET", path: "/vaults/trash", status: 200, header: jsonHeader, body: `[]`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := testutils.ValidateResponse(tc.method, tc.path, tc.status, tc.header, []byte(tc.body))
+
+			if tc.wantErr == "" {
+				testutils.AssertNoError(t, err)
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
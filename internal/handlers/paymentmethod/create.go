@@ -0,0 +1,59 @@
+package paymentmethod
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/utils"
+)
+
+var (
+	minPaymentMethodNameLength = 2
+	maxPaymentMethodNameLength = 50
+)
+
+func CreateOne(
+	logger *slog.Logger,
+	paymentMethodService *services.PaymentMethodService,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	type reqBody struct {
+		Name    string `json:"name"`
+		VaultID string `json:"vaultID"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		body, err := utils.Decode[reqBody](r)
+		if err != nil {
+			problem.Write(w, r, problem.InvalidBody())
+			return
+		}
+
+		err = validation.ValidateStruct(&body,
+			validation.Field(&body.Name, validation.Required, validation.Length(minPaymentMethodNameLength, maxPaymentMethodNameLength)),
+			validation.Field(&body.VaultID, validation.Required),
+		)
+		if err != nil {
+			problem.Write(w, r, problem.Validation(err))
+			return
+		}
+
+		err = paymentMethodService.CreateOne(r.Context(), user.ID, body.VaultID, body.Name)
+		if err != nil {
+			if errors.Is(err, services.ErrPaymentMethodWithThatNameAlreadyExists) {
+				problem.Write(w, r, problem.Field("name", err))
+				return
+			}
+
+			problem.Error(w, r, logger, err, "failed to create payment method", "vaultID", body.VaultID, "userID", user.ID)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,59 @@
+package paymentmethod_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestCreateOne(t *testing.T) {
+	t.Parallel()
+
+	t.Run("creates payment method", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+
+		request := httptest.NewRequest("POST", "/paymentmethods", testutils.ToJSONBuffer(t, map[string]string{"name": "credit card", "vaultID": vault.ID}))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNoContent)
+
+		paymentMethods, err := testutils.NewTestPaymentMethodService(db).FindAll(t.Context(), user.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(paymentMethods), 1)
+		testutils.AssertEqual(t, paymentMethods[0].Name, "credit card")
+	})
+
+	t.Run("returns 400 if payment method with that name already exists", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		paymentMethod := testutils.CreateTestPaymentMethod(t, db, user.ID, vault.ID)
+
+		request := httptest.NewRequest("POST", "/paymentmethods", testutils.ToJSONBuffer(t, map[string]string{"name": paymentMethod.Name, "vaultID": vault.ID}))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
+	})
+
+	t.Run("returns 404 if vault does not exist", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, _ := testutils.CreateTestUserWithToken(t, db)
+
+		request := httptest.NewRequest("POST", "/paymentmethods", testutils.ToJSONBuffer(t, map[string]string{"name": "cash", "vaultID": uuid.New().String()}))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNotFound)
+	})
+}
@@ -0,0 +1,27 @@
+package paymentmethod
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+)
+
+func DeleteOneByID(
+	logger *slog.Logger,
+	paymentMethodService *services.PaymentMethodService,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		paymentMethodID := r.PathValue("id")
+
+		err := paymentMethodService.DeleteOneByID(r.Context(), user.ID, paymentMethodID)
+		if err != nil {
+			problem.Error(w, r, logger, err, "failed to delete payment method", "paymentMethodID", paymentMethodID, "userID", user.ID)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,42 @@
+package paymentmethod_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestDeleteOneByID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("deletes unused payment method", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		paymentMethod := testutils.CreateTestPaymentMethod(t, db, user.ID, vault.ID)
+
+		request := httptest.NewRequest("DELETE", "/paymentmethods/"+paymentMethod.ID, nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNoContent)
+	})
+
+	t.Run("returns 409 if payment method is used by expenses", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		request := httptest.NewRequest("DELETE", "/paymentmethods/"+expense.PaymentMethodID, nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusConflict)
+	})
+}
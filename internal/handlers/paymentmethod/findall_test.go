@@ -0,0 +1,46 @@
+package paymentmethod_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestFindAll(t *testing.T) {
+	t.Parallel()
+
+	t.Run("finds all payment methods of the vault", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		paymentMethod := testutils.CreateTestPaymentMethod(t, db, user.ID, vault.ID)
+
+		request := httptest.NewRequest("GET", "/paymentmethods/"+vault.ID, nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+
+		paymentMethods := testutils.DecodeJSON[[]models.PaymentMethod](t, response.Body)
+		testutils.AssertEqual(t, len(paymentMethods), 1)
+		testutils.AssertEqual(t, paymentMethods[0].ID, paymentMethod.ID)
+	})
+
+	t.Run("returns 404 if user does not belong to vault", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, _ := testutils.CreateTestUserWithToken(t, db)
+		_, _, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+
+		request := httptest.NewRequest("GET", "/paymentmethods/"+vault.ID, nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNotFound)
+	})
+}
@@ -0,0 +1,65 @@
+package paymentmethod
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/utils"
+)
+
+func UpdateOne(
+	logger *slog.Logger,
+	paymentMethodService *services.PaymentMethodService,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	type reqBody struct {
+		Name   *string `json:"name"`
+		Status *string `json:"status"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		paymentMethodID := r.PathValue("id")
+
+		body, err := utils.Decode[reqBody](r)
+		if err != nil {
+			problem.Write(w, r, problem.InvalidBody())
+			return
+		}
+
+		err = validation.ValidateStruct(&body,
+			validation.Field(&body.Name, validation.NilOrNotEmpty, validation.Length(minPaymentMethodNameLength, maxPaymentMethodNameLength)),
+			validation.Field(&body.Status, validation.NilOrNotEmpty, validation.In(
+				string(models.PaymentMethodStatusActive),
+				string(models.PaymentMethodStatusArchived),
+			)),
+		)
+		if err != nil {
+			problem.Write(w, r, problem.Validation(err))
+			return
+		}
+
+		update := models.PaymentMethodUpdate{Name: body.Name} // nolint: exhaustruct
+		if body.Status != nil {
+			status := models.PaymentMethodStatus(*body.Status)
+			update.Status = &status
+		}
+
+		paymentMethod, err := paymentMethodService.UpdateOne(r.Context(), user.ID, paymentMethodID, update)
+		if err != nil {
+			if errors.Is(err, services.ErrPaymentMethodWithThatNameAlreadyExists) {
+				problem.Write(w, r, problem.Field("name", err))
+				return
+			}
+
+			problem.Error(w, r, logger, err, "failed to update payment method", "paymentMethodID", paymentMethodID, "userID", user.ID)
+			return
+		}
+
+		utils.Encode(w, http.StatusOK, paymentMethod)
+	}
+}
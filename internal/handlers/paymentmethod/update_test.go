@@ -0,0 +1,51 @@
+package paymentmethod_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestUpdateOne(t *testing.T) {
+	t.Parallel()
+
+	t.Run("renames and archives payment method", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		paymentMethod := testutils.CreateTestPaymentMethod(t, db, user.ID, vault.ID)
+
+		reqBody := map[string]string{"name": "old card", "status": string(models.PaymentMethodStatusArchived)}
+
+		request := httptest.NewRequest("PATCH", "/paymentmethods/"+paymentMethod.ID, testutils.ToJSONBuffer(t, reqBody))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+
+		updated := testutils.DecodeJSON[models.PaymentMethod](t, response.Body)
+		testutils.AssertEqual(t, updated.Name, "old card")
+		testutils.AssertEqual(t, updated.Status, models.PaymentMethodStatusArchived)
+	})
+
+	t.Run("returns 403 if user cannot manage payment methods", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		_, owner, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		viewerToken, viewer := testutils.CreateTestUserWithToken(t, db)
+		err := testutils.NewTestVaultService(db).AddUser(t.Context(), owner.ID, viewer.ID, vault.ID, models.VaultRoleViewer)
+		testutils.AssertNoError(t, err)
+		paymentMethod := testutils.CreateTestPaymentMethod(t, db, owner.ID, vault.ID)
+
+		request := httptest.NewRequest("PATCH", "/paymentmethods/"+paymentMethod.ID, testutils.ToJSONBuffer(t, map[string]string{"name": "renamed"}))
+		request.Header.Set("Authorization", "Bearer "+viewerToken)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusForbidden)
+	})
+}
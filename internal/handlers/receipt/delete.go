@@ -0,0 +1,27 @@
+package receipt
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+)
+
+func DeleteOneByID(
+	logger *slog.Logger,
+	receiptService *services.ReceiptService,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		receiptID := r.PathValue("id")
+
+		err := receiptService.DeleteOneByID(r.Context(), user.ID, receiptID)
+		if err != nil {
+			problem.Error(w, r, logger, err, "failed to delete receipt", "receiptID", receiptID, "userID", user.ID)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
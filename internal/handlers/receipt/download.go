@@ -0,0 +1,40 @@
+package receipt
+
+import (
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+)
+
+func Download(
+	logger *slog.Logger,
+	receiptService *services.ReceiptService,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		receiptID := r.PathValue("id")
+
+		receipt, file, err := receiptService.Open(r.Context(), user.ID, receiptID)
+		if err != nil {
+			problem.Error(w, r, logger, err, "failed to open receipt", "receiptID", receiptID, "userID", user.ID)
+			return
+		}
+		defer file.Close()
+
+		w.Header().Set("Content-Type", receipt.ContentType)
+		w.Header().Set("Content-Length", strconv.FormatInt(receipt.Size, 10))
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": receipt.FileName}))
+		w.Header().Set("ETag", `"`+receipt.Checksum+`"`)
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.WriteHeader(http.StatusOK)
+
+		if _, err := io.Copy(w, file); err != nil {
+			logger.ErrorContext(r.Context(), "failed to write receipt", "receiptID", receiptID, "userID", user.ID, "error", err)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package receipt
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/utils"
+)
+
+func FindAll(
+	logger *slog.Logger,
+	receiptService *services.ReceiptService,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		expenseID := r.PathValue("id")
+
+		receipts, err := receiptService.FindAll(r.Context(), user.ID, expenseID)
+		if err != nil {
+			problem.Error(w, r, logger, err, "failed to find receipts", "expenseID", expenseID, "userID", user.ID)
+			return
+		}
+
+		utils.Encode(w, http.StatusOK, receipts)
+	}
+}
@@ -0,0 +1,81 @@
+package receipt
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/utils"
+)
+
+const (
+	maxFileNameLength = 255
+	// multipartOverhead leaves room for part headers and boundaries on top of the file itself.
+	multipartOverhead = 64 << 10
+)
+
+func Upload(
+	logger *slog.Logger,
+	receiptService *services.ReceiptService,
+	maxSize int64,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		expenseID := r.PathValue("id")
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxSize+multipartOverhead)
+
+		mr, err := r.MultipartReader()
+		if err != nil {
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidBody, "request body must be multipart/form-data"))
+			return
+		}
+
+		var part io.Reader
+		var fileName string
+		for {
+			p, err := mr.NextPart()
+			if errors.Is(err, io.EOF) {
+				problem.Write(w, r, problem.Invalid("file", problem.CodeRequired, "file is required"))
+				return
+			}
+			if err != nil {
+				if isMaxBytesError(err) {
+					problem.Write(w, r, problem.FromError(services.ErrReceiptTooLarge))
+					return
+				}
+				problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidBody, "failed to read multipart body"))
+				return
+			}
+			if p.FormName() == "file" {
+				part, fileName = p, p.FileName()
+				break
+			}
+		}
+
+		if fileName == "" || len(fileName) > maxFileNameLength {
+			problem.Write(w, r, problem.Invalid("file", problem.CodeInvalid, "file name must be between 1 and 255 characters"))
+			return
+		}
+
+		receipt, err := receiptService.CreateOne(r.Context(), user.ID, expenseID, fileName, part)
+		if err != nil {
+			if isMaxBytesError(err) {
+				err = services.ErrReceiptTooLarge
+			}
+
+			problem.Error(w, r, logger, err, "failed to upload receipt", "expenseID", expenseID, "userID", user.ID)
+			return
+		}
+
+		utils.Encode(w, http.StatusOK, receipt)
+	}
+}
+
+func isMaxBytesError(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
@@ -0,0 +1,142 @@
+package receipt_test
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+var testPNG = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x06\x00\x00\x00")
+
+func newUploadRequest(t *testing.T, expenseID, token, fileName string, content []byte) *http.Request {
+	t.Helper()
+	body := new(bytes.Buffer)
+	mw := multipart.NewWriter(body)
+	fw, err := mw.CreateFormFile("file", fileName)
+	testutils.AssertNoError(t, err)
+	_, err = fw.Write(content)
+	testutils.AssertNoError(t, err)
+	testutils.AssertNoError(t, mw.Close())
+
+	request := httptest.NewRequest("POST", "/expenses/"+expenseID+"/receipts", body)
+	request.Header.Set("Content-Type", mw.FormDataContentType())
+	request.Header.Set("Authorization", "Bearer "+token)
+	return request
+}
+
+func TestUpload(t *testing.T) {
+	t.Parallel()
+
+	t.Run("uploads receipt and allows downloading it", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, newUploadRequest(t, expense.ID, token, "receipt.png", testPNG))
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+		receipt := testutils.DecodeJSON[models.Receipt](t, response.Body)
+		testutils.AssertEqual(t, receipt.ContentType, "image/png")
+		testutils.AssertEqual(t, receipt.ExpenseID, expense.ID)
+
+		request := httptest.NewRequest("GET", "/expenses/"+expense.ID+"/receipts", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response = httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+		receipts := testutils.DecodeJSON[[]models.Receipt](t, response.Body)
+		testutils.AssertEqual(t, len(receipts), 1)
+
+		request = httptest.NewRequest("GET", "/receipts/"+receipt.ID, nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response = httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+		testutils.AssertEqual(t, response.Header().Get("Content-Type"), "image/png")
+		testutils.AssertEqual(t, response.Header().Get("Content-Disposition"), `attachment; filename=receipt.png`)
+		testutils.AssertEqual(t, response.Header().Get("ETag"), `"`+receipt.Checksum+`"`)
+		body, err := io.ReadAll(response.Body)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, string(body), string(testPNG))
+	})
+
+	t.Run("returns 415 for unsupported file type", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, newUploadRequest(t, expense.ID, token, "receipt.png", []byte("just some text")))
+
+		testutils.AssertStatus(t, response.Code, http.StatusUnsupportedMediaType)
+	})
+
+	t.Run("returns 413 if file is too large", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		content := append(bytes.Clone(testPNG), make([]byte, testutils.TestReceiptMaxSize)...)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, newUploadRequest(t, expense.ID, token, "receipt.png", content))
+
+		testutils.AssertStatus(t, response.Code, http.StatusRequestEntityTooLarge)
+	})
+
+	t.Run("returns 400 if file is missing", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		body := new(bytes.Buffer)
+		mw := multipart.NewWriter(body)
+		testutils.AssertNoError(t, mw.WriteField("note", "no file here"))
+		testutils.AssertNoError(t, mw.Close())
+
+		request := httptest.NewRequest("POST", "/expenses/"+expense.ID+"/receipts", body)
+		request.Header.Set("Content-Type", mw.FormDataContentType())
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
+	})
+
+	t.Run("returns 404 when downloading receipt from vault user is not member of", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		otherToken, _ := testutils.CreateTestUserWithToken(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, newUploadRequest(t, expense.ID, token, "receipt.png", testPNG))
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+		receipt := testutils.DecodeJSON[models.Receipt](t, response.Body)
+
+		request := httptest.NewRequest("GET", "/receipts/"+receipt.ID, nil)
+		request.Header.Set("Authorization", "Bearer "+otherToken)
+		response = httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNotFound)
+	})
+}
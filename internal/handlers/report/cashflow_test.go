@@ -0,0 +1,63 @@
+package report_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestCashflow(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns net cashflow per month", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+		testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+		incomeService := testutils.NewTestIncomeService(db)
+
+		for _, income := range []models.Income{
+			{Source: "salary", Date: "2025-01-25", Amount: 100, VaultID: vault.ID}, // nolint: exhaustruct
+			{Source: "salary", Date: "2025-02-25", Amount: 120, VaultID: vault.ID}, // nolint: exhaustruct
+			{Source: "bonus", Date: "2025-02-26", Amount: 30, VaultID: vault.ID},   // nolint: exhaustruct
+		} {
+			err := incomeService.CreateOne(t.Context(), user.ID, income)
+			testutils.AssertNoError(t, err)
+		}
+
+		request := httptest.NewRequest("GET", "/reports/"+vault.ID+"/cashflow", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+
+		cashflow := testutils.DecodeJSON[[]models.MonthlyCashflow](t, response.Body)
+		testutils.AssertEqual(t, len(cashflow), 2)
+		testutils.AssertEqual(t, cashflow[0], models.MonthlyCashflow{Month: "2025-01", Income: 100, Expenses: 25, Net: 75})
+		testutils.AssertEqual(t, cashflow[1], models.MonthlyCashflow{Month: "2025-02", Income: 150, Expenses: 0, Net: 150})
+	})
+
+	t.Run("respects date range", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		request := httptest.NewRequest("GET", "/reports/"+vault.ID+"/cashflow?from=2025-02-01", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+
+		cashflow := testutils.DecodeJSON[[]models.MonthlyCashflow](t, response.Body)
+		testutils.AssertEqual(t, len(cashflow), 0)
+	})
+}
@@ -0,0 +1,61 @@
+package report_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestCategoryTotals(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns category totals", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		request := httptest.NewRequest("GET", "/reports/"+vault.ID+"/categories?from=2025-01-01&to=2025-12-31", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+
+		totals := testutils.DecodeJSON[[]models.CategoryTotal](t, response.Body)
+		testutils.AssertEqual(t, len(totals), 1)
+		testutils.AssertEqual(t, totals[0].CategoryID, category.ID)
+		testutils.AssertEqual(t, totals[0].RolledUpTotal, 12.5)
+	})
+
+	t.Run("returns 400 for invalid date", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, _, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+
+		request := httptest.NewRequest("GET", "/reports/"+vault.ID+"/categories?from=yesterday", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
+	})
+
+	t.Run("returns 404 if user does not belong to vault", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, _ := testutils.CreateTestUserWithToken(t, db)
+
+		request := httptest.NewRequest("GET", "/reports/"+uuid.New().String()+"/categories", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNotFound)
+	})
+}
@@ -0,0 +1,34 @@
+package report
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/utils"
+)
+
+func PaymentMethodTotals(
+	logger *slog.Logger,
+	reportService *services.ReportService,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		vaultID := r.PathValue("vaultID")
+
+		params, err := decodeDateRange(r)
+		if err != nil {
+			problem.Write(w, r, problem.Validation(err))
+			return
+		}
+
+		totals, err := reportService.PaymentMethodTotals(r.Context(), user.ID, vaultID, params.From, params.To)
+		if err != nil {
+			problem.Error(w, r, logger, err, "failed to create payment method totals report", "vaultID", vaultID, "userID", user.ID)
+			return
+		}
+
+		utils.Encode(w, http.StatusOK, totals)
+	}
+}
@@ -0,0 +1,47 @@
+package report_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestPaymentMethodTotals(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns payment method totals", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		request := httptest.NewRequest("GET", "/reports/"+vault.ID+"/payment-methods", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+
+		totals := testutils.DecodeJSON[[]models.PaymentMethodTotal](t, response.Body)
+		testutils.AssertEqual(t, len(totals), 1)
+		testutils.AssertEqual(t, totals[0].PaymentMethodID, expense.PaymentMethodID)
+		testutils.AssertEqual(t, totals[0].Total, 12.5)
+	})
+
+	t.Run("returns 400 for invalid date", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, _, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+
+		request := httptest.NewRequest("GET", "/reports/"+vault.ID+"/payment-methods?to=2025-13-01", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,27 @@
+package report
+
+import (
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+var reportDateLayout = "2006-01-02"
+
+type dateRange struct {
+	From string
+	To   string
+}
+
+func decodeDateRange(r *http.Request) (dateRange, error) {
+	params := dateRange{
+		From: r.URL.Query().Get("from"),
+		To:   r.URL.Query().Get("to"),
+	}
+
+	err := validation.ValidateStruct(&params,
+		validation.Field(&params.From, validation.Date(reportDateLayout)),
+		validation.Field(&params.To, validation.Date(reportDateLayout)),
+	)
+	return params, err
+}
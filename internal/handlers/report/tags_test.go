@@ -0,0 +1,46 @@
+package report_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestTagTotals(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns totals per tag", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		used := testutils.CreateTestTag(t, db, user.ID, vault.ID)
+		unused := testutils.CreateTestTag(t, db, user.ID, vault.ID)
+		expenseService := testutils.NewTestExpenseService(db)
+
+		for range 2 {
+			expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+			_, err := expenseService.UpdateOne(t.Context(), user.ID, expense.ID, models.ExpenseUpdate{TagIDs: &[]string{used.ID}}) // nolint: exhaustruct
+			testutils.AssertNoError(t, err)
+		}
+
+		request := httptest.NewRequest("GET", "/reports/"+vault.ID+"/tags", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+
+		totals := testutils.DecodeJSON[[]models.TagTotal](t, response.Body)
+		testutils.AssertEqual(t, len(totals), 2)
+		byTag := map[string]models.TagTotal{}
+		for _, total := range totals {
+			byTag[total.TagID] = total
+		}
+		testutils.AssertEqual(t, byTag[used.ID], models.TagTotal{TagID: used.ID, Name: used.Name, Count: 2, Total: 25})
+		testutils.AssertEqual(t, byTag[unused.ID], models.TagTotal{TagID: unused.ID, Name: unused.Name, Count: 0, Total: 0})
+	})
+}
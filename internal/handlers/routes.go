@@ -5,11 +5,23 @@ import (
 	"net/http"
 
 	"github.com/kkstas/tr-backend/internal/config"
+	"github.com/kkstas/tr-backend/internal/handlers/account"
+	"github.com/kkstas/tr-backend/internal/handlers/admin"
+	"github.com/kkstas/tr-backend/internal/handlers/expense"
 	"github.com/kkstas/tr-backend/internal/handlers/expensecategory"
+	"github.com/kkstas/tr-backend/internal/handlers/income"
 	"github.com/kkstas/tr-backend/internal/handlers/misc"
+	"github.com/kkstas/tr-backend/internal/handlers/openapi"
+	"github.com/kkstas/tr-backend/internal/handlers/paymentmethod"
+	"github.com/kkstas/tr-backend/internal/handlers/receipt"
+	"github.com/kkstas/tr-backend/internal/handlers/report"
 	"github.com/kkstas/tr-backend/internal/handlers/session"
+	"github.com/kkstas/tr-backend/internal/handlers/settlement"
+	"github.com/kkstas/tr-backend/internal/handlers/tag"
+	"github.com/kkstas/tr-backend/internal/handlers/transfer"
 	"github.com/kkstas/tr-backend/internal/handlers/user"
 	"github.com/kkstas/tr-backend/internal/handlers/vault"
+	"github.com/kkstas/tr-backend/internal/metrics"
 	mw "github.com/kkstas/tr-backend/internal/middleware"
 	"github.com/kkstas/tr-backend/internal/services"
 )
@@ -19,7 +31,20 @@ func SetupRoutes(
 	logger *slog.Logger,
 	userService *services.UserService,
 	vaultService *services.VaultService,
+	auditService *services.AuditService,
 	expenseCategoryService *services.ExpenseCategoryService,
+	paymentMethodService *services.PaymentMethodService,
+	accountService *services.AccountService,
+	transferService *services.TransferService,
+	tagService *services.TagService,
+	incomeService *services.IncomeService,
+	expenseService *services.ExpenseService,
+	receiptService *services.ReceiptService,
+	settlementService *services.SettlementService,
+	reportService *services.ReportService,
+	backupService *services.BackupService,
+	m *metrics.Metrics,
+	readinessChecks []misc.ReadinessCheck,
 ) http.Handler {
 	mux := http.NewServeMux()
 
@@ -27,20 +52,87 @@ func SetupRoutes(
 	withUser := mw.WithUser(logger, userService)
 
 	mux.HandleFunc("GET /health-check", misc.HealthCheckHandler)
+	mux.HandleFunc("GET /health/live", misc.HealthCheckHandler)
+	mux.Handle("GET /health/ready", misc.ReadinessHandler(logger, readinessChecks))
+	mux.HandleFunc("GET /openapi.json", openapi.Document)
+	mux.Handle("GET /metrics", mw.Enable(cfg.EnableMetrics, m.Handler()))
 	mux.HandleFunc("/", misc.NotFoundHandler)
 
-	mux.Handle("POST /login", session.LoginHandler(cfg.JWTSecretKey, logger, userService))
+	mux.Handle("POST /admin/backups", mw.RequireAdminToken(cfg.AdminToken, admin.CreateBackup(logger, backupService)))
+
+	mux.Handle("POST /login", session.LoginHandler(cfg.JWTSecretKey, logger, userService, m))
 	mux.Handle("POST /register", mw.Enable(cfg.EnableRegister, session.RegisterHandler(logger, userService)))
 
 	mux.Handle("GET /user", requireAuth(withUser(user.GetUserInfo())))
 
-	mux.Handle("POST /vaults", requireAuth(withUser(vault.CreateOne(vaultService))))
-	mux.Handle("GET /vaults", requireAuth(withUser(vault.FindAll(vaultService))))
+	mux.Handle("POST /vaults", requireAuth(withUser(vault.CreateOne(logger, vaultService))))
+	mux.Handle("GET /vaults", requireAuth(withUser(vault.FindAll(logger, vaultService))))
+	mux.Handle("GET /vaults/trash", requireAuth(withUser(vault.FindAllDeleted(logger, vaultService, cfg.TrashRetention))))
+	mux.Handle("PATCH /vaults/{vaultID}", requireAuth(withUser(vault.UpdateOne(logger, vaultService))))
 	mux.Handle("DELETE /vaults/{id}", requireAuth(withUser(vault.DeleteOneByID(logger, vaultService))))
-	mux.Handle("POST /vaults/{vaultID}/users", requireAuth(withUser(vault.AddUser(vaultService))))
+	mux.Handle("POST /vaults/{vaultID}/restore", requireAuth(withUser(vault.RestoreOneByID(logger, vaultService, cfg.TrashRetention))))
+	mux.Handle("GET /vaults/{vaultID}/search", requireAuth(withUser(expense.Search(logger, expenseService))))
+	mux.Handle("GET /vaults/{vaultID}/activity", requireAuth(withUser(vault.Activity(logger, auditService))))
+	mux.Handle("GET /vaults/{vaultID}/users", requireAuth(withUser(vault.FindMembers(logger, vaultService))))
+	mux.Handle("POST /vaults/{vaultID}/users", requireAuth(withUser(vault.AddUser(logger, vaultService))))
+	mux.Handle("POST /vaults/{vaultID}/transfer-ownership", requireAuth(withUser(vault.TransferOwnership(logger, vaultService))))
+
+	mux.Handle("GET /expensecategories/{vaultID}", requireAuth(withUser(expensecategory.FindAll(logger, expenseCategoryService))))
+	mux.Handle("GET /expensecategories/{vaultID}/tree", requireAuth(withUser(expensecategory.FindTree(logger, expenseCategoryService))))
+	mux.Handle("POST /expensecategories", requireAuth(withUser(expensecategory.CreateOne(logger, expenseCategoryService))))
+	mux.Handle("PATCH /expensecategories/{id}", requireAuth(withUser(expensecategory.UpdateOne(logger, expenseCategoryService))))
+	mux.Handle("PUT /expensecategories/{vaultID}/order", requireAuth(withUser(expensecategory.Reorder(logger, expenseCategoryService))))
+	mux.Handle("POST /expensecategories/{id}/merge", requireAuth(withUser(expensecategory.Merge(logger, expenseCategoryService))))
+	mux.Handle("GET /expensecategory-templates", requireAuth(withUser(expensecategory.FindTemplates(logger))))
+	mux.Handle("POST /expensecategories/{vaultID}/apply-template", requireAuth(withUser(expensecategory.ApplyTemplate(logger, expenseCategoryService))))
+
+	mux.Handle("GET /paymentmethods/{vaultID}", requireAuth(withUser(paymentmethod.FindAll(logger, paymentMethodService))))
+	mux.Handle("POST /paymentmethods", requireAuth(withUser(paymentmethod.CreateOne(logger, paymentMethodService))))
+	mux.Handle("PATCH /paymentmethods/{id}", requireAuth(withUser(paymentmethod.UpdateOne(logger, paymentMethodService))))
+	mux.Handle("DELETE /paymentmethods/{id}", requireAuth(withUser(paymentmethod.DeleteOneByID(logger, paymentMethodService))))
+
+	mux.Handle("GET /accounts/{vaultID}", requireAuth(withUser(account.FindAll(logger, accountService))))
+	mux.Handle("POST /accounts", requireAuth(withUser(account.CreateOne(logger, accountService))))
+	mux.Handle("PATCH /accounts/{id}", requireAuth(withUser(account.UpdateOne(logger, accountService))))
+	mux.Handle("DELETE /accounts/{id}", requireAuth(withUser(account.DeleteOneByID(logger, accountService))))
+	mux.Handle("GET /accounts/{id}/ledger", requireAuth(withUser(account.Ledger(logger, accountService))))
+
+	mux.Handle("GET /transfers/{vaultID}", requireAuth(withUser(transfer.FindAll(logger, transferService))))
+	mux.Handle("POST /transfers", requireAuth(withUser(transfer.CreateOne(logger, transferService))))
+	mux.Handle("DELETE /transfers/{id}", requireAuth(withUser(transfer.DeleteOneByID(logger, transferService))))
+
+	mux.Handle("GET /tags/{vaultID}", requireAuth(withUser(tag.FindAll(logger, tagService))))
+	mux.Handle("POST /tags", requireAuth(withUser(tag.CreateOne(logger, tagService))))
+	mux.Handle("PATCH /tags/{id}", requireAuth(withUser(tag.UpdateOne(logger, tagService))))
+	mux.Handle("DELETE /tags/{id}", requireAuth(withUser(tag.DeleteOneByID(logger, tagService))))
+
+	mux.Handle("GET /incomes/{vaultID}", requireAuth(withUser(income.FindAll(logger, incomeService))))
+	mux.Handle("POST /incomes", requireAuth(withUser(income.CreateOne(logger, incomeService))))
+	mux.Handle("PATCH /incomes/{id}", requireAuth(withUser(income.UpdateOne(logger, incomeService))))
+	mux.Handle("DELETE /incomes/{id}", requireAuth(withUser(income.DeleteOneByID(logger, incomeService))))
+
+	mux.Handle("GET /expenses/{vaultID}", requireAuth(withUser(expense.FindAll(logger, expenseService))))
+	mux.Handle("GET /expenses/{vaultID}/trash", requireAuth(withUser(expense.FindAllDeleted(logger, expenseService, cfg.TrashRetention))))
+	mux.Handle("POST /expenses", requireAuth(withUser(expense.CreateOne(logger, expenseService, m))))
+	mux.Handle("PATCH /expenses/{id}", requireAuth(withUser(expense.UpdateOne(logger, expenseService))))
+	mux.Handle("DELETE /expenses/{id}", requireAuth(withUser(expense.DeleteOneByID(logger, expenseService))))
+	mux.Handle("POST /expenses/{id}/restore", requireAuth(withUser(expense.RestoreOneByID(logger, expenseService, cfg.TrashRetention))))
+
+	mux.Handle("GET /expenses/{id}/receipts", requireAuth(withUser(receipt.FindAll(logger, receiptService))))
+	mux.Handle("POST /expenses/{id}/receipts", requireAuth(withUser(receipt.Upload(logger, receiptService, cfg.ReceiptMaxSize))))
+	mux.Handle("GET /receipts/{id}", requireAuth(withUser(receipt.Download(logger, receiptService))))
+	mux.Handle("DELETE /receipts/{id}", requireAuth(withUser(receipt.DeleteOneByID(logger, receiptService))))
+
+	mux.Handle("GET /settlements/{vaultID}", requireAuth(withUser(settlement.FindAll(logger, settlementService))))
+	mux.Handle("GET /settlements/{vaultID}/balances", requireAuth(withUser(settlement.Balances(logger, settlementService))))
+	mux.Handle("GET /settlements/{vaultID}/suggestions", requireAuth(withUser(settlement.Suggestions(logger, settlementService))))
+	mux.Handle("POST /settlements", requireAuth(withUser(settlement.CreateOne(logger, settlementService))))
+	mux.Handle("DELETE /settlements/{id}", requireAuth(withUser(settlement.DeleteOneByID(logger, settlementService))))
 
-	mux.Handle("GET /expensecategories/{vaultID}", requireAuth(withUser(expensecategory.FindAll(expenseCategoryService))))
-	mux.Handle("POST /expensecategories", requireAuth(withUser(expensecategory.CreateOne(expenseCategoryService))))
+	mux.Handle("GET /reports/{vaultID}/categories", requireAuth(withUser(report.CategoryTotals(logger, reportService))))
+	mux.Handle("GET /reports/{vaultID}/payment-methods", requireAuth(withUser(report.PaymentMethodTotals(logger, reportService))))
+	mux.Handle("GET /reports/{vaultID}/tags", requireAuth(withUser(report.TagTotals(logger, reportService))))
+	mux.Handle("GET /reports/{vaultID}/cashflow", requireAuth(withUser(report.Cashflow(logger, reportService))))
 
 	return mux
 }
@@ -9,11 +9,13 @@ import (
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 
 	"github.com/kkstas/tr-backend/internal/auth"
+	"github.com/kkstas/tr-backend/internal/metrics"
+	"github.com/kkstas/tr-backend/internal/problem"
 	"github.com/kkstas/tr-backend/internal/services"
 	"github.com/kkstas/tr-backend/internal/utils"
 )
 
-func LoginHandler(jwtSecretKey []byte, logger *slog.Logger, userService *services.UserService) http.Handler {
+func LoginHandler(jwtSecretKey []byte, logger *slog.Logger, userService *services.UserService, m *metrics.Metrics) http.Handler {
 	type loginData struct {
 		Email    string `json:"email"`
 		Password string `json:"password"`
@@ -22,9 +24,8 @@ func LoginHandler(jwtSecretKey []byte, logger *slog.Logger, userService *service
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		body, err := utils.Decode[loginData](r)
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
+			problem.Write(w, r, problem.InvalidBody())
 			return
-
 		}
 
 		err = validation.ValidateStruct(
@@ -33,33 +34,32 @@ func LoginHandler(jwtSecretKey []byte, logger *slog.Logger, userService *service
 			validation.Field(&body.Email, validation.Required, is.EmailFormat),
 		)
 		if err != nil {
-			utils.Encode(w, http.StatusBadRequest, err)
+			problem.Write(w, r, problem.Validation(err))
 			return
 		}
 
 		passwordHash, userID, err := userService.FindPasswordHashAndUserIDForEmail(r.Context(), body.Email)
 		if err != nil {
 			if errors.Is(err, services.ErrUserNotFound) {
-				w.WriteHeader(http.StatusNotFound)
-				return
+				m.LoginFailed()
 			}
-			logger.Error("failed to find password hash and user ID for email", "email", body.Email, "error", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			problem.Error(w, r, logger, err, "failed to find password hash and user ID for email", "email", body.Email)
 			return
 		}
 
 		if !auth.CheckPassword(passwordHash, body.Password) {
-			w.WriteHeader(http.StatusUnauthorized)
+			m.LoginFailed()
+			problem.Write(w, r, problem.New(http.StatusUnauthorized, problem.CodeInvalidLogin, "invalid email or password"))
 			return
 		}
 
 		token, err := auth.CreateToken(jwtSecretKey, userID)
 		if err != nil {
-			logger.Error("failed to create token", "error", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			problem.Error(w, r, logger, err, "failed to create token")
 			return
 		}
 
+		m.LoginSucceeded()
 		utils.Encode(w, http.StatusOK, token)
 	})
 }
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/kkstas/tr-backend/internal/auth"
+	"github.com/kkstas/tr-backend/internal/problem"
 	"github.com/kkstas/tr-backend/internal/testutils"
 )
 
@@ -86,10 +87,7 @@ func TestLogin(t *testing.T) {
 				serv.ServeHTTP(response, request)
 				testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
 
-				var m map[string]string
-				if err := json.NewDecoder(response.Body).Decode(&m); err != nil {
-					t.Fatalf("Failed to decode response body: %v", err)
-				}
+				m := testutils.DecodeFieldErrors(t, response.Body)
 				testutils.AssertNotEmpty(t, m[tc.key])
 			})
 		}
@@ -108,5 +106,7 @@ func TestLogin(t *testing.T) {
 		response := httptest.NewRecorder()
 		serv.ServeHTTP(response, request)
 		testutils.AssertStatus(t, response.Code, http.StatusNotFound)
+		testutils.AssertEqual(t, response.Header().Get("Content-Type"), "application/problem+json")
+		testutils.AssertEqual(t, testutils.DecodeJSON[problem.Problem](t, response.Body).Code, "user_not_found")
 	})
 }
@@ -8,6 +8,7 @@ import (
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 
+	"github.com/kkstas/tr-backend/internal/problem"
 	"github.com/kkstas/tr-backend/internal/services"
 	"github.com/kkstas/tr-backend/internal/utils"
 )
@@ -30,7 +31,7 @@ func RegisterHandler(logger *slog.Logger, userService *services.UserService) htt
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		body, err := utils.Decode[reqBody](r)
 		if err != nil {
-			utils.Encode(w, http.StatusBadRequest, map[string]string{"message": "failed to decode request body"})
+			problem.Write(w, r, problem.InvalidBody())
 			return
 		}
 
@@ -41,18 +42,17 @@ func RegisterHandler(logger *slog.Logger, userService *services.UserService) htt
 			validation.Field(&body.Password, validation.Required, validation.Length(minPasswordLength, maxPasswordLength)),
 		)
 		if err != nil {
-			utils.Encode(w, http.StatusBadRequest, err)
+			problem.Write(w, r, problem.Validation(err))
 			return
 		}
 
 		err = userService.CreateOne(r.Context(), body.FirstName, body.LastName, body.Email, body.Password)
 		if err != nil {
 			if errors.Is(err, services.ErrUserEmailAlreadyExists) {
-				utils.Encode(w, http.StatusBadRequest, map[string]string{"email": "user with that email already exists"})
+				problem.Write(w, r, problem.Field("email", err))
 				return
 			}
-			logger.Error("failed to create user", "error", err)
-			w.WriteHeader(http.StatusInternalServerError)
+			problem.Error(w, r, logger, err, "failed to create user")
 			return
 		}
 
@@ -2,13 +2,12 @@ package session_test
 
 import (
 	"context"
-	"encoding/json"
 	"net/http"
 	"net/http/httptest"
-	"strings"
 	"testing"
 
 	"github.com/kkstas/tr-backend/internal/config"
+	"github.com/kkstas/tr-backend/internal/problem"
 	"github.com/kkstas/tr-backend/internal/testutils"
 )
 
@@ -56,7 +55,9 @@ func TestRegister(t *testing.T) {
 		serv.ServeHTTP(response, request)
 
 		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
-		testutils.AssertEqual(t, strings.TrimSpace(response.Body.String()), `{"message":"failed to decode request body"}`)
+		testutils.AssertEqual(t, response.Header().Get("Content-Type"), problem.ContentType)
+		p := testutils.DecodeJSON[problem.Problem](t, response.Body)
+		testutils.AssertEqual(t, p.Code, problem.CodeInvalidBody)
 	})
 
 	t.Run("should return 400 with correct response body if user with provided email already exists", func(t *testing.T) {
@@ -90,7 +91,8 @@ func TestRegister(t *testing.T) {
 		serv.ServeHTTP(response, request)
 
 		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
-		testutils.AssertEqual(t, strings.TrimSpace(response.Body.String()), `{"email":"user with that email already exists"}`)
+		errs := testutils.DecodeFieldErrors(t, response.Body)
+		testutils.AssertEqual(t, errs["email"], "user with that email already exists")
 	})
 
 	t.Run("should reject invalid request properties", func(t *testing.T) {
@@ -161,10 +163,7 @@ func TestRegister(t *testing.T) {
 				serv.ServeHTTP(response, request)
 				testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
 
-				var m map[string]string
-				if err := json.NewDecoder(response.Body).Decode(&m); err != nil {
-					t.Fatalf("Failed to decode response body: %v", err)
-				}
+				m := testutils.DecodeFieldErrors(t, response.Body)
 				testutils.AssertNotEmpty(t, m[tc.key])
 			})
 		}
@@ -0,0 +1,28 @@
+package settlement
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/utils"
+)
+
+func Balances(
+	logger *slog.Logger,
+	settlementService *services.SettlementService,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		vaultID := r.PathValue("vaultID")
+
+		balances, err := settlementService.Balances(r.Context(), user.ID, vaultID)
+		if err != nil {
+			problem.Error(w, r, logger, err, "failed to compute balances", "vaultID", vaultID, "userID", user.ID)
+			return
+		}
+
+		utils.Encode(w, http.StatusOK, balances)
+	}
+}
@@ -0,0 +1,68 @@
+package settlement
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/utils"
+)
+
+var settlementDateLayout = "2006-01-02"
+
+func CreateOne(
+	logger *slog.Logger,
+	settlementService *services.SettlementService,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	type reqBody struct {
+		FromUserID string  `json:"fromUserID"`
+		ToUserID   string  `json:"toUserID"`
+		Amount     float64 `json:"amount"`
+		Date       string  `json:"date"`
+		VaultID    string  `json:"vaultID"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		body, err := utils.Decode[reqBody](r)
+		if err != nil {
+			problem.Write(w, r, problem.InvalidBody())
+			return
+		}
+
+		err = validation.ValidateStruct(&body,
+			validation.Field(&body.FromUserID, validation.Required),
+			validation.Field(&body.ToUserID, validation.Required),
+			validation.Field(&body.Amount, validation.Required, validation.Min(0.01)),
+			validation.Field(&body.Date, validation.Required, validation.Date(settlementDateLayout)),
+			validation.Field(&body.VaultID, validation.Required),
+		)
+		if err != nil {
+			problem.Write(w, r, problem.Validation(err))
+			return
+		}
+
+		err = settlementService.CreateOne(r.Context(), user.ID, models.Settlement{ // nolint: exhaustruct
+			FromUserID: body.FromUserID,
+			ToUserID:   body.ToUserID,
+			Amount:     body.Amount,
+			Date:       body.Date,
+			VaultID:    body.VaultID,
+		})
+		if err != nil {
+			if errors.Is(err, services.ErrSettlementWithSelf) {
+				problem.Write(w, r, problem.Field("toUserID", err))
+				return
+			}
+
+			problem.Error(w, r, logger, err, "failed to create settlement", "vaultID", body.VaultID, "userID", user.ID)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
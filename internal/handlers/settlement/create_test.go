@@ -0,0 +1,76 @@
+package settlement_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestCreateOne(t *testing.T) {
+	t.Parallel()
+
+	t.Run("records settlement and updates balances", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		roommate := testutils.CreateTestUser(t, db)
+		err := testutils.NewTestVaultService(db).AddUser(t.Context(), user.ID, roommate.ID, vault.ID, models.VaultRoleEditor)
+		testutils.AssertNoError(t, err)
+
+		reqBody := map[string]any{"fromUserID": roommate.ID, "toUserID": user.ID, "amount": 25, "date": "2025-03-05", "vaultID": vault.ID}
+
+		request := httptest.NewRequest("POST", "/settlements", testutils.ToJSONBuffer(t, reqBody))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNoContent)
+
+		request = httptest.NewRequest("GET", "/settlements/"+vault.ID+"/balances", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response = httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+
+		balances := testutils.DecodeJSON[[]models.MemberBalance](t, response.Body)
+		testutils.AssertEqual(t, len(balances), 2)
+		for _, balance := range balances {
+			if balance.UserID == roommate.ID {
+				testutils.AssertEqual(t, balance.Balance, 25.0)
+			} else {
+				testutils.AssertEqual(t, balance.Balance, -25.0)
+			}
+		}
+
+		request = httptest.NewRequest("GET", "/settlements/"+vault.ID+"/suggestions", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response = httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+
+		suggestions := testutils.DecodeJSON[[]models.SettlementSuggestion](t, response.Body)
+		testutils.AssertEqual(t, len(suggestions), 1)
+		testutils.AssertEqual(t, suggestions[0], models.SettlementSuggestion{FromUserID: user.ID, ToUserID: roommate.ID, Amount: 25})
+	})
+
+	t.Run("returns 400 if user is not a vault member", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		stranger := testutils.CreateTestUser(t, db)
+
+		reqBody := map[string]any{"fromUserID": stranger.ID, "toUserID": user.ID, "amount": 25, "date": "2025-03-05", "vaultID": vault.ID}
+
+		request := httptest.NewRequest("POST", "/settlements", testutils.ToJSONBuffer(t, reqBody))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,27 @@
+package settlement
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+)
+
+func DeleteOneByID(
+	logger *slog.Logger,
+	settlementService *services.SettlementService,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		settlementID := r.PathValue("id")
+
+		err := settlementService.DeleteOneByID(r.Context(), user.ID, settlementID)
+		if err != nil {
+			problem.Error(w, r, logger, err, "failed to delete settlement", "settlementID", settlementID, "userID", user.ID)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,52 @@
+package tag_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestCreateOne(t *testing.T) {
+	t.Parallel()
+
+	t.Run("creates tag", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, _, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+
+		request := httptest.NewRequest("POST", "/tags", testutils.ToJSONBuffer(t, map[string]any{"name": "vacation 2026", "vaultID": vault.ID}))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNoContent)
+
+		request = httptest.NewRequest("GET", "/tags/"+vault.ID, nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response = httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+
+		tags := testutils.DecodeJSON[[]models.Tag](t, response.Body)
+		testutils.AssertEqual(t, len(tags), 1)
+		testutils.AssertEqual(t, tags[0].Name, "vacation 2026")
+	})
+
+	t.Run("returns 400 if tag with that name already exists", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		tag := testutils.CreateTestTag(t, db, user.ID, vault.ID)
+
+		request := httptest.NewRequest("POST", "/tags", testutils.ToJSONBuffer(t, map[string]any{"name": tag.Name, "vaultID": vault.ID}))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
+	})
+}
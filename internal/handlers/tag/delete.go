@@ -0,0 +1,27 @@
+package tag
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+)
+
+func DeleteOneByID(
+	logger *slog.Logger,
+	tagService *services.TagService,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		tagID := r.PathValue("id")
+
+		err := tagService.DeleteOneByID(r.Context(), user.ID, tagID)
+		if err != nil {
+			problem.Error(w, r, logger, err, "failed to delete tag", "tagID", tagID, "userID", user.ID)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
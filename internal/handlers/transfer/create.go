@@ -0,0 +1,78 @@
+package transfer
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/utils"
+)
+
+var (
+	maxTransferDescriptionLength = 100
+	transferDateLayout           = "2006-01-02"
+)
+
+func CreateOne(
+	logger *slog.Logger,
+	transferService *services.TransferService,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	type reqBody struct {
+		FromAccountID string  `json:"fromAccountID"`
+		ToAccountID   string  `json:"toAccountID"`
+		Amount        float64 `json:"amount"`
+		Date          string  `json:"date"`
+		Description   string  `json:"description"`
+		VaultID       string  `json:"vaultID"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		body, err := utils.Decode[reqBody](r)
+		if err != nil {
+			problem.Write(w, r, problem.InvalidBody())
+			return
+		}
+
+		err = validation.ValidateStruct(&body,
+			validation.Field(&body.FromAccountID, validation.Required),
+			validation.Field(&body.ToAccountID, validation.Required),
+			validation.Field(&body.Amount, validation.Required, validation.Min(0.01)),
+			validation.Field(&body.Date, validation.Required, validation.Date(transferDateLayout)),
+			validation.Field(&body.Description, validation.Length(0, maxTransferDescriptionLength)),
+			validation.Field(&body.VaultID, validation.Required),
+		)
+		if err != nil {
+			problem.Write(w, r, problem.Validation(err))
+			return
+		}
+
+		err = transferService.CreateOne(r.Context(), user.ID, models.Transfer{ // nolint: exhaustruct
+			FromAccountID: body.FromAccountID,
+			ToAccountID:   body.ToAccountID,
+			Amount:        body.Amount,
+			Date:          body.Date,
+			Description:   body.Description,
+			VaultID:       body.VaultID,
+		})
+		if err != nil {
+			if errors.Is(err, services.ErrTransferToSameAccount) {
+				problem.Write(w, r, problem.Field("toAccountID", err))
+				return
+			}
+			if errors.Is(err, services.ErrAccountNotFound) {
+				problem.Write(w, r, problem.BadRequest(err))
+				return
+			}
+
+			problem.Error(w, r, logger, err, "failed to create transfer", "vaultID", body.VaultID, "userID", user.ID)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
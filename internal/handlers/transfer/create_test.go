@@ -0,0 +1,59 @@
+package transfer_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestCreateOne(t *testing.T) {
+	t.Parallel()
+
+	t.Run("creates transfer between accounts", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		from := testutils.CreateTestAccount(t, db, user.ID, vault.ID, 100)
+		to := testutils.CreateTestAccount(t, db, user.ID, vault.ID, 0)
+
+		reqBody := map[string]any{"fromAccountID": from.ID, "toAccountID": to.ID, "amount": 30, "date": "2025-01-05", "description": "savings", "vaultID": vault.ID}
+
+		request := httptest.NewRequest("POST", "/transfers", testutils.ToJSONBuffer(t, reqBody))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNoContent)
+
+		request = httptest.NewRequest("GET", "/transfers/"+vault.ID, nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response = httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+
+		transfers := testutils.DecodeJSON[[]models.Transfer](t, response.Body)
+		testutils.AssertEqual(t, len(transfers), 1)
+		testutils.AssertEqual(t, transfers[0].Amount, 30.0)
+		testutils.AssertEqual(t, transfers[0].Description, "savings")
+	})
+
+	t.Run("returns 400 when transferring to the same account", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		account := testutils.CreateTestAccount(t, db, user.ID, vault.ID, 100)
+
+		reqBody := map[string]any{"fromAccountID": account.ID, "toAccountID": account.ID, "amount": 30, "date": "2025-01-05", "vaultID": vault.ID}
+
+		request := httptest.NewRequest("POST", "/transfers", testutils.ToJSONBuffer(t, reqBody))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
+	})
+}
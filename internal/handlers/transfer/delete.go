@@ -0,0 +1,27 @@
+package transfer
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+)
+
+func DeleteOneByID(
+	logger *slog.Logger,
+	transferService *services.TransferService,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		transferID := r.PathValue("id")
+
+		err := transferService.DeleteOneByID(r.Context(), user.ID, transferID)
+		if err != nil {
+			problem.Error(w, r, logger, err, "failed to delete transfer", "transferID", transferID, "userID", user.ID)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,62 @@
+package vault
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/utils"
+)
+
+var (
+	defaultActivityLimit = 50
+	maxActivityLimit     = 100
+)
+
+func Activity(logger *slog.Logger, auditService *services.AuditService) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	type queryParams struct {
+		Limit int `json:"limit"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		vaultID := r.PathValue("vaultID")
+		query := r.URL.Query()
+
+		params := queryParams{Limit: defaultActivityLimit}
+		if val := query.Get("limit"); val != "" {
+			limit, err := strconv.Atoi(val)
+			if err != nil {
+				problem.Write(w, r, problem.Invalid("limit", problem.CodeInvalid, "must be a number"))
+				return
+			}
+			params.Limit = limit
+		}
+
+		err := validation.ValidateStruct(&params,
+			validation.Field(&params.Limit, validation.Required, validation.Min(1), validation.Max(maxActivityLimit)),
+		)
+		if err != nil {
+			problem.Write(w, r, problem.Validation(err))
+			return
+		}
+
+		page, err := auditService.FindAll(r.Context(), user.ID, vaultID, query.Get("entityID"), query.Get("cursor"), params.Limit)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidAuditCursor) {
+				problem.Write(w, r, problem.Field("cursor", err))
+				return
+			}
+
+			problem.Error(w, r, logger, err, "failed to find vault activity", "vaultID", vaultID, "userID", user.ID)
+			return
+		}
+
+		utils.Encode(w, http.StatusOK, page)
+	}
+}
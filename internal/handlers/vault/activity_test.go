@@ -0,0 +1,66 @@
+package vault_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestActivity(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns vault activity page", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+
+		request := httptest.NewRequest("PATCH", "/vaults/"+vault.ID, testutils.ToJSONBuffer(t, map[string]any{"description": "shared flat expenses"}))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+
+		request = httptest.NewRequest("GET", "/vaults/"+vault.ID+"/activity?limit=10", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response = httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+
+		page := testutils.DecodeJSON[models.AuditEventPage](t, response.Body)
+		testutils.AssertEqual(t, len(page.Events), 1)
+		testutils.AssertEqual(t, page.Events[0].Action, models.AuditActionUpdate)
+		testutils.AssertEqual(t, page.Events[0].EntityType, models.AuditEntityVault)
+		testutils.AssertEqual(t, page.Events[0].ActorID, user.ID)
+	})
+
+	t.Run("returns 400 for invalid cursor", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, _, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+
+		request := httptest.NewRequest("GET", "/vaults/"+vault.ID+"/activity?cursor=abc", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
+	})
+
+	t.Run("returns 404 if user is not a member of the vault", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		_, _, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		otherToken, _ := testutils.CreateTestUserWithToken(t, db)
+
+		request := httptest.NewRequest("GET", "/vaults/"+vault.ID+"/activity", nil)
+		request.Header.Set("Authorization", "Bearer "+otherToken)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNotFound)
+	})
+}
@@ -2,16 +2,19 @@ package vault
 
 import (
 	"errors"
+	"log/slog"
 	"net/http"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 
 	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
 	"github.com/kkstas/tr-backend/internal/services"
 	"github.com/kkstas/tr-backend/internal/utils"
 )
 
 func AddUser(
+	logger *slog.Logger,
 	vaultService *services.VaultService,
 ) func(w http.ResponseWriter, r *http.Request, user *models.User) {
 	type reqBody struct {
@@ -24,31 +27,31 @@ func AddUser(
 
 		body, err := utils.Decode[reqBody](r)
 		if err != nil {
-			utils.Encode(w, http.StatusBadRequest, map[string]string{"message": "failed to decode request body"})
+			problem.Write(w, r, problem.InvalidBody())
 			return
 		}
 
 		err = validation.ValidateStruct(&body,
 			validation.Field(&body.UserID, validation.Required),
-			validation.Field(&body.Role, validation.Required, validation.In(string(models.VaultRoleEditor))),
+			validation.Field(&body.Role, validation.Required, validation.In(
+				string(models.VaultRoleAdmin),
+				string(models.VaultRoleEditor),
+				string(models.VaultRoleViewer),
+			)),
 		)
 		if err != nil {
-			utils.Encode(w, http.StatusBadRequest, err)
+			problem.Write(w, r, problem.Validation(err))
 			return
 		}
 
 		err = vaultService.AddUser(r.Context(), user.ID, body.UserID, vaultID, models.VaultRole(body.Role))
 		if err != nil {
 			if errors.Is(err, services.ErrUserAlreadyAssignedToVault) {
-				utils.Encode(w, http.StatusBadRequest, map[string]string{"message": "user has already been assigned to this vault"})
+				problem.Write(w, r, problem.Field("userID", err))
 				return
 			}
 
-			if errors.Is(err, services.ErrVaultNotFound) {
-				utils.Encode(w, http.StatusNotFound, map[string]string{"message": "vault not found"})
-				return
-			}
-			utils.Encode(w, http.StatusInternalServerError, err.Error())
+			problem.Error(w, r, logger, err, "failed to add user to vault", "vaultID", vaultID, "userID", user.ID)
 			return
 		}
 
@@ -1,11 +1,15 @@
 package vault
 
 import (
+	"cmp"
+	"errors"
+	"log/slog"
 	"net/http"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 
 	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
 	"github.com/kkstas/tr-backend/internal/services"
 	"github.com/kkstas/tr-backend/internal/utils"
 )
@@ -16,16 +20,19 @@ var (
 )
 
 func CreateOne(
+	logger *slog.Logger,
 	vaultService *services.VaultService,
 ) func(w http.ResponseWriter, r *http.Request, user *models.User) {
 	type reqBody struct {
 		VaultName string `json:"vaultName"`
+		Template  string `json:"template"`
+		Locale    string `json:"locale"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
 		body, err := utils.Decode[reqBody](r)
 		if err != nil {
-			utils.Encode(w, http.StatusBadRequest, map[string]string{"message": "failed to decode request body"})
+			problem.Write(w, r, problem.InvalidBody())
 			return
 		}
 
@@ -33,13 +40,21 @@ func CreateOne(
 			validation.Field(&body.VaultName, validation.Required, validation.Length(minVaultNameLength, maxVaultNameLength)),
 		)
 		if err != nil {
-			utils.Encode(w, http.StatusBadRequest, err)
+			problem.Write(w, r, problem.Validation(err))
 			return
 		}
 
-		err = vaultService.CreateOne(r.Context(), user.ID, body.VaultName)
+		locale := cmp.Or(body.Locale, r.Header.Get("Accept-Language"))
+
+		err = vaultService.CreateOne(r.Context(), user.ID, body.VaultName, body.Template, locale)
 		if err != nil {
-			utils.Encode(w, http.StatusInternalServerError, err.Error())
+			if errors.Is(err, services.ErrCategoryTemplateNotFound) {
+				problem.Write(w, r, problem.Field("template", err))
+				return
+			}
+
+			problem.Error(w, r, logger, err, "failed to create vault", "userID", user.ID)
+			return
 		}
 
 		w.WriteHeader(http.StatusNoContent)
@@ -2,12 +2,12 @@ package vault_test
 
 import (
 	"context"
-	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
 	"github.com/kkstas/tr-backend/internal/testutils"
 )
 
@@ -81,15 +81,8 @@ func TestCreateOne(t *testing.T) {
 		serv.ServeHTTP(response, request)
 		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
 
-		type resBody struct {
-			VaultName string `json:"vaultName"`
-		}
-		var body resBody
-
-		err := json.NewDecoder(response.Body).Decode(&body)
-		testutils.AssertNoError(t, err)
-
-		if len(body.VaultName) == 0 {
+		errs := testutils.DecodeFieldErrors(t, response.Body)
+		if len(errs["vaultName"]) == 0 {
 			t.Error("expected vaultName error message in response body but didn't get one")
 		}
 	})
@@ -104,15 +97,8 @@ func TestCreateOne(t *testing.T) {
 		serv.ServeHTTP(response, request)
 		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
 
-		type resBody struct {
-			Message string `json:"message"`
-		}
-		var body resBody
-
-		err := json.NewDecoder(response.Body).Decode(&body)
-		testutils.AssertNoError(t, err)
-
-		if len(body.Message) == 0 {
+		body := testutils.DecodeJSON[problem.Problem](t, response.Body)
+		if len(body.Detail) == 0 {
 			t.Error("expected error message in response body but didn't get one")
 		}
 	})
@@ -125,4 +111,41 @@ func TestCreateOne(t *testing.T) {
 		serv.ServeHTTP(response, request)
 		testutils.AssertStatus(t, response.Code, http.StatusUnauthorized)
 	})
+
+	t.Run("creates vault with categories from template", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user := testutils.CreateTestUserWithToken(t, db)
+
+		reqBody := map[string]string{"vaultName": "trip", "template": "travel"}
+
+		request := httptest.NewRequest("POST", "/vaults", testutils.ToJSONBuffer(t, reqBody))
+		request.Header.Set("Authorization", "Bearer "+token)
+		request.Header.Set("Accept-Language", "en-US")
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNoContent)
+
+		vaults, err := testutils.NewTestVaultService(db).FindAll(context.Background(), user.ID)
+		testutils.AssertNoError(t, err)
+		categories, err := testutils.NewTestExpenseCategoryService(db).FindAll(context.Background(), user.ID, vaults[0].ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, categories[0].Name, "Transport")
+	})
+
+	t.Run("returns 400 for unknown template", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, _ := testutils.CreateTestUserWithToken(t, db)
+
+		reqBody := map[string]string{"vaultName": "trip", "template": "unknown"}
+
+		request := httptest.NewRequest("POST", "/vaults", testutils.ToJSONBuffer(t, reqBody))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
+	})
 }
@@ -1,18 +1,21 @@
 package vault
 
 import (
+	"log/slog"
 	"net/http"
 
 	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
 	"github.com/kkstas/tr-backend/internal/services"
 	"github.com/kkstas/tr-backend/internal/utils"
 )
 
-func FindAll(vaultService *services.VaultService) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+func FindAll(logger *slog.Logger, vaultService *services.VaultService) func(w http.ResponseWriter, r *http.Request, user *models.User) {
 	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
 		vaults, err := vaultService.FindAll(r.Context(), user.ID)
 		if err != nil {
-			utils.Encode(w, http.StatusInternalServerError, err.Error())
+			problem.Error(w, r, logger, err, "failed to find vaults", "userID", user.ID)
+			return
 		}
 
 		utils.Encode(w, http.StatusOK, vaults)
@@ -21,13 +21,13 @@ func TestFindAll(t *testing.T) {
 		// create vault for user
 		token, user := testutils.CreateTestUserWithToken(t, db)
 
-		err := testutils.NewTestVaultService(db).CreateOne(context.Background(), user.ID, "name")
+		err := testutils.NewTestVaultService(db).CreateOne(context.Background(), user.ID, "name", "", "")
 		testutils.AssertNoError(t, err)
 
 		// also create vault for other user that should not be returned
 		{
 			otherUser := testutils.CreateTestUser(t, db)
-			err := testutils.NewTestVaultService(db).CreateOne(context.Background(), otherUser.ID, "asdf")
+			err := testutils.NewTestVaultService(db).CreateOne(context.Background(), otherUser.ID, "asdf", "", "")
 			testutils.AssertNoError(t, err)
 		}
 
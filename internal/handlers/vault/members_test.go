@@ -0,0 +1,53 @@
+package vault_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestFindMembers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns vault members with roles", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		viewer := testutils.CreateTestUser(t, db)
+		err := testutils.NewTestVaultService(db).AddUser(t.Context(), user.ID, viewer.ID, vault.ID, models.VaultRoleViewer)
+		testutils.AssertNoError(t, err)
+
+		request := httptest.NewRequest("GET", "/vaults/"+vault.ID+"/users", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+
+		members := testutils.DecodeJSON[[]models.VaultMember](t, response.Body)
+		testutils.AssertEqual(t, len(members), 2)
+		roles := map[string]models.VaultRole{}
+		for _, member := range members {
+			roles[member.UserID] = member.Role
+		}
+		testutils.AssertEqual(t, roles[user.ID], models.VaultRoleOwner)
+		testutils.AssertEqual(t, roles[viewer.ID], models.VaultRoleViewer)
+	})
+
+	t.Run("returns 404 if user does not belong to vault", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, _ := testutils.CreateTestUserWithToken(t, db)
+
+		request := httptest.NewRequest("GET", "/vaults/"+uuid.New().String()+"/users", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNotFound)
+	})
+}
@@ -0,0 +1,29 @@
+package vault
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+)
+
+func RestoreOneByID(
+	logger *slog.Logger,
+	vaultService *services.VaultService,
+	retention time.Duration,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		vaultID := r.PathValue("vaultID")
+
+		err := vaultService.RestoreOneByID(r.Context(), user.ID, vaultID, retention)
+		if err != nil {
+			problem.Error(w, r, logger, err, "failed to restore vault", "vaultID", vaultID, "userID", user.ID)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,63 @@
+package vault_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestRestoreOneByID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("lists deleted vault in trash and restores it", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		vaultService := testutils.NewTestVaultService(db)
+
+		err := vaultService.DeleteOneByID(ctx, user.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+
+		{
+			request := httptest.NewRequest("GET", "/vaults/trash", nil)
+			request.Header.Set("Authorization", "Bearer "+token)
+			response := httptest.NewRecorder()
+			serv.ServeHTTP(response, request)
+
+			testutils.AssertStatus(t, response.Code, http.StatusOK)
+			trash := testutils.DecodeJSON[[]models.UserVaultWithRole](t, response.Body)
+			testutils.AssertEqual(t, len(trash), 1)
+			testutils.AssertEqual(t, trash[0].ID, vault.ID)
+		}
+
+		request := httptest.NewRequest("POST", "/vaults/"+vault.ID+"/restore", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNoContent)
+
+		vaults, err := vaultService.FindAll(ctx, user.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(vaults), 1)
+	})
+
+	t.Run("returns 404 if vault is not in trash", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, _ := testutils.CreateTestUserWithToken(t, db)
+
+		request := httptest.NewRequest("POST", "/vaults/"+uuid.New().String()+"/restore", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNotFound)
+	})
+}
@@ -0,0 +1,64 @@
+package vault
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/utils"
+)
+
+func TransferOwnership(
+	logger *slog.Logger,
+	vaultService *services.VaultService,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	type reqBody struct {
+		UserID   string `json:"userID"`
+		DemoteTo string `json:"demoteTo"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		vaultID := r.PathValue("vaultID")
+
+		body, err := utils.Decode[reqBody](r)
+		if err != nil {
+			problem.Write(w, r, problem.InvalidBody())
+			return
+		}
+
+		err = validation.ValidateStruct(&body,
+			validation.Field(&body.UserID, validation.Required, validation.NotIn(user.ID).Error("cannot transfer ownership to yourself")),
+			validation.Field(&body.DemoteTo, validation.In(
+				string(models.VaultRoleAdmin),
+				string(models.VaultRoleEditor),
+				string(models.VaultRoleViewer),
+			)),
+		)
+		if err != nil {
+			problem.Write(w, r, problem.Validation(err))
+			return
+		}
+
+		err = vaultService.TransferOwnership(r.Context(), user.ID, body.UserID, vaultID, models.VaultRole(body.DemoteTo))
+		if err != nil {
+			if errors.Is(err, services.ErrUserNotAssignedToVault) {
+				problem.Write(w, r, problem.Field("userID", err))
+				return
+			}
+			if errors.Is(err, services.ErrUserAlreadyVaultOwner) {
+				problem.Write(w, r, problem.Field("userID", err))
+				return
+			}
+
+			problem.Error(w, r, logger, err, "failed to transfer vault ownership", "vaultID", vaultID, "userID", user.ID, "newOwnerID", body.UserID)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
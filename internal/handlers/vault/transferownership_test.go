@@ -0,0 +1,114 @@
+package vault_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestTransferOwnership(t *testing.T) {
+	t.Parallel()
+
+	type reqBody struct {
+		UserID   string           `json:"userID"`
+		DemoteTo models.VaultRole `json:"demoteTo"`
+	}
+
+	t.Run("transfers ownership and demotes previous owner", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		serv, db := testutils.NewTestApplication(t)
+		token, owner, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		member := testutils.CreateTestUser(t, db)
+		vaultService := testutils.NewTestVaultService(db)
+
+		err := vaultService.AddUser(ctx, owner.ID, member.ID, vault.ID, models.VaultRoleEditor)
+		testutils.AssertNoError(t, err)
+
+		request := httptest.NewRequest(
+			"POST",
+			fmt.Sprintf("/vaults/%s/transfer-ownership", vault.ID),
+			testutils.ToJSONBuffer(t, reqBody{UserID: member.ID, DemoteTo: models.VaultRoleEditor}),
+		)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNoContent)
+
+		memberVault, err := vaultService.FindOneByID(ctx, member.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, memberVault.UserRole, models.VaultRoleOwner)
+
+		ownerVault, err := vaultService.FindOneByID(ctx, owner.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, ownerVault.UserRole, models.VaultRoleEditor)
+	})
+
+	t.Run("returns 403 if user is not vault owner", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		serv, db := testutils.NewTestApplication(t)
+		_, owner, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		adminToken, admin := testutils.CreateTestUserWithToken(t, db)
+		member := testutils.CreateTestUser(t, db)
+		vaultService := testutils.NewTestVaultService(db)
+
+		err := vaultService.AddUser(ctx, owner.ID, admin.ID, vault.ID, models.VaultRoleAdmin)
+		testutils.AssertNoError(t, err)
+		err = vaultService.AddUser(ctx, owner.ID, member.ID, vault.ID, models.VaultRoleEditor)
+		testutils.AssertNoError(t, err)
+
+		request := httptest.NewRequest(
+			"POST",
+			fmt.Sprintf("/vaults/%s/transfer-ownership", vault.ID),
+			testutils.ToJSONBuffer(t, reqBody{UserID: member.ID, DemoteTo: ""}),
+		)
+		request.Header.Set("Authorization", "Bearer "+adminToken)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusForbidden)
+	})
+
+	t.Run("returns 400 if new owner is not assigned to the vault", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, _, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		outsider := testutils.CreateTestUser(t, db)
+
+		request := httptest.NewRequest(
+			"POST",
+			fmt.Sprintf("/vaults/%s/transfer-ownership", vault.ID),
+			testutils.ToJSONBuffer(t, reqBody{UserID: outsider.ID, DemoteTo: models.VaultRoleViewer}),
+		)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
+	})
+
+	t.Run("returns 400 if demotion role is invalid", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, _, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		member := testutils.CreateTestUser(t, db)
+
+		request := httptest.NewRequest(
+			"POST",
+			fmt.Sprintf("/vaults/%s/transfer-ownership", vault.ID),
+			testutils.ToJSONBuffer(t, reqBody{UserID: member.ID, DemoteTo: models.VaultRoleOwner}),
+		)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,28 @@
+package vault
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/utils"
+)
+
+func FindAllDeleted(
+	logger *slog.Logger,
+	vaultService *services.VaultService,
+	retention time.Duration,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		vaults, err := vaultService.FindAllDeleted(r.Context(), user.ID, retention)
+		if err != nil {
+			problem.Error(w, r, logger, err, "failed to find deleted vaults", "userID", user.ID)
+			return
+		}
+
+		utils.Encode(w, http.StatusOK, vaults)
+	}
+}
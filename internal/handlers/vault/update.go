@@ -0,0 +1,82 @@
+package vault
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"regexp"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/utils"
+)
+
+var (
+	maxVaultDescriptionLength = 500
+	maxVaultIconLength        = 50
+	hexColorRegexp            = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+)
+
+func UpdateOne(
+	logger *slog.Logger,
+	vaultService *services.VaultService,
+) func(w http.ResponseWriter, r *http.Request, user *models.User) {
+	type reqBody struct {
+		Name                   *string `json:"name"`
+		Description            *string `json:"description"`
+		Icon                   *string `json:"icon"`
+		Color                  *string `json:"color"`
+		BaseCurrency           *string `json:"baseCurrency"`
+		DefaultPaymentMethodID *string `json:"defaultPaymentMethodID"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, user *models.User) {
+		vaultID := r.PathValue("vaultID")
+
+		body, err := utils.Decode[reqBody](r)
+		if err != nil {
+			problem.Write(w, r, problem.InvalidBody())
+			return
+		}
+
+		err = validation.ValidateStruct(&body,
+			validation.Field(&body.Name, validation.NilOrNotEmpty, validation.Length(minVaultNameLength, maxVaultNameLength)),
+			validation.Field(&body.Description, validation.Length(0, maxVaultDescriptionLength)),
+			validation.Field(&body.Icon, validation.Length(0, maxVaultIconLength)),
+			validation.Field(&body.Color, validation.Match(hexColorRegexp).Error("must be a hex color like #1a2b3c")),
+			validation.Field(&body.BaseCurrency, is.CurrencyCode),
+		)
+		if err != nil {
+			problem.Write(w, r, problem.Validation(err))
+			return
+		}
+
+		vault, err := vaultService.UpdateOne(r.Context(), user.ID, vaultID, models.VaultUpdate{
+			Name:                   body.Name,
+			Description:            body.Description,
+			Icon:                   body.Icon,
+			Color:                  body.Color,
+			BaseCurrency:           body.BaseCurrency,
+			DefaultPaymentMethodID: body.DefaultPaymentMethodID,
+		})
+		if err != nil {
+			if errors.Is(err, services.ErrVaultWithThatNameAlreadyExists) {
+				problem.Write(w, r, problem.Field("name", err))
+				return
+			}
+			if errors.Is(err, services.ErrPaymentMethodNotFound) || errors.Is(err, services.ErrPaymentMethodArchived) {
+				problem.Write(w, r, problem.Field("defaultPaymentMethodID", err))
+				return
+			}
+
+			problem.Error(w, r, logger, err, "failed to update vault", "vaultID", vaultID, "userID", user.ID)
+			return
+		}
+
+		utils.Encode(w, http.StatusOK, vault)
+	}
+}
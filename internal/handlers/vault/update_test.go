@@ -0,0 +1,128 @@
+package vault_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestUpdateOne(t *testing.T) {
+	t.Parallel()
+
+	t.Run("updates vault and returns it in vault list", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		paymentMethod := testutils.CreateTestPaymentMethod(t, db, user.ID, vault.ID)
+
+		reqBody := map[string]string{
+			"name":                   "renamed",
+			"description":            "trip to Norway",
+			"icon":                   "plane",
+			"color":                  "#00ff00",
+			"baseCurrency":           "NOK",
+			"defaultPaymentMethodID": paymentMethod.ID,
+		}
+
+		request := httptest.NewRequest("PATCH", "/vaults/"+vault.ID, testutils.ToJSONBuffer(t, reqBody))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusOK)
+		updated := testutils.DecodeJSON[models.UserVaultWithRole](t, response.Body)
+		testutils.AssertEqual(t, updated.Name, reqBody["name"])
+
+		request = httptest.NewRequest("GET", "/vaults", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		response = httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		vaults := testutils.DecodeJSON[[]models.UserVaultWithRole](t, response.Body)
+		testutils.AssertEqual(t, len(vaults), 1)
+		testutils.AssertEqual(t, vaults[0].Name, reqBody["name"])
+		testutils.AssertEqual(t, vaults[0].Description, reqBody["description"])
+		testutils.AssertEqual(t, vaults[0].Icon, reqBody["icon"])
+		testutils.AssertEqual(t, vaults[0].Color, reqBody["color"])
+		testutils.AssertEqual(t, vaults[0].BaseCurrency, reqBody["baseCurrency"])
+		testutils.AssertEqual(t, vaults[0].DefaultPaymentMethodID, reqBody["defaultPaymentMethodID"])
+	})
+
+	t.Run("returns 400 if default payment method does not belong to vault", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		otherVault := testutils.CreateTestVault(t, db, user.ID)
+		otherPaymentMethod := testutils.CreateTestPaymentMethod(t, db, user.ID, otherVault.ID)
+
+		for _, paymentMethodID := range []string{otherPaymentMethod.ID, "does-not-exist"} {
+			request := httptest.NewRequest("PATCH", "/vaults/"+vault.ID, testutils.ToJSONBuffer(t, map[string]string{"defaultPaymentMethodID": paymentMethodID}))
+			request.Header.Set("Authorization", "Bearer "+token)
+			response := httptest.NewRecorder()
+			serv.ServeHTTP(response, request)
+
+			testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
+			m := testutils.DecodeFieldErrors(t, response.Body)
+			testutils.AssertNotEmpty(t, m["defaultPaymentMethodID"])
+		}
+	})
+
+	t.Run("clears default payment method", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		paymentMethod := testutils.CreateTestPaymentMethod(t, db, user.ID, vault.ID)
+
+		for _, paymentMethodID := range []string{paymentMethod.ID, ""} {
+			request := httptest.NewRequest("PATCH", "/vaults/"+vault.ID, testutils.ToJSONBuffer(t, map[string]string{"defaultPaymentMethodID": paymentMethodID}))
+			request.Header.Set("Authorization", "Bearer "+token)
+			response := httptest.NewRecorder()
+			serv.ServeHTTP(response, request)
+
+			testutils.AssertStatus(t, response.Code, http.StatusOK)
+			updated := testutils.DecodeJSON[models.UserVaultWithRole](t, response.Body)
+			testutils.AssertEqual(t, updated.DefaultPaymentMethodID, paymentMethodID)
+		}
+	})
+
+	t.Run("returns 400 if request body is invalid", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		token, _, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+
+		reqBody := map[string]string{
+			"name":         "x",
+			"color":        "green",
+			"baseCurrency": "XYZW",
+		}
+
+		request := httptest.NewRequest("PATCH", "/vaults/"+vault.ID, testutils.ToJSONBuffer(t, reqBody))
+		request.Header.Set("Authorization", "Bearer "+token)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusBadRequest)
+
+		m := testutils.DecodeFieldErrors(t, response.Body)
+		testutils.AssertNotEmpty(t, m["name"])
+		testutils.AssertNotEmpty(t, m["color"])
+		testutils.AssertNotEmpty(t, m["baseCurrency"])
+	})
+
+	t.Run("returns 404 if user does not belong to vault", func(t *testing.T) {
+		t.Parallel()
+		serv, db := testutils.NewTestApplication(t)
+		_, _, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		otherToken, _ := testutils.CreateTestUserWithToken(t, db)
+
+		request := httptest.NewRequest("PATCH", "/vaults/"+vault.ID, testutils.ToJSONBuffer(t, map[string]string{"name": "renamed"}))
+		request.Header.Set("Authorization", "Bearer "+otherToken)
+		response := httptest.NewRecorder()
+		serv.ServeHTTP(response, request)
+
+		testutils.AssertStatus(t, response.Code, http.StatusNotFound)
+	})
+}
@@ -0,0 +1,69 @@
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// staleAfter is the number of missed intervals after which a worker is considered stuck.
+const staleAfter = 2
+
+// Health tracks the last run of every background worker.
+type Health struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	workers map[string]*workerState
+}
+
+type workerState struct {
+	interval time.Duration
+	lastRun  time.Time
+	lastErr  error
+}
+
+func NewHealth() *Health {
+	return &Health{now: time.Now, workers: map[string]*workerState{}} // nolint: exhaustruct
+}
+
+// register starts tracking a worker that runs every interval. Until its first run
+// completes, the worker is considered healthy for the same grace period.
+func (h *Health) register(name string, interval time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.workers[name] = &workerState{interval: interval, lastRun: h.now(), lastErr: nil}
+}
+
+func (h *Health) report(name string, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if worker, ok := h.workers[name]; ok {
+		worker.lastRun = h.now()
+		worker.lastErr = err
+	}
+}
+
+// Check returns an error describing every worker whose last run failed or which
+// hasn't run for staleAfter intervals.
+func (h *Health) Check() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var problems []string
+	for name, worker := range h.workers {
+		switch {
+		case worker.lastErr != nil:
+			problems = append(problems, fmt.Sprintf("%s: last run failed", name))
+		case h.now().Sub(worker.lastRun) > staleAfter*worker.interval:
+			problems = append(problems, fmt.Sprintf("%s: no run since %s", name, worker.lastRun.UTC().Format(time.RFC3339)))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	slices.Sort(problems)
+	return errors.New(strings.Join(problems, "; "))
+}
@@ -0,0 +1,50 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+type Purger interface {
+	PurgeDeleted(ctx context.Context, retention time.Duration) (int64, error)
+}
+
+// PurgeTrash returns a job that permanently removes soft deleted items once they
+// are older than retention. It runs immediately and then every interval until
+// ctx is cancelled. The outcome of every run is reported to health.
+func PurgeTrash(logger *slog.Logger, health *Health, interval, retention time.Duration, purgers map[string]Purger) func(ctx context.Context) {
+	const name = "purgeTrash"
+	health.register(name, interval)
+
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			var errs []error
+			for entity, purger := range purgers {
+				purged, err := purger.PurgeDeleted(ctx, retention)
+				if err != nil {
+					if ctx.Err() != nil {
+						return
+					}
+					logger.Error("failed to purge trash", "entity", entity, "error", err)
+					errs = append(errs, err)
+					continue
+				}
+				if purged > 0 {
+					logger.Info("purged trash", "entity", entity, "count", purged)
+				}
+			}
+			health.report(name, errors.Join(errs...))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+}
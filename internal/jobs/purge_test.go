@@ -0,0 +1,95 @@
+package jobs_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kkstas/tr-backend/internal/jobs"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+type purgerFunc func(ctx context.Context, retention time.Duration) (int64, error)
+
+func (f purgerFunc) PurgeDeleted(ctx context.Context, retention time.Duration) (int64, error) {
+	return f(ctx, retention)
+}
+
+func TestPurgeTrash(t *testing.T) {
+	t.Parallel()
+
+	t.Run("purges immediately and stops when context is cancelled", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+
+		var calls atomic.Int32
+		retention := 48 * time.Hour
+		purger := purgerFunc(func(_ context.Context, r time.Duration) (int64, error) {
+			testutils.AssertEqual(t, r, retention)
+			if calls.Add(1) == 1 {
+				cancel()
+			}
+			return 1, nil
+		})
+
+		done := make(chan struct{})
+		go func() {
+			jobs.PurgeTrash(slog.New(slog.NewTextHandler(io.Discard, nil)), jobs.NewHealth(), time.Hour, retention, map[string]jobs.Purger{"test": purger})(ctx)
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("expected purge job to stop after context cancellation")
+		}
+
+		testutils.AssertEqual(t, calls.Load(), 1)
+	})
+}
+
+func TestHealth(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name    string
+		err     error
+		wantErr bool
+	}{
+		{name: "healthy after successful run", err: nil, wantErr: false},
+		{name: "unhealthy after failed run", err: errors.New("database is locked"), wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, cancel := context.WithCancel(context.Background())
+			health := jobs.NewHealth()
+
+			purger := purgerFunc(func(context.Context, time.Duration) (int64, error) {
+				return 0, tc.err
+			})
+			job := jobs.PurgeTrash(slog.New(slog.NewTextHandler(io.Discard, nil)), health, time.Hour, time.Hour, map[string]jobs.Purger{"test": purger})
+			testutils.AssertNoError(t, health.Check())
+
+			done := make(chan struct{})
+			go func() {
+				job(ctx)
+				close(done)
+			}()
+
+			deadline := time.After(time.Second)
+			for (health.Check() != nil) != tc.wantErr {
+				select {
+				case <-deadline:
+					t.Fatalf("expected health check error to be %v, got %v", tc.wantErr, health.Check())
+				case <-time.After(time.Millisecond):
+				}
+			}
+			cancel()
+			<-done
+		})
+	}
+}
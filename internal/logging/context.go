@@ -0,0 +1,62 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+type requestInfoKeyType struct{}
+
+var requestInfoKey = requestInfoKeyType{}
+
+// requestInfo is stored as a pointer so that the user ID set by the auth middleware
+// deep in the handler chain is visible to middlewares wrapping it, e.g. the access log.
+type requestInfo struct {
+	requestID string
+	userID    string
+}
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestInfoKey, &requestInfo{requestID: requestID}) // nolint: exhaustruct
+}
+
+func RequestID(ctx context.Context) string {
+	if info, ok := ctx.Value(requestInfoKey).(*requestInfo); ok {
+		return info.requestID
+	}
+	return ""
+}
+
+// SetUserID attaches the authenticated user to the request started with WithRequestID.
+func SetUserID(ctx context.Context, userID string) {
+	if info, ok := ctx.Value(requestInfoKey).(*requestInfo); ok {
+		info.userID = userID
+	}
+}
+
+// ContextHandler adds request and user IDs from the context to every record.
+type ContextHandler struct {
+	slog.Handler
+}
+
+func NewContextHandler(handler slog.Handler) *ContextHandler {
+	return &ContextHandler{Handler: handler}
+}
+
+func (h *ContextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if info, ok := ctx.Value(requestInfoKey).(*requestInfo); ok {
+		record.AddAttrs(slog.String("requestID", info.requestID))
+		if info.userID != "" {
+			record.AddAttrs(slog.String("authUserID", info.userID))
+		}
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ContextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	return &ContextHandler{Handler: h.Handler.WithGroup(name)}
+}
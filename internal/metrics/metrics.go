@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/kkstas/tr-backend/internal/database"
+	"github.com/kkstas/tr-backend/internal/utils"
+)
+
+const namespace = "tr"
+
+type Metrics struct {
+	registry        *prometheus.Registry
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	logins          *prometheus.CounterVec
+	expensesCreated prometheus.Counter
+}
+
+// New creates metrics with their own registry, so that every application instance,
+// e.g. one per test, reports only its own requests and database pool.
+func New(db *database.DB) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{ // nolint: exhaustruct
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests by method, route pattern and status.",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{ // nolint: exhaustruct
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of HTTP requests by method and route pattern.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		logins: prometheus.NewCounterVec(prometheus.CounterOpts{ // nolint: exhaustruct
+			Namespace: namespace,
+			Name:      "logins_total",
+			Help:      "Number of login attempts by result.",
+		}, []string{"result"}),
+		expensesCreated: prometheus.NewCounter(prometheus.CounterOpts{ // nolint: exhaustruct
+			Namespace: namespace,
+			Name:      "expenses_created_total",
+			Help:      "Number of created expenses.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		m.requests,
+		m.requestDuration,
+		m.logins,
+		m.expensesCreated,
+		collectors.NewDBStatsCollector(db.DB, "writer"),
+		collectors.NewDBStatsCollector(db.Read, "reader"),
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}), // nolint: exhaustruct
+	)
+
+	// report zero instead of missing series before the first login
+	m.logins.WithLabelValues("success")
+	m.logins.WithLabelValues("failure")
+
+	return m
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}) // nolint: exhaustruct
+}
+
+func (m *Metrics) LoginSucceeded() {
+	m.logins.WithLabelValues("success").Inc()
+}
+
+func (m *Metrics) LoginFailed() {
+	m.logins.WithLabelValues("failure").Inc()
+}
+
+func (m *Metrics) ExpenseCreated() {
+	m.expensesCreated.Inc()
+}
+
+// Middleware records requests by route pattern rather than path, which would have
+// an unbounded number of values. The pattern is read once next returns, so the request
+// passed on must reach the ServeMux, which sets r.Pattern on it.
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := utils.NewResponseRecorder(w)
+		start := time.Now()
+		next.ServeHTTP(recorder, r)
+
+		route := "unmatched"
+		if r.Pattern != "" {
+			_, path, found := strings.Cut(r.Pattern, " ")
+			if !found {
+				path = r.Pattern
+			}
+			route = path
+		}
+
+		m.requests.WithLabelValues(r.Method, route, strconv.Itoa(recorder.Status)).Inc()
+		m.requestDuration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+	})
+}
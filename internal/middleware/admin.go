@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/kkstas/tr-backend/internal/problem"
+)
+
+// RequireAdminToken guards operational endpoints with a static bearer token. They don't
+// exist unless a token is configured.
+func RequireAdminToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token == "" {
+			problem.Write(w, r, problem.NotFound())
+			return
+		}
+
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			problem.Write(w, r, problem.Unauthorized())
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
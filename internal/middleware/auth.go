@@ -9,6 +9,8 @@ import (
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/kkstas/tr-backend/internal/auth"
+	"github.com/kkstas/tr-backend/internal/logging"
+	"github.com/kkstas/tr-backend/internal/problem"
 )
 
 type UserClaimsKeyType struct{}
@@ -27,16 +29,16 @@ func RequireAuth(
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			tokenString := r.Header.Get("Authorization")
 			if !strings.HasPrefix(tokenString, "Bearer ") {
-				w.WriteHeader(http.StatusUnauthorized)
+				problem.Write(w, r, problem.Unauthorized())
 				return
 			}
 			tokenString = strings.TrimPrefix(tokenString, "Bearer ")
 
 			token, err := auth.VerifyToken(jwtSecretKey, tokenString)
 			if err != nil {
-				w.WriteHeader(http.StatusUnauthorized)
+				problem.Write(w, r, problem.Unauthorized())
 				if !errors.Is(err, auth.ErrInvalidToken) {
-					logger.Error("failed to verify token", "error", err)
+					logger.ErrorContext(r.Context(), "failed to verify token", "error", err)
 				}
 				return
 			}
@@ -48,11 +50,13 @@ func RequireAuth(
 			if val, ok := claims["sub"].(string); ok {
 				jwtClaims.UserID = val
 			} else {
-				logger.Error("failed to find UserID in claims", "claims", jwtClaims)
-				w.WriteHeader(http.StatusUnauthorized)
+				logger.ErrorContext(r.Context(), "failed to find UserID in claims", "claims", jwtClaims)
+				problem.Write(w, r, problem.Unauthorized())
 				return
 			}
 
+			logging.SetUserID(r.Context(), jwtClaims.UserID)
+
 			ctx := context.WithValue(r.Context(), UserClaimsKey, jwtClaims)
 			fn(w, r.WithContext(ctx))
 		})
@@ -2,12 +2,14 @@ package middleware
 
 import (
 	"net/http"
+
+	"github.com/kkstas/tr-backend/internal/problem"
 )
 
 func Enable(enable bool, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if !enable {
-			w.WriteHeader(http.StatusNotFound)
+			problem.Write(w, r, problem.NotFound())
 			return
 		}
 		next.ServeHTTP(w, r)
@@ -4,34 +4,28 @@ import (
 	"log/slog"
 	"net/http"
 	"time"
-)
-
-type loggingResponseWriter struct {
-	http.ResponseWriter
-	statusCode int
-}
 
-func (lrw *loggingResponseWriter) WriteHeader(code int) {
-	lrw.statusCode = code
-	lrw.ResponseWriter.WriteHeader(code)
-}
+	"github.com/kkstas/tr-backend/internal/utils"
+)
 
 func LogHTTP(logger *slog.Logger, next http.Handler) http.Handler {
-	httpLogger := func(r *http.Request, lrw *loggingResponseWriter, start time.Time) {
+	httpLogger := func(r *http.Request, recorder *utils.ResponseRecorder, start time.Time) {
 		logger.LogAttrs(r.Context(),
 			slog.LevelDebug,
 			"request",
 			slog.String("method", r.Method),
-			slog.Int("status", lrw.statusCode),
+			slog.Int("status", recorder.Status),
 			slog.String("uri", r.RequestURI),
+			slog.Int("size", recorder.Size),
+			slog.String("remoteAddr", r.RemoteAddr),
 			slog.String("duration", time.Since(start).String()),
 		)
 	}
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		lrw := &loggingResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		recorder := utils.NewResponseRecorder(w)
 		now := time.Now()
-		next.ServeHTTP(lrw, r)
-		httpLogger(r, lrw, now)
+		next.ServeHTTP(recorder, r)
+		httpLogger(r, recorder, now)
 	})
 }
@@ -0,0 +1,76 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/kkstas/tr-backend/internal/logging"
+	"github.com/kkstas/tr-backend/internal/middleware"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestRequestID(t *testing.T) {
+	t.Parallel()
+
+	handler := middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(logging.RequestID(r.Context())))
+	}))
+
+	t.Run("reuses request ID from header", func(t *testing.T) {
+		t.Parallel()
+		request := httptest.NewRequest("GET", "/", nil)
+		request.Header.Set(middleware.RequestIDHeader, "edge-1234")
+		response := httptest.NewRecorder()
+		handler.ServeHTTP(response, request)
+
+		testutils.AssertEqual(t, response.Header().Get(middleware.RequestIDHeader), "edge-1234")
+		testutils.AssertEqual(t, response.Body.String(), "edge-1234")
+	})
+
+	t.Run("generates request ID if header is missing or invalid", func(t *testing.T) {
+		t.Parallel()
+		for _, header := range []string{"", "spoofed\nlog line", strings.Repeat("a", 129)} {
+			request := httptest.NewRequest("GET", "/", nil)
+			request.Header.Set(middleware.RequestIDHeader, header)
+			response := httptest.NewRecorder()
+			handler.ServeHTTP(response, request)
+
+			requestID := response.Header().Get(middleware.RequestIDHeader)
+			testutils.AssertNoError(t, uuid.Validate(requestID))
+			testutils.AssertEqual(t, response.Body.String(), requestID)
+		}
+	})
+}
+
+func TestLogHTTP(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger := slog.New(logging.NewContextHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))) // nolint: exhaustruct
+
+	handler := middleware.RequestID(middleware.LogHTTP(logger, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logging.SetUserID(r.Context(), "user-1")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("hello"))
+	})))
+
+	request := httptest.NewRequest("POST", "/expenses", nil)
+	request.Header.Set(middleware.RequestIDHeader, "req-1")
+	request.RemoteAddr = "203.0.113.7:51234"
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	var entry map[string]any
+	testutils.AssertNoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	testutils.AssertEqual(t, entry["requestID"], any("req-1"))
+	testutils.AssertEqual(t, entry["authUserID"], any("user-1"))
+	testutils.AssertEqual(t, entry["status"], any(float64(http.StatusCreated)))
+	testutils.AssertEqual(t, entry["size"], any(float64(len("hello"))))
+	testutils.AssertEqual(t, entry["remoteAddr"], any("203.0.113.7:51234"))
+}
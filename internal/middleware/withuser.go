@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
 	"github.com/kkstas/tr-backend/internal/services"
 )
 
@@ -16,15 +17,15 @@ func WithUser(
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			claims, ok := r.Context().Value(UserClaimsKey).(JWTClaims)
 			if !ok {
-				logger.Error("user claims not found in request context")
-				w.WriteHeader(http.StatusUnauthorized)
+				logger.ErrorContext(r.Context(), "user claims not found in request context")
+				problem.Write(w, r, problem.Unauthorized())
 				return
 			}
 
 			user, err := userService.FindOneByID(r.Context(), claims.UserID)
 			if err != nil {
-				logger.Error("failed to find user with id from token %s: %v", claims.UserID, err)
-				w.WriteHeader(http.StatusUnauthorized)
+				logger.ErrorContext(r.Context(), "failed to find user with id from token", "userID", claims.UserID, "error", err)
+				problem.Write(w, r, problem.Unauthorized())
 				return
 			}
 
@@ -0,0 +1,63 @@
+package models
+
+type AccountType string
+
+var (
+	AccountTypeChecking   AccountType = "checking"
+	AccountTypeSavings    AccountType = "savings"
+	AccountTypeCash       AccountType = "cash"
+	AccountTypeCreditCard AccountType = "credit_card"
+)
+
+type Account struct {
+	ID             string      `json:"id"`
+	Name           string      `json:"name"`
+	Type           AccountType `json:"type"`
+	OpeningBalance float64     `json:"openingBalance"`
+	Balance        float64     `json:"balance"`
+	VaultID        string      `json:"vaultID"`
+	CreatedBy      string      `json:"createdBy"`
+	CreatedAt      string      `json:"createdAt"`
+}
+
+type AccountUpdate struct {
+	Name           *string
+	Type           *AccountType
+	OpeningBalance *float64
+}
+
+type LedgerEntryKind string
+
+var (
+	LedgerEntryKindExpense     LedgerEntryKind = "expense"
+	LedgerEntryKindIncome      LedgerEntryKind = "income"
+	LedgerEntryKindTransferIn  LedgerEntryKind = "transfer_in"
+	LedgerEntryKindTransferOut LedgerEntryKind = "transfer_out"
+)
+
+type LedgerEntry struct {
+	Kind        LedgerEntryKind `json:"kind"`
+	ID          string          `json:"id"`
+	Date        string          `json:"date"`
+	Description string          `json:"description"`
+	// Amount is positive for money coming into the account and negative for money going out.
+	Amount  float64 `json:"amount"`
+	Balance float64 `json:"balance"`
+}
+
+type AccountLedger struct {
+	Account Account       `json:"account"`
+	Entries []LedgerEntry `json:"entries"`
+}
+
+type Transfer struct {
+	ID            string  `json:"id"`
+	FromAccountID string  `json:"fromAccountID"`
+	ToAccountID   string  `json:"toAccountID"`
+	Amount        float64 `json:"amount"`
+	Date          string  `json:"date"`
+	Description   string  `json:"description"`
+	VaultID       string  `json:"vaultID"`
+	CreatedBy     string  `json:"createdBy"`
+	CreatedAt     string  `json:"createdAt"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import "encoding/json"
+
+type AuditAction string
+
+const (
+	AuditActionCreate  AuditAction = "create"
+	AuditActionUpdate  AuditAction = "update"
+	AuditActionDelete  AuditAction = "delete"
+	AuditActionRestore AuditAction = "restore"
+)
+
+type AuditEntity string
+
+const (
+	AuditEntityVault           AuditEntity = "vault"
+	AuditEntityMember          AuditEntity = "member"
+	AuditEntityExpenseCategory AuditEntity = "expense_category"
+	AuditEntityExpense         AuditEntity = "expense"
+)
+
+// AuditEvent describes a single change made by ActorID. Before and After hold JSON
+// snapshots of the entity and are null for creations and deletions respectively.
+type AuditEvent struct {
+	ID         int64           `json:"id"`
+	VaultID    string          `json:"vaultID"`
+	ActorID    string          `json:"actorID"`
+	Action     AuditAction     `json:"action"`
+	EntityType AuditEntity     `json:"entityType"`
+	EntityID   string          `json:"entityID"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	CreatedAt  string          `json:"createdAt"`
+}
+
+type AuditEventPage struct {
+	Events []AuditEvent `json:"events"`
+	// NextCursor is passed as cursor to fetch the next, older page. Empty on the last page.
+	NextCursor string `json:"nextCursor,omitempty"`
+}
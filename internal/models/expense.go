@@ -0,0 +1,36 @@
+package models
+
+type Expense struct {
+	ID              string         `json:"id"`
+	Name            string         `json:"name"`
+	Notes           string         `json:"notes"`
+	Date            string         `json:"date"`
+	CategoryID      string         `json:"categoryID"`
+	Amount          float64        `json:"amount"`
+	PaymentMethodID string         `json:"paymentMethodID"`
+	AccountID       string         `json:"accountID"`
+	PaidBy          string         `json:"paidBy"`
+	SplitMethod     SplitMethod    `json:"splitMethod,omitempty"`
+	Splits          []ExpenseSplit `json:"splits"`
+	TagIDs          []string       `json:"tagIDs"`
+	VaultID         string         `json:"vaultID"`
+	CreatedBy       string         `json:"createdBy"`
+	CreatedAt       string         `json:"createdAt"`
+	DeletedAt       string         `json:"deletedAt,omitempty"`
+}
+
+type ExpenseUpdate struct {
+	Name            *string
+	Notes           *string
+	Date            *string
+	CategoryID      *string
+	Amount          *float64
+	PaymentMethodID *string
+	// AccountID set to empty string detaches expense from its account.
+	AccountID *string
+	TagIDs    *[]string
+	PaidBy    *string
+	// Split replaces splits of the expense. When it's nil and Amount changes, existing
+	// splits are computed again from their split method and shares.
+	Split *SplitUpdate
+}
@@ -12,7 +12,25 @@ type ExpenseCategory struct {
 	Name      string                `json:"name"`
 	Status    ExpenseCategoryStatus `json:"status"`
 	Priority  int                   `json:"priority"`
+	ParentID  *string               `json:"parentID"`
 	VaultID   string                `json:"vaultID"`
 	CreatedBy string                `json:"createdBy"`
 	CreatedAt string                `json:"createdAt"`
 }
+
+type ExpenseCategoryUpdate struct {
+	Name   *string
+	Status *ExpenseCategoryStatus
+	// ParentID moves the category under another one. Empty string moves it to the top level.
+	ParentID *string
+}
+
+type ExpenseCategoryTreeNode struct {
+	ExpenseCategory
+	Children []ExpenseCategoryTreeNode `json:"children"`
+}
+
+type CategoryTemplate struct {
+	Name          string             `json:"name"`
+	Subcategories []CategoryTemplate `json:"subcategories"`
+}
@@ -0,0 +1,20 @@
+package models
+
+type Income struct {
+	ID        string  `json:"id"`
+	Source    string  `json:"source"`
+	Date      string  `json:"date"`
+	Amount    float64 `json:"amount"`
+	AccountID string  `json:"accountID"`
+	VaultID   string  `json:"vaultID"`
+	CreatedBy string  `json:"createdBy"`
+	CreatedAt string  `json:"createdAt"`
+}
+
+type IncomeUpdate struct {
+	Source *string
+	Date   *string
+	Amount *float64
+	// AccountID set to empty string detaches income from its account.
+	AccountID *string
+}
@@ -0,0 +1,22 @@
+package models
+
+type PaymentMethodStatus string
+
+var (
+	PaymentMethodStatusActive   PaymentMethodStatus = "active"
+	PaymentMethodStatusArchived PaymentMethodStatus = "archived"
+)
+
+type PaymentMethod struct {
+	ID        string              `json:"id"`
+	Name      string              `json:"name"`
+	Status    PaymentMethodStatus `json:"status"`
+	VaultID   string              `json:"vaultID"`
+	CreatedBy string              `json:"createdBy"`
+	CreatedAt string              `json:"createdAt"`
+}
+
+type PaymentMethodUpdate struct {
+	Name   *string
+	Status *PaymentMethodStatus
+}
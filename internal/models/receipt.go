@@ -0,0 +1,13 @@
+package models
+
+type Receipt struct {
+	ID          string `json:"id"`
+	ExpenseID   string `json:"expenseID"`
+	FileName    string `json:"fileName"`
+	ContentType string `json:"contentType"`
+	Size        int64  `json:"size"`
+	Checksum    string `json:"checksum"`
+	StorageKey  string `json:"-"`
+	CreatedBy   string `json:"createdBy"`
+	CreatedAt   string `json:"createdAt"`
+}
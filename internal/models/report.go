@@ -0,0 +1,30 @@
+package models
+
+type CategoryTotal struct {
+	CategoryID    string          `json:"categoryID"`
+	Name          string          `json:"name"`
+	Total         float64         `json:"total"`
+	RolledUpTotal float64         `json:"rolledUpTotal"`
+	Subcategories []CategoryTotal `json:"subcategories"`
+}
+
+type PaymentMethodTotal struct {
+	PaymentMethodID string              `json:"paymentMethodID"`
+	Name            string              `json:"name"`
+	Status          PaymentMethodStatus `json:"status"`
+	Total           float64             `json:"total"`
+}
+
+type MonthlyCashflow struct {
+	Month    string  `json:"month"`
+	Income   float64 `json:"income"`
+	Expenses float64 `json:"expenses"`
+	Net      float64 `json:"net"`
+}
+
+type TagTotal struct {
+	TagID string  `json:"tagID"`
+	Name  string  `json:"name"`
+	Count int     `json:"count"`
+	Total float64 `json:"total"`
+}
@@ -0,0 +1,15 @@
+package models
+
+// ExpenseSearchResult is an expense matching a search query. Matched terms in
+// Highlights are wrapped in <mark></mark>.
+type ExpenseSearchResult struct {
+	Expense
+	Score      float64          `json:"score"`
+	Highlights SearchHighlights `json:"highlights"`
+}
+
+type SearchHighlights struct {
+	Name     string `json:"name"`
+	Notes    string `json:"notes"`
+	Category string `json:"category"`
+}
@@ -0,0 +1,56 @@
+package models
+
+type SplitMethod string
+
+var (
+	SplitMethodEqual   SplitMethod = "equal"
+	SplitMethodPercent SplitMethod = "percent"
+	SplitMethodExact   SplitMethod = "exact"
+	SplitMethodShares  SplitMethod = "shares"
+)
+
+// SplitShare is a participant of a split. Meaning of Value depends on the split method:
+// it is ignored for equal splits, and is a percentage, an exact amount or a number of shares otherwise.
+type SplitShare struct {
+	UserID string  `json:"userID"`
+	Value  float64 `json:"value"`
+}
+
+// SplitUpdate describes how to split an expense between participants, see splits.Compute.
+type SplitUpdate struct {
+	Method SplitMethod
+	Shares []SplitShare
+}
+
+// ExpenseSplit is the part of an expense owed by a participant. Share is the value of the
+// participant's SplitShare that Amount was computed from.
+type ExpenseSplit struct {
+	UserID string  `json:"userID"`
+	Amount float64 `json:"amount"`
+	Share  float64 `json:"share"`
+}
+
+// MemberBalance is positive when the member is owed money and negative when the member owes money.
+type MemberBalance struct {
+	UserID    string  `json:"userID"`
+	FirstName string  `json:"firstName"`
+	LastName  string  `json:"lastName"`
+	Balance   float64 `json:"balance"`
+}
+
+type SettlementSuggestion struct {
+	FromUserID string  `json:"fromUserID"`
+	ToUserID   string  `json:"toUserID"`
+	Amount     float64 `json:"amount"`
+}
+
+type Settlement struct {
+	ID         string  `json:"id"`
+	FromUserID string  `json:"fromUserID"`
+	ToUserID   string  `json:"toUserID"`
+	Amount     float64 `json:"amount"`
+	Date       string  `json:"date"`
+	VaultID    string  `json:"vaultID"`
+	CreatedBy  string  `json:"createdBy"`
+	CreatedAt  string  `json:"createdAt"`
+}
@@ -0,0 +1,9 @@
+package models
+
+type Tag struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	VaultID   string `json:"vaultID"`
+	CreatedBy string `json:"createdBy"`
+	CreatedAt string `json:"createdAt"`
+}
@@ -4,7 +4,9 @@ type VaultRole string
 
 const (
 	VaultRoleOwner  VaultRole = "owner"
+	VaultRoleAdmin  VaultRole = "admin"
 	VaultRoleEditor VaultRole = "editor"
+	VaultRoleViewer VaultRole = "viewer"
 )
 
 type Vault struct {
@@ -14,7 +16,30 @@ type Vault struct {
 }
 
 type UserVaultWithRole struct {
-	ID       string    `json:"ID"`
-	Name     string    `json:"name"`
-	UserRole VaultRole `json:"userRole"`
+	ID                     string    `json:"ID"`
+	Name                   string    `json:"name"`
+	Description            string    `json:"description"`
+	Icon                   string    `json:"icon"`
+	Color                  string    `json:"color"`
+	BaseCurrency           string    `json:"baseCurrency"`
+	DefaultPaymentMethodID string    `json:"defaultPaymentMethodID"`
+	UserRole               VaultRole `json:"userRole"`
+	DeletedAt              string    `json:"deletedAt,omitempty"`
+}
+
+type VaultUpdate struct {
+	Name                   *string
+	Description            *string
+	Icon                   *string
+	Color                  *string
+	BaseCurrency           *string
+	DefaultPaymentMethodID *string
+}
+
+type VaultMember struct {
+	UserID    string    `json:"userID"`
+	FirstName string    `json:"firstName"`
+	LastName  string    `json:"lastName"`
+	Email     string    `json:"email"`
+	Role      VaultRole `json:"role"`
 }
@@ -0,0 +1,81 @@
+package permissions
+
+import "github.com/kkstas/tr-backend/internal/models"
+
+type Action string
+
+const (
+	ActionRead                 Action = "read"
+	ActionWriteExpenses        Action = "write_expenses"
+	ActionManageCategories     Action = "manage_categories"
+	ActionManagePaymentMethods Action = "manage_payment_methods"
+	ActionManageAccounts       Action = "manage_accounts"
+	ActionManageTags           Action = "manage_tags"
+	ActionManageMembers        Action = "manage_members"
+	ActionManageVault          Action = "manage_vault"
+	ActionDeleteVault          Action = "delete_vault"
+	ActionTransferOwnership    Action = "transfer_ownership"
+)
+
+var rolePermissions = map[models.VaultRole]map[Action]bool{
+	models.VaultRoleOwner: {
+		ActionRead:                 true,
+		ActionWriteExpenses:        true,
+		ActionManageCategories:     true,
+		ActionManagePaymentMethods: true,
+		ActionManageAccounts:       true,
+		ActionManageTags:           true,
+		ActionManageMembers:        true,
+		ActionManageVault:          true,
+		ActionDeleteVault:          true,
+		ActionTransferOwnership:    true,
+	},
+	models.VaultRoleAdmin: {
+		ActionRead:                 true,
+		ActionWriteExpenses:        true,
+		ActionManageCategories:     true,
+		ActionManagePaymentMethods: true,
+		ActionManageAccounts:       true,
+		ActionManageTags:           true,
+		ActionManageMembers:        true,
+		ActionManageVault:          true,
+	},
+	models.VaultRoleEditor: {
+		ActionRead:          true,
+		ActionWriteExpenses: true,
+		ActionManageTags:    true,
+	},
+	models.VaultRoleViewer: {
+		ActionRead: true,
+	},
+}
+
+func Can(role models.VaultRole, action Action) bool {
+	return rolePermissions[role][action]
+}
+
+// CanAssignRole reports whether a member with given role is allowed to grant
+// assignedRole to another member. Nobody can grant a role higher than their own,
+// and ownership can only be handed over by an owner.
+func CanAssignRole(role, assignedRole models.VaultRole) bool {
+	if !Can(role, ActionManageMembers) {
+		return false
+	}
+	assignedRank := rank(assignedRole)
+	return assignedRank > 0 && assignedRank <= rank(role)
+}
+
+func rank(role models.VaultRole) int {
+	switch role {
+	case models.VaultRoleOwner:
+		return 4
+	case models.VaultRoleAdmin:
+		return 3
+	case models.VaultRoleEditor:
+		return 2
+	case models.VaultRoleViewer:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,99 @@
+package permissions_test
+
+import (
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/permissions"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestCan(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		role   models.VaultRole
+		action permissions.Action
+		want   bool
+	}{
+		{role: models.VaultRoleOwner, action: permissions.ActionRead, want: true},
+		{role: models.VaultRoleOwner, action: permissions.ActionWriteExpenses, want: true},
+		{role: models.VaultRoleOwner, action: permissions.ActionManageCategories, want: true},
+		{role: models.VaultRoleOwner, action: permissions.ActionManagePaymentMethods, want: true},
+		{role: models.VaultRoleOwner, action: permissions.ActionManageAccounts, want: true},
+		{role: models.VaultRoleOwner, action: permissions.ActionManageTags, want: true},
+		{role: models.VaultRoleOwner, action: permissions.ActionManageMembers, want: true},
+		{role: models.VaultRoleOwner, action: permissions.ActionManageVault, want: true},
+		{role: models.VaultRoleOwner, action: permissions.ActionDeleteVault, want: true},
+		{role: models.VaultRoleOwner, action: permissions.ActionTransferOwnership, want: true},
+
+		{role: models.VaultRoleAdmin, action: permissions.ActionRead, want: true},
+		{role: models.VaultRoleAdmin, action: permissions.ActionWriteExpenses, want: true},
+		{role: models.VaultRoleAdmin, action: permissions.ActionManageCategories, want: true},
+		{role: models.VaultRoleAdmin, action: permissions.ActionManagePaymentMethods, want: true},
+		{role: models.VaultRoleAdmin, action: permissions.ActionManageAccounts, want: true},
+		{role: models.VaultRoleAdmin, action: permissions.ActionManageTags, want: true},
+		{role: models.VaultRoleAdmin, action: permissions.ActionManageMembers, want: true},
+		{role: models.VaultRoleAdmin, action: permissions.ActionManageVault, want: true},
+		{role: models.VaultRoleAdmin, action: permissions.ActionDeleteVault, want: false},
+		{role: models.VaultRoleAdmin, action: permissions.ActionTransferOwnership, want: false},
+
+		{role: models.VaultRoleEditor, action: permissions.ActionRead, want: true},
+		{role: models.VaultRoleEditor, action: permissions.ActionWriteExpenses, want: true},
+		{role: models.VaultRoleEditor, action: permissions.ActionManageCategories, want: false},
+		{role: models.VaultRoleEditor, action: permissions.ActionManagePaymentMethods, want: false},
+		{role: models.VaultRoleEditor, action: permissions.ActionManageAccounts, want: false},
+		{role: models.VaultRoleEditor, action: permissions.ActionManageTags, want: true},
+		{role: models.VaultRoleEditor, action: permissions.ActionManageMembers, want: false},
+		{role: models.VaultRoleEditor, action: permissions.ActionManageVault, want: false},
+		{role: models.VaultRoleEditor, action: permissions.ActionDeleteVault, want: false},
+		{role: models.VaultRoleEditor, action: permissions.ActionTransferOwnership, want: false},
+
+		{role: models.VaultRoleViewer, action: permissions.ActionRead, want: true},
+		{role: models.VaultRoleViewer, action: permissions.ActionWriteExpenses, want: false},
+		{role: models.VaultRoleViewer, action: permissions.ActionManageCategories, want: false},
+		{role: models.VaultRoleViewer, action: permissions.ActionManagePaymentMethods, want: false},
+		{role: models.VaultRoleViewer, action: permissions.ActionManageAccounts, want: false},
+		{role: models.VaultRoleViewer, action: permissions.ActionManageTags, want: false},
+		{role: models.VaultRoleViewer, action: permissions.ActionManageMembers, want: false},
+		{role: models.VaultRoleViewer, action: permissions.ActionManageVault, want: false},
+		{role: models.VaultRoleViewer, action: permissions.ActionDeleteVault, want: false},
+		{role: models.VaultRoleViewer, action: permissions.ActionTransferOwnership, want: false},
+
+		{role: models.VaultRole("unknown"), action: permissions.ActionRead, want: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(string(tc.role)+"_"+string(tc.action), func(t *testing.T) {
+			t.Parallel()
+			testutils.AssertEqual(t, permissions.Can(tc.role, tc.action), tc.want)
+		})
+	}
+}
+
+func TestCanAssignRole(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		role         models.VaultRole
+		assignedRole models.VaultRole
+		want         bool
+	}{
+		{role: models.VaultRoleOwner, assignedRole: models.VaultRoleOwner, want: true},
+		{role: models.VaultRoleOwner, assignedRole: models.VaultRoleAdmin, want: true},
+		{role: models.VaultRoleOwner, assignedRole: models.VaultRoleViewer, want: true},
+		{role: models.VaultRoleAdmin, assignedRole: models.VaultRoleOwner, want: false},
+		{role: models.VaultRoleAdmin, assignedRole: models.VaultRoleAdmin, want: true},
+		{role: models.VaultRoleAdmin, assignedRole: models.VaultRoleEditor, want: true},
+		{role: models.VaultRoleEditor, assignedRole: models.VaultRoleViewer, want: false},
+		{role: models.VaultRoleViewer, assignedRole: models.VaultRoleViewer, want: false},
+		{role: models.VaultRoleOwner, assignedRole: models.VaultRole("unknown"), want: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(string(tc.role)+"_assigns_"+string(tc.assignedRole), func(t *testing.T) {
+			t.Parallel()
+			testutils.AssertEqual(t, permissions.CanAssignRole(tc.role, tc.assignedRole), tc.want)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package problem
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/splits"
+)
+
+const (
+	CodeInvalidBody      = "invalid_body"
+	CodeValidationFailed = "validation_failed"
+	CodeInvalid          = "invalid"
+	CodeRequired         = "validation_required"
+	CodeUnauthorized     = "unauthorized"
+	CodeInvalidLogin     = "invalid_credentials"
+	CodeNotFound         = "not_found"
+	CodeInternal         = "internal_error"
+)
+
+type mapping struct {
+	err    error
+	status int
+	code   string
+	// wrapped errors carry a message meant for clients, e.g. why a split is invalid
+	exposeWrapped bool
+}
+
+func (m mapping) message(err error) string {
+	if m.exposeWrapped {
+		return err.Error()
+	}
+	return m.err.Error()
+}
+
+var mappings = []mapping{
+	{err: services.ErrUserNotFound, status: http.StatusNotFound, code: "user_not_found"},
+	{err: services.ErrUserEmailAlreadyExists, status: http.StatusConflict, code: "user_email_taken"},
+
+	{err: services.ErrVaultNotFound, status: http.StatusNotFound, code: "vault_not_found"},
+	{err: services.ErrInsufficientVaultPermissions, status: http.StatusForbidden, code: "insufficient_vault_permissions"},
+	{err: services.ErrUserAlreadyAssignedToVault, status: http.StatusConflict, code: "user_already_in_vault"},
+	{err: services.ErrVaultWithThatNameAlreadyExists, status: http.StatusConflict, code: "vault_name_taken"},
+	{err: services.ErrUserNotAssignedToVault, status: http.StatusBadRequest, code: "user_not_in_vault"},
+	{err: services.ErrUserAlreadyVaultOwner, status: http.StatusConflict, code: "user_already_vault_owner"},
+	{err: services.ErrInvalidAuditCursor, status: http.StatusBadRequest, code: "invalid_cursor"},
+
+	{err: services.ErrExpenseCategoryNotFound, status: http.StatusNotFound, code: "expense_category_not_found"},
+	{err: services.ErrExpenseCategoryWithThatNameAlreadyExists, status: http.StatusConflict, code: "expense_category_name_taken"},
+	{err: services.ErrExpenseCategoryInactive, status: http.StatusBadRequest, code: "expense_category_inactive"},
+	{err: services.ErrInvalidExpenseCategoryOrder, status: http.StatusBadRequest, code: "invalid_expense_category_order"},
+	{err: services.ErrCannotMergeExpenseCategoryIntoItself, status: http.StatusBadRequest, code: "expense_category_self_merge"},
+	{err: services.ErrInvalidExpenseCategoryParent, status: http.StatusBadRequest, code: "invalid_expense_category_parent"},
+	{err: services.ErrExpenseCategoryCycle, status: http.StatusBadRequest, code: "expense_category_cycle"},
+	{err: services.ErrCategoryTemplateNotFound, status: http.StatusNotFound, code: "category_template_not_found"},
+
+	{err: services.ErrExpenseNotFound, status: http.StatusNotFound, code: "expense_not_found"},
+	{err: splits.ErrInvalidSplit, status: http.StatusBadRequest, code: "invalid_split", exposeWrapped: true},
+
+	{err: services.ErrPaymentMethodNotFound, status: http.StatusNotFound, code: "payment_method_not_found"},
+	{err: services.ErrPaymentMethodWithThatNameAlreadyExists, status: http.StatusConflict, code: "payment_method_name_taken"},
+	{err: services.ErrPaymentMethodArchived, status: http.StatusBadRequest, code: "payment_method_archived"},
+	{err: services.ErrPaymentMethodInUse, status: http.StatusConflict, code: "payment_method_in_use"},
+
+	{err: services.ErrAccountNotFound, status: http.StatusNotFound, code: "account_not_found"},
+	{err: services.ErrAccountWithThatNameAlreadyExists, status: http.StatusConflict, code: "account_name_taken"},
+	{err: services.ErrAccountInUse, status: http.StatusConflict, code: "account_in_use"},
+
+	{err: services.ErrTransferNotFound, status: http.StatusNotFound, code: "transfer_not_found"},
+	{err: services.ErrTransferToSameAccount, status: http.StatusBadRequest, code: "transfer_to_same_account"},
+
+	{err: services.ErrIncomeNotFound, status: http.StatusNotFound, code: "income_not_found"},
+
+	{err: services.ErrTagNotFound, status: http.StatusNotFound, code: "tag_not_found"},
+	{err: services.ErrTagWithThatNameAlreadyExists, status: http.StatusConflict, code: "tag_name_taken"},
+
+	{err: services.ErrSettlementNotFound, status: http.StatusNotFound, code: "settlement_not_found"},
+	{err: services.ErrSettlementWithSelf, status: http.StatusBadRequest, code: "settlement_with_self"},
+
+	{err: services.ErrReceiptNotFound, status: http.StatusNotFound, code: "receipt_not_found"},
+	{err: services.ErrReceiptTooLarge, status: http.StatusRequestEntityTooLarge, code: "receipt_too_large"},
+	{err: services.ErrReceiptTypeNotAllowed, status: http.StatusUnsupportedMediaType, code: "receipt_type_not_allowed"},
+}
+
+func lookup(err error) (mapping, bool) {
+	for _, m := range mappings {
+		if errors.Is(err, m.err) {
+			return m, true
+		}
+	}
+	return mapping{}, false // nolint: exhaustruct
+}
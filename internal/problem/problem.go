@@ -0,0 +1,161 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sort"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const ContentType = "application/problem+json"
+
+const typePrefix = "urn:tr-backend:problem:"
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Problem is an RFC 7807 problem details object. Code is a stable machine-readable
+// identifier that clients can rely on, Type is the same identifier as a URN.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Code     string       `json:"code"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+func New(status int, code, detail string) *Problem {
+	return &Problem{ // nolint: exhaustruct
+		Type:   typePrefix + code,
+		Title:  http.StatusText(status),
+		Status: status,
+		Code:   code,
+		Detail: detail,
+	}
+}
+
+func InvalidBody() *Problem {
+	return New(http.StatusBadRequest, CodeInvalidBody, "failed to decode request body")
+}
+
+func Unauthorized() *Problem {
+	return New(http.StatusUnauthorized, CodeUnauthorized, "missing or invalid access token")
+}
+
+func NotFound() *Problem {
+	return New(http.StatusNotFound, CodeNotFound, "resource not found")
+}
+
+func Internal() *Problem {
+	return New(http.StatusInternalServerError, CodeInternal, "internal server error")
+}
+
+// Invalid reports a single invalid request field.
+func Invalid(field, code, message string) *Problem {
+	p := New(http.StatusBadRequest, CodeValidationFailed, "request contains invalid fields")
+	p.Errors = []FieldError{{Field: field, Code: code, Message: message}}
+	return p
+}
+
+// Field reports err as a problem with the given request field, e.g. a category ID
+// that points to a missing category is a bad request rather than a missing resource.
+func Field(field string, err error) *Problem {
+	m, ok := lookup(err)
+	if !ok {
+		return Invalid(field, CodeInvalid, "invalid value")
+	}
+	return Invalid(field, m.code, m.message(err))
+}
+
+// BadRequest reports err with its own code but as a bad request, for errors
+// caused by request body values that can't be tied to a single field.
+func BadRequest(err error) *Problem {
+	p := FromError(err)
+	if p.Status >= http.StatusInternalServerError {
+		return p
+	}
+	p.Status, p.Title = http.StatusBadRequest, http.StatusText(http.StatusBadRequest)
+	return p
+}
+
+// Validation converts errors returned by ozzo validation into field-level details.
+// Nested errors are flattened into dotted field names, e.g. "split.method".
+func Validation(err error) *Problem {
+	var errs validation.Errors
+	if !errors.As(err, &errs) {
+		var verr validation.Error
+		if errors.As(err, &verr) {
+			return Invalid("", verr.Code(), verr.Error())
+		}
+		return FromError(err)
+	}
+
+	p := New(http.StatusBadRequest, CodeValidationFailed, "request contains invalid fields")
+	p.Errors = flatten("", errs)
+	sort.Slice(p.Errors, func(i, j int) bool { return p.Errors[i].Field < p.Errors[j].Field })
+	return p
+}
+
+func flatten(prefix string, errs validation.Errors) []FieldError {
+	var fieldErrors []FieldError
+	for field, err := range errs {
+		if err == nil {
+			continue
+		}
+		if prefix != "" {
+			field = prefix + "." + field
+		}
+
+		var nested validation.Errors
+		if errors.As(err, &nested) {
+			fieldErrors = append(fieldErrors, flatten(field, nested)...)
+			continue
+		}
+
+		code := CodeInvalid
+		var verr validation.Error
+		if errors.As(err, &verr) {
+			code = verr.Code()
+		}
+		fieldErrors = append(fieldErrors, FieldError{Field: field, Code: code, Message: err.Error()})
+	}
+	return fieldErrors
+}
+
+// FromError maps service errors to problems. Unknown errors become an internal
+// error without leaking their message.
+func FromError(err error) *Problem {
+	m, ok := lookup(err)
+	if !ok {
+		return Internal()
+	}
+	return New(m.status, m.code, m.message(err))
+}
+
+func Write(w http.ResponseWriter, r *http.Request, p *Problem) {
+	if p.Instance == "" && r != nil {
+		p.Instance = r.URL.Path
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// Error writes the problem for err and logs errors that map to a server error.
+func Error(w http.ResponseWriter, r *http.Request, logger *slog.Logger, err error, msg string, args ...any) {
+	p := FromError(err)
+	if p.Status >= http.StatusInternalServerError {
+		logger.ErrorContext(r.Context(), msg, append(args, "error", err)...)
+	}
+	Write(w, r, p)
+}
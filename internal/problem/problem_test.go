@@ -0,0 +1,107 @@
+package problem_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+
+	"github.com/kkstas/tr-backend/internal/problem"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/splits"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestFromError(t *testing.T) {
+	t.Parallel()
+
+	t.Run("maps wrapped service errors to their status and code", func(t *testing.T) {
+		t.Parallel()
+		p := problem.FromError(fmt.Errorf("failed to find vault abc: %w", services.ErrVaultNotFound))
+
+		testutils.AssertEqual(t, p.Status, http.StatusNotFound)
+		testutils.AssertEqual(t, p.Code, "vault_not_found")
+		testutils.AssertEqual(t, p.Type, "urn:tr-backend:problem:vault_not_found")
+		testutils.AssertEqual(t, p.Detail, services.ErrVaultNotFound.Error())
+	})
+
+	t.Run("maps insufficient permissions to 403", func(t *testing.T) {
+		t.Parallel()
+		p := problem.FromError(services.ErrInsufficientVaultPermissions)
+
+		testutils.AssertEqual(t, p.Status, http.StatusForbidden)
+		testutils.AssertEqual(t, p.Code, "insufficient_vault_permissions")
+	})
+
+	t.Run("does not leak unknown errors", func(t *testing.T) {
+		t.Parallel()
+		p := problem.FromError(errors.New("sql: database is locked"))
+
+		testutils.AssertEqual(t, p.Status, http.StatusInternalServerError)
+		testutils.AssertEqual(t, p.Code, problem.CodeInternal)
+		testutils.AssertEqual(t, p.Detail, "internal server error")
+	})
+}
+
+func TestField(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reports service error as field error", func(t *testing.T) {
+		t.Parallel()
+		p := problem.Field("categoryID", services.ErrExpenseCategoryNotFound)
+
+		testutils.AssertEqual(t, p.Status, http.StatusBadRequest)
+		testutils.AssertEqual(t, p.Code, problem.CodeValidationFailed)
+		testutils.AssertEqual(t, len(p.Errors), 1)
+		testutils.AssertEqual(t, p.Errors[0], problem.FieldError{
+			Field:   "categoryID",
+			Code:    "expense_category_not_found",
+			Message: "expense category not found",
+		})
+	})
+
+	t.Run("exposes wrapped message of split errors", func(t *testing.T) {
+		t.Parallel()
+		err := fmt.Errorf("%w: percentages must add up to 100", splits.ErrInvalidSplit)
+		p := problem.Field("split", err)
+
+		testutils.AssertEqual(t, p.Errors[0].Message, err.Error())
+	})
+}
+
+func TestValidation(t *testing.T) {
+	t.Parallel()
+
+	type split struct {
+		Method string `json:"method"`
+	}
+
+	s := split{} // nolint: exhaustruct
+	nested := validation.ValidateStruct(&s, validation.Field(&s.Method, validation.Required))
+
+	p := problem.Validation(validation.Errors{"name": validation.ErrRequired, "split": nested})
+
+	testutils.AssertEqual(t, p.Status, http.StatusBadRequest)
+	testutils.AssertEqual(t, len(p.Errors), 2)
+	testutils.AssertEqual(t, p.Errors[0].Field, "name")
+	testutils.AssertEqual(t, p.Errors[0].Code, problem.CodeRequired)
+	testutils.AssertEqual(t, p.Errors[1].Field, "split.method")
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	request := httptest.NewRequest("GET", "/vaults/abc", nil)
+	response := httptest.NewRecorder()
+	problem.Write(response, request, problem.FromError(services.ErrVaultNotFound))
+
+	testutils.AssertStatus(t, response.Code, http.StatusNotFound)
+	testutils.AssertEqual(t, response.Header().Get("Content-Type"), problem.ContentType)
+
+	p := testutils.DecodeJSON[problem.Problem](t, response.Body)
+	testutils.AssertEqual(t, p.Instance, "/vaults/abc")
+	testutils.AssertEqual(t, p.Title, "Not Found")
+}
@@ -0,0 +1,166 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/kkstas/tr-backend/internal/database"
+	"github.com/kkstas/tr-backend/internal/models"
+)
+
+var ErrAccountNotFound = errors.New("account not found")
+var ErrAccountInUse = errors.New("account is used by expenses, incomes or transfers")
+
+// accountBalanceColumn computes current balance of account aliased as a from the whole ledger.
+const accountBalanceColumn = `a.opening_balance
+	- COALESCE((SELECT SUM(e.amount) FROM expenses e WHERE e.account_id = a.id AND e.deleted_at IS NULL), 0)
+	+ COALESCE((SELECT SUM(i.amount) FROM incomes i WHERE i.account_id = a.id), 0)
+	+ COALESCE((SELECT SUM(t.amount) FROM transfers t WHERE t.to_account_id = a.id), 0)
+	- COALESCE((SELECT SUM(t.amount) FROM transfers t WHERE t.from_account_id = a.id), 0)`
+
+type AccountRepo struct {
+	db *database.DB
+}
+
+func NewAccountRepo(db *database.DB) *AccountRepo {
+	return &AccountRepo{db: db}
+}
+
+func (r *AccountRepo) CreateOne(ctx context.Context, account models.Account) (accountID string, err error) {
+	accountID = uuid.New().String()
+
+	_, err = r.db.Writer(ctx).ExecContext(ctx, `
+		INSERT INTO accounts(id, name, type, opening_balance, vault_id, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		accountID, account.Name, account.Type, account.OpeningBalance, account.VaultID, account.CreatedBy)
+	if err != nil {
+		return "", fmt.Errorf("failed to insert account: %w", err)
+	}
+	return accountID, nil
+}
+
+func (r *AccountRepo) FindAll(ctx context.Context, vaultID string) ([]models.Account, error) {
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, `
+		SELECT a.id, a.name, a.type, a.opening_balance, `+accountBalanceColumn+`, a.vault_id, a.created_by, a.created_at
+		FROM accounts a
+		WHERE a.vault_id = $1
+		ORDER BY a.name`, vaultID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute find all accounts query for vault %s: %w", vaultID, err)
+	}
+	defer rows.Close()
+
+	accounts := []models.Account{}
+
+	for rows.Next() {
+		var a models.Account
+		err := rows.Scan(&a.ID, &a.Name, &a.Type, &a.OpeningBalance, &a.Balance, &a.VaultID, &a.CreatedBy, &a.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, a)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func (r *AccountRepo) FindOneByID(ctx context.Context, accountID string) (*models.Account, error) {
+	a := models.Account{} // nolint: exhaustruct
+
+	err := r.db.Reader(ctx).QueryRowContext(ctx, `
+		SELECT a.id, a.name, a.type, a.opening_balance, `+accountBalanceColumn+`, a.vault_id, a.created_by, a.created_at
+		FROM accounts a
+		WHERE a.id = $1
+		`, accountID).Scan(&a.ID, &a.Name, &a.Type, &a.OpeningBalance, &a.Balance, &a.VaultID, &a.CreatedBy, &a.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+func (r *AccountRepo) UpdateOne(ctx context.Context, account *models.Account) error {
+	_, err := r.db.Writer(ctx).ExecContext(ctx, `
+		UPDATE accounts
+		SET name = $1, type = $2, opening_balance = $3
+		WHERE id = $4`, account.Name, account.Type, account.OpeningBalance, account.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update account %s: %w", account.ID, err)
+	}
+	return nil
+}
+
+// DeleteOneByID deletes account only if nothing in the ledger, including deleted expenses
+// still kept in trash, refers to it. Otherwise ErrAccountInUse is returned.
+func (r *AccountRepo) DeleteOneByID(ctx context.Context, accountID string) error {
+	res, err := r.db.Writer(ctx).ExecContext(ctx, `
+		DELETE FROM accounts
+		WHERE id = $1
+			AND NOT EXISTS (SELECT 1 FROM expenses WHERE account_id = $1)
+			AND NOT EXISTS (SELECT 1 FROM incomes WHERE account_id = $1)
+			AND NOT EXISTS (SELECT 1 FROM transfers WHERE from_account_id = $1 OR to_account_id = $1)`, accountID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete account %s: %w", accountID, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to check deleted rows: %w", err)
+	} else if n == 0 {
+		return ErrAccountInUse
+	}
+	return nil
+}
+
+// FindLedgerEntries returns every entry that changed balance of the account, oldest first.
+// Balance of returned entries is left empty.
+func (r *AccountRepo) FindLedgerEntries(ctx context.Context, accountID string) ([]models.LedgerEntry, error) {
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, `
+		SELECT kind, id, date, description, amount FROM (
+			SELECT $2 AS kind, id, date, name AS description, -amount AS amount, created_at
+			FROM expenses WHERE account_id = $1 AND deleted_at IS NULL
+			UNION ALL
+			SELECT $3, id, date, source, amount, created_at
+			FROM incomes WHERE account_id = $1
+			UNION ALL
+			SELECT $4, id, date, description, amount, created_at
+			FROM transfers WHERE to_account_id = $1
+			UNION ALL
+			SELECT $5, id, date, description, -amount, created_at
+			FROM transfers WHERE from_account_id = $1
+		)
+		ORDER BY date, created_at, id`,
+		accountID, models.LedgerEntryKindExpense, models.LedgerEntryKindIncome, models.LedgerEntryKindTransferIn, models.LedgerEntryKindTransferOut,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute find ledger entries query for account %s: %w", accountID, err)
+	}
+	defer rows.Close()
+
+	entries := []models.LedgerEntry{}
+
+	for rows.Next() {
+		var e models.LedgerEntry
+		err := rows.Scan(&e.Kind, &e.ID, &e.Date, &e.Description, &e.Amount)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,99 @@
+package repositories_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/repositories"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestAccountRepo_FindOneByID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("computes balance from opening balance, expenses and transfers", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		accountRepo := repositories.NewAccountRepo(db)
+		transferRepo := repositories.NewTransferRepo(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		checking := testutils.CreateTestAccount(t, db, user.ID, vault.ID, 1000)
+		savings := testutils.CreateTestAccount(t, db, user.ID, vault.ID, 0)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		paymentMethod := testutils.CreateTestPaymentMethod(t, db, user.ID, vault.ID)
+
+		_, err := repositories.NewExpenseRepo(db).CreateOne(ctx, models.Expense{ // nolint: exhaustruct
+			Name:            "groceries",
+			Date:            "2025-01-02",
+			CategoryID:      category.ID,
+			Amount:          100,
+			PaymentMethodID: paymentMethod.ID,
+			AccountID:       checking.ID,
+			VaultID:         vault.ID,
+			CreatedBy:       user.ID,
+		})
+		testutils.AssertNoError(t, err)
+
+		_, err = transferRepo.CreateOne(ctx, models.Transfer{ // nolint: exhaustruct
+			FromAccountID: checking.ID,
+			ToAccountID:   savings.ID,
+			Amount:        300,
+			Date:          "2025-01-03",
+			VaultID:       vault.ID,
+			CreatedBy:     user.ID,
+		})
+		testutils.AssertNoError(t, err)
+
+		found, err := accountRepo.FindOneByID(ctx, checking.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, found.Balance, 600.0)
+
+		found, err = accountRepo.FindOneByID(ctx, savings.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, found.Balance, 300.0)
+
+		entries, err := accountRepo.FindLedgerEntries(ctx, checking.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(entries), 2)
+		testutils.AssertEqual(t, entries[0].Kind, models.LedgerEntryKindExpense)
+		testutils.AssertEqual(t, entries[0].Amount, -100.0)
+		testutils.AssertEqual(t, entries[1].Kind, models.LedgerEntryKindTransferOut)
+		testutils.AssertEqual(t, entries[1].Amount, -300.0)
+	})
+}
+
+func TestAccountRepo_DeleteOneByID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns error if account is used by transfer", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		accountRepo := repositories.NewAccountRepo(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		from := testutils.CreateTestAccount(t, db, user.ID, vault.ID, 100)
+		to := testutils.CreateTestAccount(t, db, user.ID, vault.ID, 0)
+		unused := testutils.CreateTestAccount(t, db, user.ID, vault.ID, 0)
+
+		_, err := repositories.NewTransferRepo(db).CreateOne(ctx, models.Transfer{ // nolint: exhaustruct
+			FromAccountID: from.ID,
+			ToAccountID:   to.ID,
+			Amount:        10,
+			Date:          "2025-01-03",
+			VaultID:       vault.ID,
+			CreatedBy:     user.ID,
+		})
+		testutils.AssertNoError(t, err)
+
+		err = accountRepo.DeleteOneByID(ctx, to.ID)
+		if !errors.Is(err, repositories.ErrAccountInUse) {
+			t.Errorf("expected error %q, got %v", repositories.ErrAccountInUse, err)
+		}
+
+		err = accountRepo.DeleteOneByID(ctx, unused.ID)
+		testutils.AssertNoError(t, err)
+	})
+}
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/kkstas/tr-backend/internal/database"
+	"github.com/kkstas/tr-backend/internal/models"
+)
+
+type AuditRepo struct {
+	db *database.DB
+}
+
+func NewAuditRepo(db *database.DB) *AuditRepo {
+	return &AuditRepo{db: db}
+}
+
+func (r *AuditRepo) CreateOne(ctx context.Context, event models.AuditEvent) error {
+	_, err := r.db.Writer(ctx).ExecContext(ctx, `
+		INSERT INTO audit_events(vault_id, actor_id, action, entity_type, entity_id, before, after)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		event.VaultID, event.ActorID, event.Action, event.EntityType, event.EntityID, nullableJSON(event.Before), nullableJSON(event.After))
+	if err != nil {
+		return fmt.Errorf("failed to insert audit event: %w", err)
+	}
+	return nil
+}
+
+// FindAll returns up to limit events of the vault, newest first. Only events older than
+// beforeID are returned, unless it is 0. If entityID is not empty, only events of that entity are returned.
+func (r *AuditRepo) FindAll(ctx context.Context, vaultID, entityID string, beforeID int64, limit int) ([]models.AuditEvent, error) {
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, `
+		SELECT id, vault_id, actor_id, action, entity_type, entity_id, before, after, created_at
+		FROM audit_events
+		WHERE vault_id = $1 AND ($2 = '' OR entity_id = $2) AND ($3 = 0 OR id < $3)
+		ORDER BY id DESC
+		LIMIT $4`, vaultID, entityID, beforeID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute find all audit events query for vault %s: %w", vaultID, err)
+	}
+	defer rows.Close()
+
+	events := []models.AuditEvent{}
+
+	for rows.Next() {
+		var e models.AuditEvent
+		var before, after sql.NullString
+		err := rows.Scan(&e.ID, &e.VaultID, &e.ActorID, &e.Action, &e.EntityType, &e.EntityID, &before, &after, &e.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if before.Valid {
+			e.Before = []byte(before.String)
+		}
+		if after.Valid {
+			e.After = []byte(after.String)
+		}
+		events = append(events, e)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func nullableJSON(data []byte) sql.NullString {
+	return sql.NullString{String: string(data), Valid: len(data) > 0}
+}
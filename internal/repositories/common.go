@@ -0,0 +1,9 @@
+package repositories
+
+import "time"
+
+// formatTime formats t the same way SQLite's CURRENT_TIMESTAMP does, so it can be
+// compared with DATETIME columns populated by it.
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.DateTime)
+}
@@ -0,0 +1,390 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kkstas/tr-backend/internal/database"
+	"github.com/kkstas/tr-backend/internal/models"
+)
+
+var ErrExpenseNotFound = errors.New("expense not found")
+
+type ExpenseRepo struct {
+	db *database.DB
+}
+
+func NewExpenseRepo(db *database.DB) *ExpenseRepo {
+	return &ExpenseRepo{db: db}
+}
+
+func (r *ExpenseRepo) CreateOne(ctx context.Context, expense models.Expense) (expenseID string, err error) {
+	expenseID = uuid.New().String()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback() // nolint: errcheck
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO expenses(id, name, notes, date, category_id, amount, payment_method_id, account_id, paid_by, split_method, vault_id, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), NULLIF($9, ''), NULLIF($10, ''), $11, $12)`,
+		expenseID, expense.Name, expense.Notes, expense.Date, expense.CategoryID, expense.Amount, expense.PaymentMethodID, expense.AccountID, expense.PaidBy, expense.SplitMethod, expense.VaultID, expense.CreatedBy)
+	if err != nil {
+		return "", fmt.Errorf("failed to insert expense: %w", err)
+	}
+
+	for _, split := range expense.Splits {
+		_, err = tx.ExecContext(ctx, `INSERT INTO expense_splits(expense_id, user_id, amount, share) VALUES ($1, $2, $3, $4)`, expenseID, split.UserID, split.Amount, split.Share)
+		if err != nil {
+			return "", fmt.Errorf("failed to insert split of expense %s for user %s: %w", expenseID, split.UserID, err)
+		}
+	}
+
+	if err := insertExpenseTags(ctx, tx, expenseID, expense.TagIDs); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return expenseID, nil
+}
+
+// FindAll returns non-deleted expenses of the vault. If tagIDs are given, only expenses
+// tagged with all of them are returned.
+func (r *ExpenseRepo) FindAll(ctx context.Context, vaultID string, tagIDs ...string) ([]models.Expense, error) {
+	if tagIDs == nil {
+		tagIDs = []string{}
+	}
+	tagFilter, err := json.Marshal(tagIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode tag filter: %w", err)
+	}
+
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, `
+		SELECT id, name, notes, date, category_id, amount, COALESCE(payment_method_id, ''), COALESCE(account_id, ''), COALESCE(paid_by, created_by), vault_id, created_by, created_at
+		FROM expenses
+		WHERE vault_id = $1 AND deleted_at IS NULL
+			AND (json_array_length($2) = 0 OR id IN (
+				SELECT expense_id FROM expense_tags
+				WHERE tag_id IN (SELECT value FROM json_each($2))
+				GROUP BY expense_id
+				HAVING COUNT(*) = (SELECT COUNT(DISTINCT value) FROM json_each($2))
+			))
+		ORDER BY date DESC, created_at DESC`, vaultID, string(tagFilter),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute find all expenses query for vault %s: %w", vaultID, err)
+	}
+	defer rows.Close()
+
+	expenses := []models.Expense{}
+
+	for rows.Next() {
+		var e models.Expense
+		err := rows.Scan(&e.ID, &e.Name, &e.Notes, &e.Date, &e.CategoryID, &e.Amount, &e.PaymentMethodID, &e.AccountID, &e.PaidBy, &e.VaultID, &e.CreatedBy, &e.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		expenses = append(expenses, e)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return r.attachDetails(ctx, vaultID, "", expenses)
+}
+
+func (r *ExpenseRepo) FindOneByID(ctx context.Context, expenseID string) (*models.Expense, error) {
+	e := models.Expense{} // nolint: exhaustruct
+
+	err := r.db.Reader(ctx).QueryRowContext(ctx, `
+		SELECT id, name, notes, date, category_id, amount, COALESCE(payment_method_id, ''), COALESCE(account_id, ''), COALESCE(paid_by, created_by), vault_id, created_by, created_at
+		FROM expenses
+		WHERE id = $1 AND deleted_at IS NULL
+		`, expenseID).Scan(&e.ID, &e.Name, &e.Notes, &e.Date, &e.CategoryID, &e.Amount, &e.PaymentMethodID, &e.AccountID, &e.PaidBy, &e.VaultID, &e.CreatedBy, &e.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrExpenseNotFound
+		}
+		return nil, err
+	}
+
+	expenses, err := r.attachDetails(ctx, e.VaultID, e.ID, []models.Expense{e})
+	if err != nil {
+		return nil, err
+	}
+	return &expenses[0], nil
+}
+
+// Search returns non-deleted expenses of the vault matching the FTS5 match expression, best matches first.
+// Name matches weigh more than notes, and notes more than category name.
+func (r *ExpenseRepo) Search(ctx context.Context, vaultID, match string, limit int) ([]models.ExpenseSearchResult, error) {
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, `
+		SELECT e.id, e.name, e.notes, e.date, e.category_id, e.amount, COALESCE(e.payment_method_id, ''), COALESCE(e.account_id, ''), COALESCE(e.paid_by, e.created_by), e.vault_id, e.created_by, e.created_at,
+			-bm25(expenses_fts, 10.0, 4.0, 2.0),
+			highlight(expenses_fts, 0, '<mark>', '</mark>'),
+			snippet(expenses_fts, 1, '<mark>', '</mark>', '…', 16),
+			highlight(expenses_fts, 2, '<mark>', '</mark>')
+		FROM expenses_fts
+		JOIN expenses e ON e.rowid = expenses_fts.rowid
+		WHERE expenses_fts MATCH $1 AND e.vault_id = $2 AND e.deleted_at IS NULL
+		ORDER BY bm25(expenses_fts, 10.0, 4.0, 2.0), e.date DESC
+		LIMIT $3`, match, vaultID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute search expenses query for vault %s: %w", vaultID, err)
+	}
+	defer rows.Close()
+
+	results := []models.ExpenseSearchResult{}
+
+	for rows.Next() {
+		var res models.ExpenseSearchResult
+		e := &res.Expense
+		err := rows.Scan(&e.ID, &e.Name, &e.Notes, &e.Date, &e.CategoryID, &e.Amount, &e.PaymentMethodID, &e.AccountID, &e.PaidBy, &e.VaultID, &e.CreatedBy, &e.CreatedAt,
+			&res.Score, &res.Highlights.Name, &res.Highlights.Notes, &res.Highlights.Category)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, res)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	expenses := make([]models.Expense, len(results))
+	for i := range results {
+		expenses[i] = results[i].Expense
+	}
+	expenses, err = r.attachDetails(ctx, vaultID, "", expenses)
+	if err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i].Expense = expenses[i]
+	}
+
+	return results, nil
+}
+
+// UpdateOne updates expense fields and replaces its splits and tags.
+func (r *ExpenseRepo) UpdateOne(ctx context.Context, expense *models.Expense) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback() // nolint: errcheck
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE expenses
+		SET name = $1, notes = $2, date = $3, category_id = $4, amount = $5, payment_method_id = $6, account_id = NULLIF($7, ''), paid_by = $8, split_method = NULLIF($9, '')
+		WHERE id = $10`,
+		expense.Name, expense.Notes, expense.Date, expense.CategoryID, expense.Amount, expense.PaymentMethodID, expense.AccountID, expense.PaidBy, expense.SplitMethod, expense.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update expense %s: %w", expense.ID, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM expense_splits WHERE expense_id = $1`, expense.ID); err != nil {
+		return fmt.Errorf("failed to remove splits of expense %s: %w", expense.ID, err)
+	}
+	for _, split := range expense.Splits {
+		_, err = tx.ExecContext(ctx, `INSERT INTO expense_splits(expense_id, user_id, amount, share) VALUES ($1, $2, $3, $4)`, expense.ID, split.UserID, split.Amount, split.Share)
+		if err != nil {
+			return fmt.Errorf("failed to insert split of expense %s for user %s: %w", expense.ID, split.UserID, err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM expense_tags WHERE expense_id = $1`, expense.ID); err != nil {
+		return fmt.Errorf("failed to remove tags of expense %s: %w", expense.ID, err)
+	}
+	if err := insertExpenseTags(ctx, tx, expense.ID, expense.TagIDs); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ExpenseRepo) DeleteOneByID(ctx context.Context, expenseID string) error {
+	_, err := r.db.Writer(ctx).ExecContext(ctx, `UPDATE expenses SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`, expenseID)
+	if err != nil {
+		return fmt.Errorf("failed to mark expense %s as deleted: %w", expenseID, err)
+	}
+	return nil
+}
+
+func (r *ExpenseRepo) FindAllDeleted(ctx context.Context, vaultID string, deletedAfter time.Time) ([]models.Expense, error) {
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, `
+		SELECT id, name, notes, date, category_id, amount, COALESCE(payment_method_id, ''), COALESCE(account_id, ''), COALESCE(paid_by, created_by), vault_id, created_by, created_at, deleted_at
+		FROM expenses
+		WHERE vault_id = $1 AND deleted_at IS NOT NULL AND deleted_at >= $2
+		ORDER BY deleted_at DESC`, vaultID, formatTime(deletedAfter),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute find deleted expenses query for vault %s: %w", vaultID, err)
+	}
+	defer rows.Close()
+
+	expenses := []models.Expense{}
+
+	for rows.Next() {
+		var e models.Expense
+		err := rows.Scan(&e.ID, &e.Name, &e.Notes, &e.Date, &e.CategoryID, &e.Amount, &e.PaymentMethodID, &e.AccountID, &e.PaidBy, &e.VaultID, &e.CreatedBy, &e.CreatedAt, &e.DeletedAt)
+		if err != nil {
+			return nil, err
+		}
+		expenses = append(expenses, e)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return r.attachDetails(ctx, vaultID, "", expenses)
+}
+
+func (r *ExpenseRepo) FindOneDeletedByID(ctx context.Context, expenseID string, deletedAfter time.Time) (*models.Expense, error) {
+	e := models.Expense{} // nolint: exhaustruct
+
+	err := r.db.Reader(ctx).QueryRowContext(ctx, `
+		SELECT id, name, notes, date, category_id, amount, COALESCE(payment_method_id, ''), COALESCE(account_id, ''), COALESCE(paid_by, created_by), vault_id, created_by, created_at, deleted_at
+		FROM expenses
+		WHERE id = $1 AND deleted_at IS NOT NULL AND deleted_at >= $2
+		`, expenseID, formatTime(deletedAfter)).Scan(&e.ID, &e.Name, &e.Notes, &e.Date, &e.CategoryID, &e.Amount, &e.PaymentMethodID, &e.AccountID, &e.PaidBy, &e.VaultID, &e.CreatedBy, &e.CreatedAt, &e.DeletedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrExpenseNotFound
+		}
+		return nil, err
+	}
+
+	expenses, err := r.attachDetails(ctx, e.VaultID, e.ID, []models.Expense{e})
+	if err != nil {
+		return nil, err
+	}
+	return &expenses[0], nil
+}
+
+func (r *ExpenseRepo) RestoreOneByID(ctx context.Context, expenseID string) error {
+	_, err := r.db.Writer(ctx).ExecContext(ctx, `UPDATE expenses SET deleted_at = NULL WHERE id = $1`, expenseID)
+	if err != nil {
+		return fmt.Errorf("failed to restore expense %s: %w", expenseID, err)
+	}
+	return nil
+}
+
+func (r *ExpenseRepo) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	res, err := r.db.Writer(ctx).ExecContext(ctx, `DELETE FROM expenses WHERE deleted_at IS NOT NULL AND deleted_at < $1`, formatTime(deletedBefore))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted expenses: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// attachDetails fills Splits and TagIDs of given expenses belonging to the vault, limiting queries
+// to a single expense if expenseID is not empty. Expenses without splits or tags get empty slices.
+func (r *ExpenseRepo) attachDetails(ctx context.Context, vaultID, expenseID string, expenses []models.Expense) ([]models.Expense, error) {
+	expenses, err := r.attachSplits(ctx, vaultID, expenseID, expenses)
+	if err != nil {
+		return nil, err
+	}
+	return r.attachTags(ctx, vaultID, expenseID, expenses)
+}
+
+func (r *ExpenseRepo) attachSplits(ctx context.Context, vaultID, expenseID string, expenses []models.Expense) ([]models.Expense, error) {
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, `
+		SELECT s.expense_id, COALESCE(e.split_method, ''), s.user_id, s.amount, s.share
+		FROM expense_splits s
+		JOIN expenses e ON e.id = s.expense_id
+		WHERE e.vault_id = $1 AND ($2 = '' OR e.id = $2)
+		ORDER BY s.rowid`, vaultID, expenseID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute find expense splits query for vault %s: %w", vaultID, err)
+	}
+	defer rows.Close()
+
+	splits := map[string][]models.ExpenseSplit{}
+	methods := map[string]models.SplitMethod{}
+	for rows.Next() {
+		var expenseID string
+		var method models.SplitMethod
+		var split models.ExpenseSplit
+		if err := rows.Scan(&expenseID, &method, &split.UserID, &split.Amount, &split.Share); err != nil {
+			return nil, err
+		}
+		splits[expenseID] = append(splits[expenseID], split)
+		methods[expenseID] = method
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range expenses {
+		expenses[i].Splits = splits[expenses[i].ID]
+		expenses[i].SplitMethod = methods[expenses[i].ID]
+		if expenses[i].Splits == nil {
+			expenses[i].Splits = []models.ExpenseSplit{}
+		}
+	}
+	return expenses, nil
+}
+
+func (r *ExpenseRepo) attachTags(ctx context.Context, vaultID, expenseID string, expenses []models.Expense) ([]models.Expense, error) {
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, `
+		SELECT et.expense_id, et.tag_id
+		FROM expense_tags et
+		JOIN tags t ON t.id = et.tag_id
+		WHERE t.vault_id = $1 AND ($2 = '' OR et.expense_id = $2)
+		ORDER BY t.name`, vaultID, expenseID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute find expense tags query for vault %s: %w", vaultID, err)
+	}
+	defer rows.Close()
+
+	tags := map[string][]string{}
+	for rows.Next() {
+		var expenseID, tagID string
+		if err := rows.Scan(&expenseID, &tagID); err != nil {
+			return nil, err
+		}
+		tags[expenseID] = append(tags[expenseID], tagID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range expenses {
+		expenses[i].TagIDs = tags[expenses[i].ID]
+		if expenses[i].TagIDs == nil {
+			expenses[i].TagIDs = []string{}
+		}
+	}
+	return expenses, nil
+}
+
+func insertExpenseTags(ctx context.Context, tx *database.Tx, expenseID string, tagIDs []string) error {
+	for _, tagID := range tagIDs {
+		_, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO expense_tags(expense_id, tag_id) VALUES ($1, $2)`, expenseID, tagID)
+		if err != nil {
+			return fmt.Errorf("failed to assign tag %s to expense %s: %w", tagID, expenseID, err)
+		}
+	}
+	return nil
+}
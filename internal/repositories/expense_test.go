@@ -0,0 +1,168 @@
+package repositories_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/repositories"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestExpenseRepo_CreateOne(t *testing.T) {
+	t.Parallel()
+
+	t.Run("creates new expense", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		paymentMethod := testutils.CreateTestPaymentMethod(t, db, user.ID, vault.ID)
+		expenseRepo := repositories.NewExpenseRepo(db)
+
+		expense := models.Expense{ // nolint: exhaustruct
+			Name:            "groceries",
+			Date:            "2025-02-01",
+			CategoryID:      category.ID,
+			Amount:          42.5,
+			PaymentMethodID: paymentMethod.ID,
+			VaultID:         vault.ID,
+			CreatedBy:       user.ID,
+		}
+
+		expenseID, err := expenseRepo.CreateOne(ctx, expense)
+		testutils.AssertNoError(t, err)
+
+		found, err := expenseRepo.FindOneByID(ctx, expenseID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, found.Name, expense.Name)
+		testutils.AssertEqual(t, found.Date, expense.Date)
+		testutils.AssertEqual(t, found.CategoryID, expense.CategoryID)
+		testutils.AssertEqual(t, found.Amount, expense.Amount)
+		testutils.AssertEqual(t, found.PaymentMethodID, expense.PaymentMethodID)
+		testutils.AssertEqual(t, found.VaultID, expense.VaultID)
+		testutils.AssertEqual(t, found.CreatedBy, expense.CreatedBy)
+		testutils.AssertValidDate(t, found.CreatedAt)
+	})
+}
+
+func TestExpenseRepo_FindAll(t *testing.T) {
+	t.Parallel()
+
+	t.Run("finds all expenses that are not deleted", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expenseRepo := repositories.NewExpenseRepo(db)
+
+		testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+		deleted := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		err := expenseRepo.DeleteOneByID(ctx, deleted.ID)
+		testutils.AssertNoError(t, err)
+
+		expenses, err := expenseRepo.FindAll(ctx, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(expenses), 1)
+	})
+
+	t.Run("returns empty array if no expenses are found", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		expenseRepo := repositories.NewExpenseRepo(testutils.OpenTestDB(t, ctx))
+
+		expenses, err := expenseRepo.FindAll(ctx, uuid.New().String())
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(expenses), 0)
+	})
+}
+
+func TestExpenseRepo_DeleteOneByID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("soft deletes expense", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+		expenseRepo := repositories.NewExpenseRepo(db)
+
+		err := expenseRepo.DeleteOneByID(ctx, expense.ID)
+		testutils.AssertNoError(t, err)
+
+		_, err = expenseRepo.FindOneByID(ctx, expense.ID)
+		if !errors.Is(err, repositories.ErrExpenseNotFound) {
+			t.Errorf("expected error %q, got %v", repositories.ErrExpenseNotFound, err)
+		}
+
+		deleted, err := expenseRepo.FindOneDeletedByID(ctx, expense.ID, time.Now().Add(-time.Hour))
+		testutils.AssertNoError(t, err)
+		testutils.AssertValidDate(t, deleted.DeletedAt)
+
+		deletedExpenses, err := expenseRepo.FindAllDeleted(ctx, vault.ID, time.Now().Add(-time.Hour))
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(deletedExpenses), 1)
+	})
+}
+
+func TestExpenseRepo_RestoreOneByID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("restores soft deleted expense", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+		expenseRepo := repositories.NewExpenseRepo(db)
+
+		err := expenseRepo.DeleteOneByID(ctx, expense.ID)
+		testutils.AssertNoError(t, err)
+
+		err = expenseRepo.RestoreOneByID(ctx, expense.ID)
+		testutils.AssertNoError(t, err)
+
+		_, err = expenseRepo.FindOneByID(ctx, expense.ID)
+		testutils.AssertNoError(t, err)
+	})
+}
+
+func TestExpenseRepo_PurgeDeleted(t *testing.T) {
+	t.Parallel()
+
+	t.Run("permanently removes expenses deleted before given time", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+		testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+		expenseRepo := repositories.NewExpenseRepo(db)
+
+		err := expenseRepo.DeleteOneByID(ctx, expense.ID)
+		testutils.AssertNoError(t, err)
+
+		purged, err := expenseRepo.PurgeDeleted(ctx, time.Now().Add(time.Hour))
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, purged, 1)
+
+		deletedExpenses, err := expenseRepo.FindAllDeleted(ctx, vault.ID, time.Time{})
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(deletedExpenses), 0)
+
+		expenses, err := expenseRepo.FindAll(ctx, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(expenses), 1)
+	})
+}
@@ -7,26 +7,27 @@ import (
 	"fmt"
 
 	"github.com/google/uuid"
+	"github.com/kkstas/tr-backend/internal/database"
 	"github.com/kkstas/tr-backend/internal/models"
 )
 
 var ErrExpenseCategoryNotFound = errors.New("expense category not found")
 
 type ExpenseCategoryRepo struct {
-	db *sql.DB
+	db *database.DB
 }
 
-func NewExpenseCategoryRepo(db *sql.DB) *ExpenseCategoryRepo {
+func NewExpenseCategoryRepo(db *database.DB) *ExpenseCategoryRepo {
 	return &ExpenseCategoryRepo{db: db}
 }
 
-func (r *ExpenseCategoryRepo) CreateOne(ctx context.Context, name string, status models.ExpenseCategoryStatus, priority int, vaultID, createdBy string) (categoryID string, err error) {
+func (r *ExpenseCategoryRepo) CreateOne(ctx context.Context, name string, status models.ExpenseCategoryStatus, priority int, parentID *string, vaultID, createdBy string) (categoryID string, err error) {
 	categoryID = uuid.New().String()
 
-	_, err = r.db.ExecContext(ctx, `
-		INSERT INTO expense_categories(id, name, status, priority, vault_id, created_by)
-		VALUES ($1, $2, $3, $4, $5, $6)`,
-		categoryID, name, status, priority, vaultID, createdBy)
+	_, err = r.db.Writer(ctx).ExecContext(ctx, `
+		INSERT INTO expense_categories(id, name, status, priority, parent_id, vault_id, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		categoryID, name, status, priority, parentID, vaultID, createdBy)
 	if err != nil {
 		return "", err
 	}
@@ -34,10 +35,11 @@ func (r *ExpenseCategoryRepo) CreateOne(ctx context.Context, name string, status
 }
 
 func (r *ExpenseCategoryRepo) FindAll(ctx context.Context, vaultID string) ([]models.ExpenseCategory, error) {
-	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, name, status, priority, vault_id, created_by, created_at
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, `
+		SELECT id, name, status, priority, parent_id, vault_id, created_by, created_at
 		FROM expense_categories
-		WHERE vault_id = $1`, vaultID,
+		WHERE vault_id = $1
+		ORDER BY priority, created_at`, vaultID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute find all categories query for vault %s: %w", vaultID, err)
@@ -48,7 +50,7 @@ func (r *ExpenseCategoryRepo) FindAll(ctx context.Context, vaultID string) ([]mo
 
 	var category = models.ExpenseCategory{} // nolint: exhaustruct
 	for rows.Next() {
-		err := rows.Scan(&category.ID, &category.Name, &category.Status, &category.Priority, &category.VaultID, &category.CreatedBy, &category.CreatedAt)
+		err := rows.Scan(&category.ID, &category.Name, &category.Status, &category.Priority, &category.ParentID, &category.VaultID, &category.CreatedBy, &category.CreatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -64,11 +66,11 @@ func (r *ExpenseCategoryRepo) FindAll(ctx context.Context, vaultID string) ([]mo
 func (r *ExpenseCategoryRepo) FindOneByID(ctx context.Context, categoryID string) (*models.ExpenseCategory, error) {
 	category := models.ExpenseCategory{} // nolint: exhaustruct
 
-	err := r.db.QueryRowContext(ctx, `
-		SELECT id, name, status, priority, vault_id, created_by, created_at
+	err := r.db.Reader(ctx).QueryRowContext(ctx, `
+		SELECT id, name, status, priority, parent_id, vault_id, created_by, created_at
 		FROM expense_categories
 		WHERE id = $1
-		`, categoryID).Scan(&category.ID, &category.Name, &category.Status, &category.Priority, &category.VaultID, &category.CreatedBy, &category.CreatedAt)
+		`, categoryID).Scan(&category.ID, &category.Name, &category.Status, &category.Priority, &category.ParentID, &category.VaultID, &category.CreatedBy, &category.CreatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrExpenseCategoryNotFound
@@ -81,7 +83,7 @@ func (r *ExpenseCategoryRepo) FindOneByID(ctx context.Context, categoryID string
 }
 
 func (r *ExpenseCategoryRepo) SetStatus(ctx context.Context, categoryID string, status models.ExpenseCategoryStatus) error {
-	_, err := r.db.ExecContext(ctx, `
+	_, err := r.db.Writer(ctx).ExecContext(ctx, `
 		UPDATE expense_categories
 		SET status = $1
 		WHERE id = $2`, status, categoryID,
@@ -93,7 +95,7 @@ func (r *ExpenseCategoryRepo) SetStatus(ctx context.Context, categoryID string,
 }
 
 func (r *ExpenseCategoryRepo) SetPriority(ctx context.Context, categoryID string, priority int) error {
-	_, err := r.db.ExecContext(ctx, `
+	_, err := r.db.Writer(ctx).ExecContext(ctx, `
 		UPDATE expense_categories
 		SET priority = $1
 		WHERE id = $2`, priority, categoryID,
@@ -103,3 +105,126 @@ func (r *ExpenseCategoryRepo) SetPriority(ctx context.Context, categoryID string
 	}
 	return nil
 }
+
+func (r *ExpenseCategoryRepo) UpdateOne(ctx context.Context, category *models.ExpenseCategory) error {
+	_, err := r.db.Writer(ctx).ExecContext(ctx, `
+		UPDATE expense_categories
+		SET name = $1, status = $2, parent_id = $3
+		WHERE id = $4`, category.Name, category.Status, category.ParentID, category.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update expense category %s: %w", category.ID, err)
+	}
+	return nil
+}
+
+func (r *ExpenseCategoryRepo) Reorder(ctx context.Context, vaultID string, categoryIDs []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback() // nolint: errcheck
+
+	for priority, categoryID := range categoryIDs {
+		res, err := tx.ExecContext(ctx, `
+			UPDATE expense_categories
+			SET priority = $1
+			WHERE id = $2 AND vault_id = $3`, priority, categoryID, vaultID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to set priority of expense category %s: %w", categoryID, err)
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return fmt.Errorf("failed to check updated rows: %w", err)
+		} else if n == 0 {
+			return ErrExpenseCategoryNotFound
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ExpenseCategoryRepo) Merge(ctx context.Context, sourceCategoryID, targetCategoryID string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback() // nolint: errcheck
+
+	_, err = tx.ExecContext(ctx, `UPDATE expenses SET category_id = $1 WHERE category_id = $2`, targetCategoryID, sourceCategoryID)
+	if err != nil {
+		return fmt.Errorf("failed to move expenses from category %s to %s: %w", sourceCategoryID, targetCategoryID, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE expense_categories
+		SET parent_id = (SELECT parent_id FROM expense_categories WHERE id = $1)
+		WHERE parent_id = $1`, sourceCategoryID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to move subcategories of merged expense category %s: %w", sourceCategoryID, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM expense_categories WHERE id = $1`, sourceCategoryID)
+	if err != nil {
+		return fmt.Errorf("failed to delete merged expense category %s: %w", sourceCategoryID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ExpenseCategoryRepo) CreateFromTemplate(ctx context.Context, vaultID, createdBy string, categories []models.CategoryTemplate) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback() // nolint: errcheck
+
+	if err := insertTemplateCategories(ctx, tx, vaultID, createdBy, nil, categories); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// insertTemplateCategories creates template categories in the vault. Categories whose names
+// are already taken in the vault are reused, so applying the same template twice is a no-op.
+func insertTemplateCategories(ctx context.Context, tx *database.Tx, vaultID, createdBy string, parentID *string, categories []models.CategoryTemplate) error {
+	for _, category := range categories {
+		var categoryID string
+		err := tx.QueryRowContext(ctx, `
+			SELECT id FROM expense_categories WHERE vault_id = $1 AND name = $2`, vaultID, category.Name,
+		).Scan(&categoryID)
+
+		if errors.Is(err, sql.ErrNoRows) {
+			categoryID = uuid.New().String()
+			_, err = tx.ExecContext(ctx, `
+				INSERT INTO expense_categories(id, name, status, priority, parent_id, vault_id, created_by)
+				VALUES ($1, $2, $3, (SELECT COALESCE(MAX(priority) + 1, 0) FROM expense_categories WHERE vault_id = $4), $5, $4, $6)`,
+				categoryID, category.Name, models.ExpenseCategoryStatusActive, vaultID, parentID, createdBy)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create template category %q in vault %s: %w", category.Name, vaultID, err)
+		}
+
+		if err := insertTemplateCategories(ctx, tx, vaultID, createdBy, &categoryID, category.Subcategories); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -21,10 +21,10 @@ func TestExpenseCategoryRepo_FindAll(t *testing.T) {
 		expenseCategoryRepo := repositories.NewExpenseCategoryRepo(db)
 		user := testutils.CreateTestUser(t, db)
 
-		vaultID, err := repositories.NewVaultRepo(db).CreateOne(ctx, user.ID, models.VaultRoleOwner, "vault name")
+		vaultID, err := repositories.NewVaultRepo(db).CreateOne(ctx, user.ID, models.VaultRoleOwner, "vault name", nil)
 		testutils.AssertNoError(t, err)
 
-		_, err = expenseCategoryRepo.CreateOne(ctx, "category name", models.ExpenseCategoryStatusActive, 0, vaultID, user.ID)
+		_, err = expenseCategoryRepo.CreateOne(ctx, "category name", models.ExpenseCategoryStatusActive, 0, nil, vaultID, user.ID)
 		testutils.AssertNoError(t, err)
 
 		foundCategories, err := expenseCategoryRepo.FindAll(ctx, vaultID)
@@ -54,7 +54,7 @@ func TestExpenseCategoryRepo_CreateOne(t *testing.T) {
 		expenseCategoryRepo := repositories.NewExpenseCategoryRepo(db)
 		user := testutils.CreateTestUser(t, db)
 
-		vaultID, err := repositories.NewVaultRepo(db).CreateOne(ctx, user.ID, models.VaultRoleOwner, "vault name")
+		vaultID, err := repositories.NewVaultRepo(db).CreateOne(ctx, user.ID, models.VaultRoleOwner, "vault name", nil)
 		testutils.AssertNoError(t, err)
 
 		name := "category name"
@@ -62,7 +62,7 @@ func TestExpenseCategoryRepo_CreateOne(t *testing.T) {
 		priority := 0
 		createdBy := user.ID
 
-		categoryID, err := expenseCategoryRepo.CreateOne(ctx, name, status, priority, vaultID, createdBy)
+		categoryID, err := expenseCategoryRepo.CreateOne(ctx, name, status, priority, nil, vaultID, createdBy)
 		testutils.AssertNoError(t, err)
 
 		category, err := expenseCategoryRepo.FindOneByID(ctx, categoryID)
@@ -86,7 +86,7 @@ func TestExpenseCategoryRepo_FindOneByID(t *testing.T) {
 		expenseCategoryRepo := repositories.NewExpenseCategoryRepo(db)
 		user := testutils.CreateTestUser(t, db)
 
-		vaultID, err := repositories.NewVaultRepo(db).CreateOne(ctx, user.ID, models.VaultRoleOwner, "vault name")
+		vaultID, err := repositories.NewVaultRepo(db).CreateOne(ctx, user.ID, models.VaultRoleOwner, "vault name", nil)
 		testutils.AssertNoError(t, err)
 
 		name := "category name"
@@ -94,7 +94,7 @@ func TestExpenseCategoryRepo_FindOneByID(t *testing.T) {
 		priority := 0
 		createdBy := user.ID
 
-		categoryID, err := expenseCategoryRepo.CreateOne(ctx, name, status, priority, vaultID, createdBy)
+		categoryID, err := expenseCategoryRepo.CreateOne(ctx, name, status, priority, nil, vaultID, createdBy)
 		testutils.AssertNoError(t, err)
 
 		foundCategory, err := expenseCategoryRepo.FindOneByID(ctx, categoryID)
@@ -133,13 +133,13 @@ func TestExpenseCategoryRepo_SetStatus(t *testing.T) {
 		expenseCategoryRepo := repositories.NewExpenseCategoryRepo(db)
 		user := testutils.CreateTestUser(t, db)
 
-		vaultID, err := repositories.NewVaultRepo(db).CreateOne(ctx, user.ID, models.VaultRoleOwner, "vault name")
+		vaultID, err := repositories.NewVaultRepo(db).CreateOne(ctx, user.ID, models.VaultRoleOwner, "vault name", nil)
 		testutils.AssertNoError(t, err)
 
 		name := "category name"
 		prevStatus := models.ExpenseCategoryStatusActive
 
-		categoryID, err := expenseCategoryRepo.CreateOne(ctx, name, prevStatus, 0, vaultID, user.ID)
+		categoryID, err := expenseCategoryRepo.CreateOne(ctx, name, prevStatus, 0, nil, vaultID, user.ID)
 		testutils.AssertNoError(t, err)
 
 		newStatus := models.ExpenseCategoryStatusInactive
@@ -162,12 +162,12 @@ func TestExpenseCategoryRepo_SetPriority(t *testing.T) {
 		expenseCategoryRepo := repositories.NewExpenseCategoryRepo(db)
 		user := testutils.CreateTestUser(t, db)
 
-		vaultID, err := repositories.NewVaultRepo(db).CreateOne(ctx, user.ID, models.VaultRoleOwner, "vault name")
+		vaultID, err := repositories.NewVaultRepo(db).CreateOne(ctx, user.ID, models.VaultRoleOwner, "vault name", nil)
 		testutils.AssertNoError(t, err)
 
 		name := "category name"
 
-		categoryID, err := expenseCategoryRepo.CreateOne(ctx, name, models.ExpenseCategoryStatusActive, 0, vaultID, user.ID)
+		categoryID, err := expenseCategoryRepo.CreateOne(ctx, name, models.ExpenseCategoryStatusActive, 0, nil, vaultID, user.ID)
 		testutils.AssertNoError(t, err)
 
 		newPriority := 1
@@ -179,3 +179,96 @@ func TestExpenseCategoryRepo_SetPriority(t *testing.T) {
 		testutils.AssertEqual(t, category.Priority, newPriority)
 	})
 }
+
+func TestExpenseCategoryRepo_UpdateOne(t *testing.T) {
+	t.Parallel()
+
+	t.Run("updates expense category name and status", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		expenseCategoryRepo := repositories.NewExpenseCategoryRepo(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+
+		category.Name = "renamed"
+		category.Status = models.ExpenseCategoryStatusInactive
+		err := expenseCategoryRepo.UpdateOne(ctx, category)
+		testutils.AssertNoError(t, err)
+
+		found, err := expenseCategoryRepo.FindOneByID(ctx, category.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, found.Name, category.Name)
+		testutils.AssertEqual(t, found.Status, category.Status)
+	})
+}
+
+func TestExpenseCategoryRepo_Reorder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rewrites priorities in given order", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		expenseCategoryRepo := repositories.NewExpenseCategoryRepo(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		first := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		second := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		third := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+
+		err := expenseCategoryRepo.Reorder(ctx, vault.ID, []string{third.ID, first.ID, second.ID})
+		testutils.AssertNoError(t, err)
+
+		categories, err := expenseCategoryRepo.FindAll(ctx, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(categories), 3)
+		testutils.AssertEqual(t, categories[0].ID, third.ID)
+		testutils.AssertEqual(t, categories[1].ID, first.ID)
+		testutils.AssertEqual(t, categories[2].ID, second.ID)
+	})
+
+	t.Run("does not change any priority if one of categories is not in the vault", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		expenseCategoryRepo := repositories.NewExpenseCategoryRepo(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+
+		err := expenseCategoryRepo.Reorder(ctx, vault.ID, []string{uuid.New().String(), category.ID})
+		if !errors.Is(err, repositories.ErrExpenseCategoryNotFound) {
+			t.Errorf("expected error %q, got %v", repositories.ErrExpenseCategoryNotFound, err)
+		}
+
+		found, err := expenseCategoryRepo.FindOneByID(ctx, category.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, found.Priority, 0)
+	})
+}
+
+func TestExpenseCategoryRepo_Merge(t *testing.T) {
+	t.Parallel()
+
+	t.Run("moves expenses to target category and deletes source category", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		expenseCategoryRepo := repositories.NewExpenseCategoryRepo(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		source := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		target := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, source.ID)
+
+		err := expenseCategoryRepo.Merge(ctx, source.ID, target.ID)
+		testutils.AssertNoError(t, err)
+
+		_, err = expenseCategoryRepo.FindOneByID(ctx, source.ID)
+		if !errors.Is(err, repositories.ErrExpenseCategoryNotFound) {
+			t.Errorf("expected error %q, got %v", repositories.ErrExpenseCategoryNotFound, err)
+		}
+
+		movedExpense, err := repositories.NewExpenseRepo(db).FindOneByID(ctx, expense.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, movedExpense.CategoryID, target.ID)
+	})
+}
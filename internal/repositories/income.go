@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/kkstas/tr-backend/internal/database"
+	"github.com/kkstas/tr-backend/internal/models"
+)
+
+var ErrIncomeNotFound = errors.New("income not found")
+
+type IncomeRepo struct {
+	db *database.DB
+}
+
+func NewIncomeRepo(db *database.DB) *IncomeRepo {
+	return &IncomeRepo{db: db}
+}
+
+func (r *IncomeRepo) CreateOne(ctx context.Context, income models.Income) (incomeID string, err error) {
+	incomeID = uuid.New().String()
+
+	_, err = r.db.Writer(ctx).ExecContext(ctx, `
+		INSERT INTO incomes(id, source, date, amount, account_id, vault_id, created_by)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7)`,
+		incomeID, income.Source, income.Date, income.Amount, income.AccountID, income.VaultID, income.CreatedBy)
+	if err != nil {
+		return "", fmt.Errorf("failed to insert income: %w", err)
+	}
+	return incomeID, nil
+}
+
+func (r *IncomeRepo) FindAll(ctx context.Context, vaultID string) ([]models.Income, error) {
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, `
+		SELECT id, source, date, amount, COALESCE(account_id, ''), vault_id, created_by, created_at
+		FROM incomes
+		WHERE vault_id = $1
+		ORDER BY date DESC, created_at DESC`, vaultID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute find all incomes query for vault %s: %w", vaultID, err)
+	}
+	defer rows.Close()
+
+	incomes := []models.Income{}
+
+	for rows.Next() {
+		var i models.Income
+		err := rows.Scan(&i.ID, &i.Source, &i.Date, &i.Amount, &i.AccountID, &i.VaultID, &i.CreatedBy, &i.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		incomes = append(incomes, i)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return incomes, nil
+}
+
+func (r *IncomeRepo) FindOneByID(ctx context.Context, incomeID string) (*models.Income, error) {
+	i := models.Income{} // nolint: exhaustruct
+
+	err := r.db.Reader(ctx).QueryRowContext(ctx, `
+		SELECT id, source, date, amount, COALESCE(account_id, ''), vault_id, created_by, created_at
+		FROM incomes
+		WHERE id = $1
+		`, incomeID).Scan(&i.ID, &i.Source, &i.Date, &i.Amount, &i.AccountID, &i.VaultID, &i.CreatedBy, &i.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrIncomeNotFound
+		}
+		return nil, err
+	}
+
+	return &i, nil
+}
+
+func (r *IncomeRepo) UpdateOne(ctx context.Context, income *models.Income) error {
+	_, err := r.db.Writer(ctx).ExecContext(ctx, `
+		UPDATE incomes
+		SET source = $1, date = $2, amount = $3, account_id = NULLIF($4, '')
+		WHERE id = $5`, income.Source, income.Date, income.Amount, income.AccountID, income.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update income %s: %w", income.ID, err)
+	}
+	return nil
+}
+
+func (r *IncomeRepo) DeleteOneByID(ctx context.Context, incomeID string) error {
+	_, err := r.db.Writer(ctx).ExecContext(ctx, `DELETE FROM incomes WHERE id = $1`, incomeID)
+	if err != nil {
+		return fmt.Errorf("failed to delete income %s: %w", incomeID, err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/kkstas/tr-backend/internal/database"
+	"github.com/kkstas/tr-backend/internal/models"
+)
+
+var ErrPaymentMethodNotFound = errors.New("payment method not found")
+var ErrPaymentMethodInUse = errors.New("payment method is used by expenses")
+
+type PaymentMethodRepo struct {
+	db *database.DB
+}
+
+func NewPaymentMethodRepo(db *database.DB) *PaymentMethodRepo {
+	return &PaymentMethodRepo{db: db}
+}
+
+func (r *PaymentMethodRepo) CreateOne(ctx context.Context, name, vaultID, createdBy string) (paymentMethodID string, err error) {
+	paymentMethodID = uuid.New().String()
+
+	_, err = r.db.Writer(ctx).ExecContext(ctx, `
+		INSERT INTO payment_methods(id, name, status, vault_id, created_by)
+		VALUES ($1, $2, $3, $4, $5)`,
+		paymentMethodID, name, models.PaymentMethodStatusActive, vaultID, createdBy)
+	if err != nil {
+		return "", fmt.Errorf("failed to insert payment method: %w", err)
+	}
+	return paymentMethodID, nil
+}
+
+func (r *PaymentMethodRepo) FindAll(ctx context.Context, vaultID string) ([]models.PaymentMethod, error) {
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, `
+		SELECT id, name, status, vault_id, created_by, created_at
+		FROM payment_methods
+		WHERE vault_id = $1
+		ORDER BY name`, vaultID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute find all payment methods query for vault %s: %w", vaultID, err)
+	}
+	defer rows.Close()
+
+	paymentMethods := []models.PaymentMethod{}
+
+	for rows.Next() {
+		var pm models.PaymentMethod
+		err := rows.Scan(&pm.ID, &pm.Name, &pm.Status, &pm.VaultID, &pm.CreatedBy, &pm.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		paymentMethods = append(paymentMethods, pm)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return paymentMethods, nil
+}
+
+func (r *PaymentMethodRepo) FindOneByID(ctx context.Context, paymentMethodID string) (*models.PaymentMethod, error) {
+	pm := models.PaymentMethod{} // nolint: exhaustruct
+
+	err := r.db.Reader(ctx).QueryRowContext(ctx, `
+		SELECT id, name, status, vault_id, created_by, created_at
+		FROM payment_methods
+		WHERE id = $1
+		`, paymentMethodID).Scan(&pm.ID, &pm.Name, &pm.Status, &pm.VaultID, &pm.CreatedBy, &pm.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrPaymentMethodNotFound
+		}
+		return nil, err
+	}
+
+	return &pm, nil
+}
+
+func (r *PaymentMethodRepo) UpdateOne(ctx context.Context, paymentMethod *models.PaymentMethod) error {
+	_, err := r.db.Writer(ctx).ExecContext(ctx, `
+		UPDATE payment_methods
+		SET name = $1, status = $2
+		WHERE id = $3`, paymentMethod.Name, paymentMethod.Status, paymentMethod.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update payment method %s: %w", paymentMethod.ID, err)
+	}
+	return nil
+}
+
+// DeleteOneByID deletes payment method only if no expense, including deleted ones
+// still kept in trash, refers to it. Otherwise ErrPaymentMethodInUse is returned.
+func (r *PaymentMethodRepo) DeleteOneByID(ctx context.Context, paymentMethodID string) error {
+	res, err := r.db.Writer(ctx).ExecContext(ctx, `
+		DELETE FROM payment_methods
+		WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM expenses WHERE payment_method_id = $1)`, paymentMethodID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete payment method %s: %w", paymentMethodID, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to check deleted rows: %w", err)
+	} else if n == 0 {
+		return ErrPaymentMethodInUse
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package repositories_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/repositories"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestPaymentMethodRepo_CreateOne(t *testing.T) {
+	t.Parallel()
+
+	t.Run("creates active payment method", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		paymentMethodRepo := repositories.NewPaymentMethodRepo(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+
+		paymentMethodID, err := paymentMethodRepo.CreateOne(ctx, "cash", vault.ID, user.ID)
+		testutils.AssertNoError(t, err)
+
+		found, err := paymentMethodRepo.FindOneByID(ctx, paymentMethodID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, found.Name, "cash")
+		testutils.AssertEqual(t, found.Status, models.PaymentMethodStatusActive)
+		testutils.AssertEqual(t, found.VaultID, vault.ID)
+		testutils.AssertEqual(t, found.CreatedBy, user.ID)
+		testutils.AssertValidDate(t, found.CreatedAt)
+	})
+}
+
+func TestPaymentMethodRepo_DeleteOneByID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("deletes unused payment method", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		paymentMethodRepo := repositories.NewPaymentMethodRepo(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		paymentMethod := testutils.CreateTestPaymentMethod(t, db, user.ID, vault.ID)
+
+		err := paymentMethodRepo.DeleteOneByID(ctx, paymentMethod.ID)
+		testutils.AssertNoError(t, err)
+
+		_, err = paymentMethodRepo.FindOneByID(ctx, paymentMethod.ID)
+		if !errors.Is(err, repositories.ErrPaymentMethodNotFound) {
+			t.Errorf("expected error %q, got %v", repositories.ErrPaymentMethodNotFound, err)
+		}
+	})
+
+	t.Run("returns error if payment method is used by deleted expense", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		paymentMethodRepo := repositories.NewPaymentMethodRepo(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+		err := repositories.NewExpenseRepo(db).DeleteOneByID(ctx, expense.ID)
+		testutils.AssertNoError(t, err)
+
+		err = paymentMethodRepo.DeleteOneByID(ctx, expense.PaymentMethodID)
+		if !errors.Is(err, repositories.ErrPaymentMethodInUse) {
+			t.Errorf("expected error %q, got %v", repositories.ErrPaymentMethodInUse, err)
+		}
+	})
+}
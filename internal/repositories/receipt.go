@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kkstas/tr-backend/internal/database"
+	"github.com/kkstas/tr-backend/internal/models"
+)
+
+var ErrReceiptNotFound = errors.New("receipt not found")
+
+type ReceiptRepo struct {
+	db *database.DB
+}
+
+func NewReceiptRepo(db *database.DB) *ReceiptRepo {
+	return &ReceiptRepo{db: db}
+}
+
+func (r *ReceiptRepo) CreateOne(ctx context.Context, receipt models.Receipt) error {
+	_, err := r.db.Writer(ctx).ExecContext(ctx, `
+		INSERT INTO receipts(id, expense_id, file_name, content_type, size, checksum, storage_key, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		receipt.ID, receipt.ExpenseID, receipt.FileName, receipt.ContentType, receipt.Size, receipt.Checksum, receipt.StorageKey, receipt.CreatedBy)
+	if err != nil {
+		return fmt.Errorf("failed to insert receipt: %w", err)
+	}
+	return nil
+}
+
+func (r *ReceiptRepo) FindAll(ctx context.Context, expenseID string) ([]models.Receipt, error) {
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, `
+		SELECT id, expense_id, file_name, content_type, size, checksum, storage_key, created_by, created_at
+		FROM receipts
+		WHERE expense_id = $1
+		ORDER BY created_at, id`, expenseID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute find all receipts query for expense %s: %w", expenseID, err)
+	}
+	defer rows.Close()
+
+	receipts := []models.Receipt{}
+
+	for rows.Next() {
+		var rc models.Receipt
+		err := rows.Scan(&rc.ID, &rc.ExpenseID, &rc.FileName, &rc.ContentType, &rc.Size, &rc.Checksum, &rc.StorageKey, &rc.CreatedBy, &rc.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		receipts = append(receipts, rc)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return receipts, nil
+}
+
+func (r *ReceiptRepo) FindOneByID(ctx context.Context, receiptID string) (*models.Receipt, error) {
+	rc := models.Receipt{} // nolint: exhaustruct
+
+	err := r.db.Reader(ctx).QueryRowContext(ctx, `
+		SELECT id, expense_id, file_name, content_type, size, checksum, storage_key, created_by, created_at
+		FROM receipts
+		WHERE id = $1
+		`, receiptID).Scan(&rc.ID, &rc.ExpenseID, &rc.FileName, &rc.ContentType, &rc.Size, &rc.Checksum, &rc.StorageKey, &rc.CreatedBy, &rc.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrReceiptNotFound
+		}
+		return nil, err
+	}
+
+	return &rc, nil
+}
+
+func (r *ReceiptRepo) DeleteOneByID(ctx context.Context, receiptID string) error {
+	_, err := r.db.Writer(ctx).ExecContext(ctx, `DELETE FROM receipts WHERE id = $1`, receiptID)
+	if err != nil {
+		return fmt.Errorf("failed to delete receipt %s: %w", receiptID, err)
+	}
+	return nil
+}
+
+// FindStorageKeysOfDeleted returns storage keys of receipts of expenses, or of expenses in
+// vaults, that were deleted before deletedBefore.
+func (r *ReceiptRepo) FindStorageKeysOfDeleted(ctx context.Context, deletedBefore time.Time) ([]string, error) {
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, `
+		SELECT r.storage_key
+		FROM receipts r
+		JOIN expenses e ON e.id = r.expense_id
+		JOIN vaults v ON v.id = e.vault_id
+		WHERE (e.deleted_at IS NOT NULL AND e.deleted_at < $1) OR (v.deleted_at IS NOT NULL AND v.deleted_at < $1)`,
+		formatTime(deletedBefore),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find storage keys of deleted receipts: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kkstas/tr-backend/internal/database"
+	"github.com/kkstas/tr-backend/internal/models"
+)
+
+type ReportRepo struct {
+	db *database.DB
+}
+
+func NewReportRepo(db *database.DB) *ReportRepo {
+	return &ReportRepo{db: db}
+}
+
+// SumExpensesByCategory returns totals of non-deleted expenses keyed by category ID.
+// Empty from or to leaves that end of the date range open.
+func (r *ReportRepo) SumExpensesByCategory(ctx context.Context, vaultID, from, to string) (map[string]float64, error) {
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, `
+		SELECT category_id, SUM(amount)
+		FROM expenses
+		WHERE vault_id = $1 AND deleted_at IS NULL
+			AND ($2 = '' OR date >= $2)
+			AND ($3 = '' OR date <= $3)
+		GROUP BY category_id`, vaultID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum expenses by category for vault %s: %w", vaultID, err)
+	}
+	defer rows.Close()
+
+	totals := map[string]float64{}
+	for rows.Next() {
+		var categoryID string
+		var total float64
+		if err := rows.Scan(&categoryID, &total); err != nil {
+			return nil, err
+		}
+		totals[categoryID] = total
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
+// SumExpensesByPaymentMethod returns totals of non-deleted expenses keyed by payment method ID.
+// Empty from or to leaves that end of the date range open.
+func (r *ReportRepo) SumExpensesByPaymentMethod(ctx context.Context, vaultID, from, to string) (map[string]float64, error) {
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, `
+		SELECT payment_method_id, SUM(amount)
+		FROM expenses
+		WHERE vault_id = $1 AND deleted_at IS NULL AND payment_method_id IS NOT NULL
+			AND ($2 = '' OR date >= $2)
+			AND ($3 = '' OR date <= $3)
+		GROUP BY payment_method_id`, vaultID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum expenses by payment method for vault %s: %w", vaultID, err)
+	}
+	defer rows.Close()
+
+	totals := map[string]float64{}
+	for rows.Next() {
+		var paymentMethodID string
+		var total float64
+		if err := rows.Scan(&paymentMethodID, &total); err != nil {
+			return nil, err
+		}
+		totals[paymentMethodID] = total
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
+// SumCashflowByMonth returns incomes and non-deleted expenses summed per month (YYYY-MM), oldest first.
+// Months without any income or expense are omitted. Empty from or to leaves that end of the date range open.
+func (r *ReportRepo) SumCashflowByMonth(ctx context.Context, vaultID, from, to string) ([]models.MonthlyCashflow, error) {
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, `
+		SELECT substr(date, 1, 7) AS month, SUM(income), SUM(expense) FROM (
+			SELECT date, amount AS income, 0 AS expense
+			FROM incomes
+			WHERE vault_id = $1
+			UNION ALL
+			SELECT date, 0, amount
+			FROM expenses
+			WHERE vault_id = $1 AND deleted_at IS NULL
+		)
+		WHERE ($2 = '' OR date >= $2)
+			AND ($3 = '' OR date <= $3)
+		GROUP BY month
+		ORDER BY month`, vaultID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum cashflow by month for vault %s: %w", vaultID, err)
+	}
+	defer rows.Close()
+
+	cashflow := []models.MonthlyCashflow{}
+	for rows.Next() {
+		var c models.MonthlyCashflow
+		if err := rows.Scan(&c.Month, &c.Income, &c.Expenses); err != nil {
+			return nil, err
+		}
+		c.Net = c.Income - c.Expenses
+		cashflow = append(cashflow, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return cashflow, nil
+}
+
+// SumExpensesByTag returns count and total of non-deleted expenses keyed by tag ID. Expense with
+// several tags counts towards each of them. Empty from or to leaves that end of the date range open.
+func (r *ReportRepo) SumExpensesByTag(ctx context.Context, vaultID, from, to string) (map[string]models.TagTotal, error) {
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, `
+		SELECT et.tag_id, COUNT(*), SUM(e.amount)
+		FROM expense_tags et
+		JOIN expenses e ON e.id = et.expense_id
+		WHERE e.vault_id = $1 AND e.deleted_at IS NULL
+			AND ($2 = '' OR e.date >= $2)
+			AND ($3 = '' OR e.date <= $3)
+		GROUP BY et.tag_id`, vaultID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum expenses by tag for vault %s: %w", vaultID, err)
+	}
+	defer rows.Close()
+
+	totals := map[string]models.TagTotal{}
+	for rows.Next() {
+		var t models.TagTotal
+		if err := rows.Scan(&t.TagID, &t.Count, &t.Total); err != nil {
+			return nil, err
+		}
+		totals[t.TagID] = t
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
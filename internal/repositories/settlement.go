@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/kkstas/tr-backend/internal/database"
+	"github.com/kkstas/tr-backend/internal/models"
+)
+
+var ErrSettlementNotFound = errors.New("settlement not found")
+
+type SettlementRepo struct {
+	db *database.DB
+}
+
+func NewSettlementRepo(db *database.DB) *SettlementRepo {
+	return &SettlementRepo{db: db}
+}
+
+func (r *SettlementRepo) CreateOne(ctx context.Context, settlement models.Settlement) (settlementID string, err error) {
+	settlementID = uuid.New().String()
+
+	_, err = r.db.Writer(ctx).ExecContext(ctx, `
+		INSERT INTO settlements(id, from_user_id, to_user_id, amount, date, vault_id, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		settlementID, settlement.FromUserID, settlement.ToUserID, settlement.Amount, settlement.Date, settlement.VaultID, settlement.CreatedBy)
+	if err != nil {
+		return "", fmt.Errorf("failed to insert settlement: %w", err)
+	}
+	return settlementID, nil
+}
+
+func (r *SettlementRepo) FindAll(ctx context.Context, vaultID string) ([]models.Settlement, error) {
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, `
+		SELECT id, from_user_id, to_user_id, amount, date, vault_id, created_by, created_at
+		FROM settlements
+		WHERE vault_id = $1
+		ORDER BY date DESC, created_at DESC`, vaultID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute find all settlements query for vault %s: %w", vaultID, err)
+	}
+	defer rows.Close()
+
+	settlements := []models.Settlement{}
+
+	for rows.Next() {
+		var s models.Settlement
+		err := rows.Scan(&s.ID, &s.FromUserID, &s.ToUserID, &s.Amount, &s.Date, &s.VaultID, &s.CreatedBy, &s.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		settlements = append(settlements, s)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return settlements, nil
+}
+
+func (r *SettlementRepo) FindOneByID(ctx context.Context, settlementID string) (*models.Settlement, error) {
+	s := models.Settlement{} // nolint: exhaustruct
+
+	err := r.db.Reader(ctx).QueryRowContext(ctx, `
+		SELECT id, from_user_id, to_user_id, amount, date, vault_id, created_by, created_at
+		FROM settlements
+		WHERE id = $1
+		`, settlementID).Scan(&s.ID, &s.FromUserID, &s.ToUserID, &s.Amount, &s.Date, &s.VaultID, &s.CreatedBy, &s.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSettlementNotFound
+		}
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+func (r *SettlementRepo) DeleteOneByID(ctx context.Context, settlementID string) error {
+	_, err := r.db.Writer(ctx).ExecContext(ctx, `DELETE FROM settlements WHERE id = $1`, settlementID)
+	if err != nil {
+		return fmt.Errorf("failed to delete settlement %s: %w", settlementID, err)
+	}
+	return nil
+}
+
+// SumBalances returns balances of users keyed by user ID, computed from splits of non-deleted
+// expenses and recorded settlements. Payer of a split expense is owed every split amount,
+// and every participant owes their own split amount.
+func (r *SettlementRepo) SumBalances(ctx context.Context, vaultID string) (map[string]float64, error) {
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, `
+		SELECT user_id, SUM(amount) FROM (
+			SELECT COALESCE(e.paid_by, e.created_by) AS user_id, s.amount AS amount
+			FROM expense_splits s
+			JOIN expenses e ON e.id = s.expense_id
+			WHERE e.vault_id = $1 AND e.deleted_at IS NULL
+			UNION ALL
+			SELECT s.user_id, -s.amount
+			FROM expense_splits s
+			JOIN expenses e ON e.id = s.expense_id
+			WHERE e.vault_id = $1 AND e.deleted_at IS NULL
+			UNION ALL
+			SELECT from_user_id, amount FROM settlements WHERE vault_id = $1
+			UNION ALL
+			SELECT to_user_id, -amount FROM settlements WHERE vault_id = $1
+		)
+		GROUP BY user_id`, vaultID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum balances for vault %s: %w", vaultID, err)
+	}
+	defer rows.Close()
+
+	balances := map[string]float64{}
+	for rows.Next() {
+		var userID string
+		var balance float64
+		if err := rows.Scan(&userID, &balance); err != nil {
+			return nil, err
+		}
+		balances[userID] = balance
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return balances, nil
+}
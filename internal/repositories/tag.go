@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/kkstas/tr-backend/internal/database"
+	"github.com/kkstas/tr-backend/internal/models"
+)
+
+var ErrTagNotFound = errors.New("tag not found")
+
+type TagRepo struct {
+	db *database.DB
+}
+
+func NewTagRepo(db *database.DB) *TagRepo {
+	return &TagRepo{db: db}
+}
+
+func (r *TagRepo) CreateOne(ctx context.Context, name, vaultID, createdBy string) (tagID string, err error) {
+	tagID = uuid.New().String()
+
+	_, err = r.db.Writer(ctx).ExecContext(ctx, `
+		INSERT INTO tags(id, name, vault_id, created_by)
+		VALUES ($1, $2, $3, $4)`,
+		tagID, name, vaultID, createdBy)
+	if err != nil {
+		return "", fmt.Errorf("failed to insert tag: %w", err)
+	}
+	return tagID, nil
+}
+
+func (r *TagRepo) FindAll(ctx context.Context, vaultID string) ([]models.Tag, error) {
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, `
+		SELECT id, name, vault_id, created_by, created_at
+		FROM tags
+		WHERE vault_id = $1
+		ORDER BY name`, vaultID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute find all tags query for vault %s: %w", vaultID, err)
+	}
+	defer rows.Close()
+
+	tags := []models.Tag{}
+
+	for rows.Next() {
+		var t models.Tag
+		err := rows.Scan(&t.ID, &t.Name, &t.VaultID, &t.CreatedBy, &t.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (r *TagRepo) FindOneByID(ctx context.Context, tagID string) (*models.Tag, error) {
+	t := models.Tag{} // nolint: exhaustruct
+
+	err := r.db.Reader(ctx).QueryRowContext(ctx, `
+		SELECT id, name, vault_id, created_by, created_at
+		FROM tags
+		WHERE id = $1
+		`, tagID).Scan(&t.ID, &t.Name, &t.VaultID, &t.CreatedBy, &t.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTagNotFound
+		}
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+func (r *TagRepo) UpdateOne(ctx context.Context, tag *models.Tag) error {
+	_, err := r.db.Writer(ctx).ExecContext(ctx, `UPDATE tags SET name = $1 WHERE id = $2`, tag.Name, tag.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update tag %s: %w", tag.ID, err)
+	}
+	return nil
+}
+
+// DeleteOneByID deletes tag and removes it from all expenses it was assigned to.
+func (r *TagRepo) DeleteOneByID(ctx context.Context, tagID string) error {
+	_, err := r.db.Writer(ctx).ExecContext(ctx, `DELETE FROM tags WHERE id = $1`, tagID)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag %s: %w", tagID, err)
+	}
+	return nil
+}
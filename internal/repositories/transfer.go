@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/kkstas/tr-backend/internal/database"
+	"github.com/kkstas/tr-backend/internal/models"
+)
+
+var ErrTransferNotFound = errors.New("transfer not found")
+
+type TransferRepo struct {
+	db *database.DB
+}
+
+func NewTransferRepo(db *database.DB) *TransferRepo {
+	return &TransferRepo{db: db}
+}
+
+func (r *TransferRepo) CreateOne(ctx context.Context, transfer models.Transfer) (transferID string, err error) {
+	transferID = uuid.New().String()
+
+	_, err = r.db.Writer(ctx).ExecContext(ctx, `
+		INSERT INTO transfers(id, from_account_id, to_account_id, amount, date, description, vault_id, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		transferID, transfer.FromAccountID, transfer.ToAccountID, transfer.Amount, transfer.Date, transfer.Description, transfer.VaultID, transfer.CreatedBy)
+	if err != nil {
+		return "", fmt.Errorf("failed to insert transfer: %w", err)
+	}
+	return transferID, nil
+}
+
+func (r *TransferRepo) FindAll(ctx context.Context, vaultID string) ([]models.Transfer, error) {
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, `
+		SELECT id, from_account_id, to_account_id, amount, date, description, vault_id, created_by, created_at
+		FROM transfers
+		WHERE vault_id = $1
+		ORDER BY date DESC, created_at DESC`, vaultID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute find all transfers query for vault %s: %w", vaultID, err)
+	}
+	defer rows.Close()
+
+	transfers := []models.Transfer{}
+
+	for rows.Next() {
+		var t models.Transfer
+		err := rows.Scan(&t.ID, &t.FromAccountID, &t.ToAccountID, &t.Amount, &t.Date, &t.Description, &t.VaultID, &t.CreatedBy, &t.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, t)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return transfers, nil
+}
+
+func (r *TransferRepo) FindOneByID(ctx context.Context, transferID string) (*models.Transfer, error) {
+	t := models.Transfer{} // nolint: exhaustruct
+
+	err := r.db.Reader(ctx).QueryRowContext(ctx, `
+		SELECT id, from_account_id, to_account_id, amount, date, description, vault_id, created_by, created_at
+		FROM transfers
+		WHERE id = $1
+		`, transferID).Scan(&t.ID, &t.FromAccountID, &t.ToAccountID, &t.Amount, &t.Date, &t.Description, &t.VaultID, &t.CreatedBy, &t.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTransferNotFound
+		}
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+func (r *TransferRepo) DeleteOneByID(ctx context.Context, transferID string) error {
+	_, err := r.db.Writer(ctx).ExecContext(ctx, `DELETE FROM transfers WHERE id = $1`, transferID)
+	if err != nil {
+		return fmt.Errorf("failed to delete transfer %s: %w", transferID, err)
+	}
+	return nil
+}
@@ -8,21 +8,22 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/kkstas/tr-backend/internal/database"
 	"github.com/kkstas/tr-backend/internal/models"
 )
 
 var ErrUserNotFound = errors.New("user not found")
 
 type UserRepo struct {
-	db *sql.DB
+	db *database.DB
 }
 
-func NewUserRepo(db *sql.DB) *UserRepo {
+func NewUserRepo(db *database.DB) *UserRepo {
 	return &UserRepo{db: db}
 }
 
 func (r *UserRepo) CreateOne(ctx context.Context, firstName, lastName, email, passwordHash string) error {
-	_, err := r.db.ExecContext(ctx, `
+	_, err := r.db.Writer(ctx).ExecContext(ctx, `
 		INSERT INTO users(id, first_name, last_name, email, password_hash)
 		VALUES ($1, $2, $3, $4, $5)`,
 		uuid.New().String(), firstName, lastName, email, passwordHash)
@@ -33,7 +34,7 @@ func (r *UserRepo) CreateOne(ctx context.Context, firstName, lastName, email, pa
 }
 
 func (r *UserRepo) FindPasswordHashAndUserIDForEmail(ctx context.Context, email string) (passwordHash, userID string, err error) {
-	err = r.db.QueryRowContext(ctx, `SELECT u.id, u.password_hash FROM users u WHERE u.email = $1`, email).Scan(&userID, &passwordHash)
+	err = r.db.Reader(ctx).QueryRowContext(ctx, `SELECT u.id, u.password_hash FROM users u WHERE u.email = $1`, email).Scan(&userID, &passwordHash)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return "", "", ErrUserNotFound
@@ -44,7 +45,7 @@ func (r *UserRepo) FindPasswordHashAndUserIDForEmail(ctx context.Context, email
 }
 
 func (r *UserRepo) FindAll(ctx context.Context) ([]models.User, error) {
-	rows, err := r.db.QueryContext(ctx, `
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, `
 		SELECT id, first_name, last_name, email, created_at
 		FROM users
 	`)
@@ -73,8 +74,10 @@ func (r *UserRepo) FindOneByID(ctx context.Context, id string) (*models.User, er
 	var user models.User
 	var activeVault sql.NullString
 
-	err := r.db.QueryRowContext(ctx, `
-			SELECT id, first_name, last_name, email, active_vault, created_at
+	err := r.db.Reader(ctx).QueryRowContext(ctx, `
+			SELECT id, first_name, last_name, email, (
+				SELECT v.id FROM vaults v WHERE v.id = users.active_vault AND v.deleted_at IS NULL
+			), created_at
 			FROM users
 			WHERE users.id = $1
 		`, id).
@@ -97,8 +100,10 @@ func (r *UserRepo) FindOneByEmail(ctx context.Context, email string) (*models.Us
 	var user models.User
 	var activeVault sql.NullString
 
-	err := r.db.QueryRowContext(ctx, `
-			SELECT id, first_name, last_name, email, active_vault, created_at
+	err := r.db.Reader(ctx).QueryRowContext(ctx, `
+			SELECT id, first_name, last_name, email, (
+				SELECT v.id FROM vaults v WHERE v.id = users.active_vault AND v.deleted_at IS NULL
+			), created_at
 			FROM users
 			WHERE users.email = $1
 		`, email).
@@ -118,7 +123,7 @@ func (r *UserRepo) FindOneByEmail(ctx context.Context, email string) (*models.Us
 }
 
 func (r *UserRepo) AssignActiveVault(ctx context.Context, userID, vaultID string) error {
-	_, err := r.db.ExecContext(ctx, `
+	_, err := r.db.Writer(ctx).ExecContext(ctx, `
 		UPDATE users
 		SET active_vault = $1
 		WHERE id = $2
@@ -253,7 +253,7 @@ func TestUserRepo_AssignActiveVault(t *testing.T) {
 		testutils.AssertNoError(t, err)
 		testutils.AssertEqual(t, user.ActiveVault, "")
 
-		vaultID, err := vaultRepo.CreateOne(ctx, user.ID, models.VaultRoleOwner, "vault name")
+		vaultID, err := vaultRepo.CreateOne(ctx, user.ID, models.VaultRoleOwner, "vault name", nil)
 		testutils.AssertNoError(t, err)
 
 		err = userRepo.AssignActiveVault(ctx, user.ID, vaultID)
@@ -5,24 +5,26 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/kkstas/tr-backend/internal/database"
 	"github.com/kkstas/tr-backend/internal/models"
 )
 
 var ErrVaultNotFound = errors.New("vault not found")
 
 type VaultRepo struct {
-	db *sql.DB
+	db *database.DB
 }
 
-func NewVaultRepo(db *sql.DB) *VaultRepo {
+func NewVaultRepo(db *database.DB) *VaultRepo {
 	return &VaultRepo{db: db}
 }
 
-func (r *VaultRepo) CreateOne(ctx context.Context, userID string, userRole models.VaultRole, vaultName string) (vaultID string, err error) {
-	tx, err := r.db.BeginTx(ctx, nil)
+func (r *VaultRepo) CreateOne(ctx context.Context, userID string, userRole models.VaultRole, vaultName string, categories []models.CategoryTemplate) (vaultID string, err error) {
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return "", fmt.Errorf("failed to create vault: %w", err)
 	}
@@ -41,6 +43,10 @@ func (r *VaultRepo) CreateOne(ctx context.Context, userID string, userRole model
 		return "", fmt.Errorf("failed to insert new record in user_vaults junction table: %w", err)
 	}
 
+	if err := insertTemplateCategories(ctx, tx, vaultID, userID, nil, categories); err != nil {
+		return "", err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return "", fmt.Errorf("failed to commit transaction: %w", err)
 	}
@@ -49,11 +55,10 @@ func (r *VaultRepo) CreateOne(ctx context.Context, userID string, userRole model
 }
 
 func (r *VaultRepo) FindAll(ctx context.Context, userID string) ([]models.UserVaultWithRole, error) {
-	rows, err := r.db.QueryContext(ctx, `
-		SELECT vaults.id, vaults.name, user_vaults.role FROM users
-		JOIN user_vaults ON users.id = user_vaults.user_id
-		JOIN vaults ON vaults.id = user_vaults.vault_id
-		WHERE users.id = $1
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, `
+		SELECT v.id, v.name, v.description, v.icon, v.color, v.base_currency, COALESCE(v.default_payment_method_id, ''), uv.role FROM vaults v
+		JOIN user_vaults uv ON uv.vault_id = v.id
+		WHERE uv.user_id = $1 AND v.deleted_at IS NULL
 	`, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query vaults: %w", err)
@@ -65,7 +70,7 @@ func (r *VaultRepo) FindAll(ctx context.Context, userID string) ([]models.UserVa
 
 	for rows.Next() {
 		var v models.UserVaultWithRole
-		if err := rows.Scan(&v.ID, &v.Name, &v.UserRole); err != nil {
+		if err := rows.Scan(&v.ID, &v.Name, &v.Description, &v.Icon, &v.Color, &v.BaseCurrency, &v.DefaultPaymentMethodID, &v.UserRole); err != nil {
 			return nil, err
 		}
 		vaults = append(vaults, v)
@@ -81,11 +86,11 @@ func (r *VaultRepo) FindAll(ctx context.Context, userID string) ([]models.UserVa
 func (r *VaultRepo) FindOneByID(ctx context.Context, userID, vaultID string) (*models.UserVaultWithRole, error) {
 	v := models.UserVaultWithRole{} // nolint: exhaustruct
 
-	err := r.db.QueryRowContext(ctx, `
-		SELECT v.id, v.name, uv.role FROM vaults v
+	err := r.db.Reader(ctx).QueryRowContext(ctx, `
+		SELECT v.id, v.name, v.description, v.icon, v.color, v.base_currency, COALESCE(v.default_payment_method_id, ''), uv.role FROM vaults v
 		JOIN user_vaults uv ON uv.vault_id = v.id
-		WHERE v.id = $1 AND uv.user_id = $2
-		`, vaultID, userID).Scan(&v.ID, &v.Name, &v.UserRole)
+		WHERE v.id = $1 AND uv.user_id = $2 AND v.deleted_at IS NULL
+		`, vaultID, userID).Scan(&v.ID, &v.Name, &v.Description, &v.Icon, &v.Color, &v.BaseCurrency, &v.DefaultPaymentMethodID, &v.UserRole)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrVaultNotFound
@@ -100,11 +105,11 @@ func (r *VaultRepo) FindOneByID(ctx context.Context, userID, vaultID string) (*m
 func (r *VaultRepo) FindOneByName(ctx context.Context, userID, vaultName string) (*models.UserVaultWithRole, error) {
 	v := models.UserVaultWithRole{} // nolint: exhaustruct
 
-	err := r.db.QueryRowContext(ctx, `
-		SELECT v.id, v.name, uv.role FROM vaults v
+	err := r.db.Reader(ctx).QueryRowContext(ctx, `
+		SELECT v.id, v.name, v.description, v.icon, v.color, v.base_currency, COALESCE(v.default_payment_method_id, ''), uv.role FROM vaults v
 		JOIN user_vaults uv ON uv.vault_id = v.id
-		WHERE v.name = $1 AND uv.user_id = $2
-		`, vaultName, userID).Scan(&v.ID, &v.Name, &v.UserRole)
+		WHERE v.name = $1 AND uv.user_id = $2 AND v.deleted_at IS NULL
+		`, vaultName, userID).Scan(&v.ID, &v.Name, &v.Description, &v.Icon, &v.Color, &v.BaseCurrency, &v.DefaultPaymentMethodID, &v.UserRole)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrVaultNotFound
@@ -116,16 +121,184 @@ func (r *VaultRepo) FindOneByName(ctx context.Context, userID, vaultName string)
 	return &v, nil
 }
 
+// NameTakenByMember reports whether any member of the vault has another vault with the
+// given name, so that renaming or restoring it keeps vault names unique per user.
+func (r *VaultRepo) NameTakenByMember(ctx context.Context, vaultID, vaultName string) (bool, error) {
+	var taken bool
+	err := r.db.Reader(ctx).QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM vaults v
+			JOIN user_vaults uv ON uv.vault_id = v.id
+			WHERE v.name = $1 AND v.id != $2 AND v.deleted_at IS NULL
+				AND uv.user_id IN (SELECT user_id FROM user_vaults WHERE vault_id = $2)
+		)
+		`, vaultName, vaultID).Scan(&taken)
+	if err != nil {
+		return false, fmt.Errorf("failed to check if name %q is taken by members of vault %s: %w", vaultName, vaultID, err)
+	}
+	return taken, nil
+}
+
+func (r *VaultRepo) UpdateOne(ctx context.Context, vault *models.UserVaultWithRole) error {
+	_, err := r.db.Writer(ctx).ExecContext(ctx, `
+		UPDATE vaults
+		SET name = $1, description = $2, icon = $3, color = $4, base_currency = $5, default_payment_method_id = NULLIF($6, '')
+		WHERE id = $7 AND deleted_at IS NULL`,
+		vault.Name, vault.Description, vault.Icon, vault.Color, vault.BaseCurrency, vault.DefaultPaymentMethodID, vault.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update vault %s: %w", vault.ID, err)
+	}
+	return nil
+}
+
+// DeleteOneByID marks the vault as deleted. Members keep it as their active vault, which
+// reads as empty until the vault is restored, so a restore gives it back to them.
 func (r *VaultRepo) DeleteOneByID(ctx context.Context, vaultID string) error {
-	_, err := r.db.ExecContext(ctx, `DELETE FROM vaults WHERE vaults.id = $1`, vaultID)
-	return err
+	_, err := r.db.Writer(ctx).ExecContext(ctx, `UPDATE vaults SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`, vaultID)
+	if err != nil {
+		return fmt.Errorf("failed to mark vault %s as deleted: %w", vaultID, err)
+	}
+	return nil
+}
+
+func (r *VaultRepo) FindAllDeleted(ctx context.Context, userID string, deletedAfter time.Time) ([]models.UserVaultWithRole, error) {
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, `
+		SELECT v.id, v.name, v.description, v.icon, v.color, v.base_currency, COALESCE(v.default_payment_method_id, ''), uv.role, v.deleted_at FROM vaults v
+		JOIN user_vaults uv ON uv.vault_id = v.id
+		WHERE uv.user_id = $1 AND v.deleted_at IS NOT NULL AND v.deleted_at >= $2
+		ORDER BY v.deleted_at DESC
+	`, userID, formatTime(deletedAfter))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deleted vaults: %w", err)
+	}
+
+	defer rows.Close()
+
+	vaults := []models.UserVaultWithRole{}
+
+	for rows.Next() {
+		var v models.UserVaultWithRole
+		if err := rows.Scan(&v.ID, &v.Name, &v.Description, &v.Icon, &v.Color, &v.BaseCurrency, &v.DefaultPaymentMethodID, &v.UserRole, &v.DeletedAt); err != nil {
+			return nil, err
+		}
+		vaults = append(vaults, v)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return vaults, nil
+}
+
+func (r *VaultRepo) FindOneDeletedByID(ctx context.Context, userID, vaultID string, deletedAfter time.Time) (*models.UserVaultWithRole, error) {
+	v := models.UserVaultWithRole{} // nolint: exhaustruct
+
+	err := r.db.Reader(ctx).QueryRowContext(ctx, `
+		SELECT v.id, v.name, v.description, v.icon, v.color, v.base_currency, COALESCE(v.default_payment_method_id, ''), uv.role, v.deleted_at FROM vaults v
+		JOIN user_vaults uv ON uv.vault_id = v.id
+		WHERE v.id = $1 AND uv.user_id = $2 AND v.deleted_at IS NOT NULL AND v.deleted_at >= $3
+		`, vaultID, userID, formatTime(deletedAfter)).Scan(&v.ID, &v.Name, &v.Description, &v.Icon, &v.Color, &v.BaseCurrency, &v.DefaultPaymentMethodID, &v.UserRole, &v.DeletedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrVaultNotFound
+		}
+
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+func (r *VaultRepo) RestoreOneByID(ctx context.Context, vaultID string) error {
+	_, err := r.db.Writer(ctx).ExecContext(ctx, `UPDATE vaults SET deleted_at = NULL WHERE id = $1`, vaultID)
+	if err != nil {
+		return fmt.Errorf("failed to restore vault %s: %w", vaultID, err)
+	}
+	return nil
+}
+
+func (r *VaultRepo) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	res, err := r.db.Writer(ctx).ExecContext(ctx, `DELETE FROM vaults WHERE deleted_at IS NOT NULL AND deleted_at < $1`, formatTime(deletedBefore))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted vaults: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+func (r *VaultRepo) FindMembers(ctx context.Context, vaultID string) ([]models.VaultMember, error) {
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, `
+		SELECT u.id, u.first_name, u.last_name, u.email, uv.role FROM users u
+		JOIN user_vaults uv ON uv.user_id = u.id
+		WHERE uv.vault_id = $1
+		ORDER BY u.first_name, u.last_name, u.id
+	`, vaultID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query members of vault %s: %w", vaultID, err)
+	}
+
+	defer rows.Close()
+
+	members := []models.VaultMember{}
+
+	for rows.Next() {
+		var m models.VaultMember
+		if err := rows.Scan(&m.UserID, &m.FirstName, &m.LastName, &m.Email, &m.Role); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return members, nil
 }
 
 func (r *VaultRepo) AddUser(ctx context.Context, vaultID, userID string, userRole models.VaultRole) error {
-	_, err := r.db.ExecContext(ctx, `INSERT INTO user_vaults(user_id, vault_id, role) VALUES ($1, $2, $3)`, userID, vaultID, userRole)
+	_, err := r.db.Writer(ctx).ExecContext(ctx, `INSERT INTO user_vaults(user_id, vault_id, role) VALUES ($1, $2, $3)`, userID, vaultID, userRole)
 	if err != nil {
 		return fmt.Errorf("failed to add user %s to vault %s: %w", userID, vaultID, err)
 	}
 
 	return nil
 }
+
+func (r *VaultRepo) TransferOwnership(ctx context.Context, vaultID, fromUserID, toUserID string, fromUserNewRole models.VaultRole) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback() // nolint: errcheck
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE user_vaults
+		SET role = $1
+		WHERE vault_id = $2 AND user_id = $3`,
+		models.VaultRoleOwner, vaultID, toUserID)
+	if err != nil {
+		return fmt.Errorf("failed to promote user %s to owner of vault %s: %w", toUserID, vaultID, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to check promoted rows: %w", err)
+	} else if n == 0 {
+		return ErrVaultNotFound
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE user_vaults
+		SET role = $1
+		WHERE vault_id = $2 AND user_id = $3`,
+		fromUserNewRole, vaultID, fromUserID)
+	if err != nil {
+		return fmt.Errorf("failed to set role %s for user %s in vault %s: %w", fromUserNewRole, fromUserID, vaultID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -22,7 +23,7 @@ func TestVaultRepo_CreateOne(t *testing.T) {
 		user := testutils.CreateTestUser(t, db)
 		vaultRepo := repositories.NewVaultRepo(db)
 
-		_, err := vaultRepo.CreateOne(ctx, user.ID, models.VaultRoleOwner, "some name")
+		_, err := vaultRepo.CreateOne(ctx, user.ID, models.VaultRoleOwner, "some name", nil)
 		testutils.AssertNoError(t, err)
 
 		foundVaults, err := vaultRepo.FindAll(ctx, user.ID)
@@ -46,7 +47,7 @@ func TestVaultRepo_FindAll(t *testing.T) {
 		userRole := models.VaultRoleOwner
 		vaultName := "some name"
 
-		_, err := vaultRepo.CreateOne(ctx, userID, userRole, vaultName)
+		_, err := vaultRepo.CreateOne(ctx, userID, userRole, vaultName, nil)
 		testutils.AssertNoError(t, err)
 
 		foundVaults, err := vaultRepo.FindAll(ctx, userID)
@@ -84,7 +85,7 @@ func TestVaultRepo_FindOneByID(t *testing.T) {
 		userRole := models.VaultRoleOwner
 		vaultName := "some name"
 
-		vaultID, err := vaultRepo.CreateOne(ctx, userID, userRole, vaultName)
+		vaultID, err := vaultRepo.CreateOne(ctx, userID, userRole, vaultName, nil)
 		testutils.AssertNoError(t, err)
 
 		vault, err := vaultRepo.FindOneByID(ctx, userID, vaultID)
@@ -111,7 +112,7 @@ func TestVaultRepo_FindOneByName(t *testing.T) {
 		userRole := models.VaultRoleOwner
 		vaultName := "some name"
 
-		vaultID, err := vaultRepo.CreateOne(ctx, userID, userRole, vaultName)
+		vaultID, err := vaultRepo.CreateOne(ctx, userID, userRole, vaultName, nil)
 		testutils.AssertNoError(t, err)
 
 		vault, err := vaultRepo.FindOneByName(ctx, userID, vaultName)
@@ -150,7 +151,7 @@ func TestVaultRepo_DeleteOneByID(t *testing.T) {
 
 		vaultRepo := repositories.NewVaultRepo(db)
 
-		vaultID, err := vaultRepo.CreateOne(ctx, user.ID, models.VaultRoleOwner, "some name")
+		vaultID, err := vaultRepo.CreateOne(ctx, user.ID, models.VaultRoleOwner, "some name", nil)
 		testutils.AssertNoError(t, err)
 
 		err = vaultRepo.DeleteOneByID(ctx, vaultID)
@@ -177,7 +178,7 @@ func TestVaultRepo_AddUser(t *testing.T) {
 		userRole := models.VaultRoleOwner
 		vaultName := "some name"
 
-		vaultID, err := vaultRepo.CreateOne(ctx, userID, userRole, vaultName)
+		vaultID, err := vaultRepo.CreateOne(ctx, userID, userRole, vaultName, nil)
 		testutils.AssertNoError(t, err)
 
 		invitee := testutils.CreateTestUser(t, db)
@@ -194,3 +195,205 @@ func TestVaultRepo_AddUser(t *testing.T) {
 		testutils.AssertEqual(t, inviteeVaultWithRole.UserRole, inviteeRole)
 	})
 }
+
+func TestVaultRepo_TransferOwnership(t *testing.T) {
+	t.Parallel()
+
+	t.Run("promotes new owner and demotes previous one", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		owner := testutils.CreateTestUser(t, db)
+		member := testutils.CreateTestUser(t, db)
+		vaultRepo := repositories.NewVaultRepo(db)
+
+		vaultID, err := vaultRepo.CreateOne(ctx, owner.ID, models.VaultRoleOwner, "some name", nil)
+		testutils.AssertNoError(t, err)
+		err = vaultRepo.AddUser(ctx, vaultID, member.ID, models.VaultRoleEditor)
+		testutils.AssertNoError(t, err)
+
+		err = vaultRepo.TransferOwnership(ctx, vaultID, owner.ID, member.ID, models.VaultRoleAdmin)
+		testutils.AssertNoError(t, err)
+
+		memberVault, err := vaultRepo.FindOneByID(ctx, member.ID, vaultID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, memberVault.UserRole, models.VaultRoleOwner)
+
+		ownerVault, err := vaultRepo.FindOneByID(ctx, owner.ID, vaultID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, ownerVault.UserRole, models.VaultRoleAdmin)
+	})
+
+	t.Run("does not change previous owner's role if new owner is not a member", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		owner := testutils.CreateTestUser(t, db)
+		outsider := testutils.CreateTestUser(t, db)
+		vaultRepo := repositories.NewVaultRepo(db)
+
+		vaultID, err := vaultRepo.CreateOne(ctx, owner.ID, models.VaultRoleOwner, "some name", nil)
+		testutils.AssertNoError(t, err)
+
+		err = vaultRepo.TransferOwnership(ctx, vaultID, owner.ID, outsider.ID, models.VaultRoleViewer)
+		if !errors.Is(err, repositories.ErrVaultNotFound) {
+			t.Errorf("expected error %q, got %v", repositories.ErrVaultNotFound, err)
+		}
+
+		ownerVault, err := vaultRepo.FindOneByID(ctx, owner.ID, vaultID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, ownerVault.UserRole, models.VaultRoleOwner)
+	})
+}
+
+func TestVaultRepo_FindAllDeleted(t *testing.T) {
+	t.Parallel()
+
+	t.Run("finds soft deleted vaults deleted after given time", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		user := testutils.CreateTestUser(t, db)
+		vaultRepo := repositories.NewVaultRepo(db)
+
+		vaultID, err := vaultRepo.CreateOne(ctx, user.ID, models.VaultRoleOwner, "some name", nil)
+		testutils.AssertNoError(t, err)
+		_, err = vaultRepo.CreateOne(ctx, user.ID, models.VaultRoleOwner, "other name", nil)
+		testutils.AssertNoError(t, err)
+
+		err = vaultRepo.DeleteOneByID(ctx, vaultID)
+		testutils.AssertNoError(t, err)
+
+		deletedVaults, err := vaultRepo.FindAllDeleted(ctx, user.ID, time.Now().Add(-time.Hour))
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(deletedVaults), 1)
+		testutils.AssertEqual(t, deletedVaults[0].ID, vaultID)
+		testutils.AssertValidDate(t, deletedVaults[0].DeletedAt)
+
+		deletedVaults, err = vaultRepo.FindAllDeleted(ctx, user.ID, time.Now().Add(time.Hour))
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(deletedVaults), 0)
+	})
+}
+
+func TestVaultRepo_RestoreOneByID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("restores soft deleted vault", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		user := testutils.CreateTestUser(t, db)
+		vaultRepo := repositories.NewVaultRepo(db)
+
+		vaultID, err := vaultRepo.CreateOne(ctx, user.ID, models.VaultRoleOwner, "some name", nil)
+		testutils.AssertNoError(t, err)
+
+		err = vaultRepo.DeleteOneByID(ctx, vaultID)
+		testutils.AssertNoError(t, err)
+
+		err = vaultRepo.RestoreOneByID(ctx, vaultID)
+		testutils.AssertNoError(t, err)
+
+		vault, err := vaultRepo.FindOneByID(ctx, user.ID, vaultID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, vault.ID, vaultID)
+	})
+}
+
+func TestVaultRepo_PurgeDeleted(t *testing.T) {
+	t.Parallel()
+
+	t.Run("permanently removes vaults deleted before given time", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		user := testutils.CreateTestUser(t, db)
+		vaultRepo := repositories.NewVaultRepo(db)
+
+		deletedVaultID, err := vaultRepo.CreateOne(ctx, user.ID, models.VaultRoleOwner, "some name", nil)
+		testutils.AssertNoError(t, err)
+		_, err = vaultRepo.CreateOne(ctx, user.ID, models.VaultRoleOwner, "other name", nil)
+		testutils.AssertNoError(t, err)
+
+		err = vaultRepo.DeleteOneByID(ctx, deletedVaultID)
+		testutils.AssertNoError(t, err)
+
+		purged, err := vaultRepo.PurgeDeleted(ctx, time.Now().Add(-time.Hour))
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, purged, 0)
+
+		purged, err = vaultRepo.PurgeDeleted(ctx, time.Now().Add(time.Hour))
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, purged, 1)
+
+		deletedVaults, err := vaultRepo.FindAllDeleted(ctx, user.ID, time.Time{})
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(deletedVaults), 0)
+
+		foundVaults, err := vaultRepo.FindAll(ctx, user.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(foundVaults), 1)
+	})
+}
+
+func TestVaultRepo_UpdateOne(t *testing.T) {
+	t.Parallel()
+
+	t.Run("updates vault name and metadata", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		user := testutils.CreateTestUser(t, db)
+		vaultRepo := repositories.NewVaultRepo(db)
+
+		vaultID, err := vaultRepo.CreateOne(ctx, user.ID, models.VaultRoleOwner, "some name", nil)
+		testutils.AssertNoError(t, err)
+
+		vault, err := vaultRepo.FindOneByID(ctx, user.ID, vaultID)
+		testutils.AssertNoError(t, err)
+
+		vault.Name = "new name"
+		vault.Description = "household expenses"
+		vault.Icon = "house"
+		vault.Color = "#aabbcc"
+		vault.BaseCurrency = "EUR"
+		vault.DefaultPaymentMethodID = testutils.CreateTestPaymentMethod(t, db, user.ID, vaultID).ID
+
+		err = vaultRepo.UpdateOne(ctx, vault)
+		testutils.AssertNoError(t, err)
+
+		foundVaults, err := vaultRepo.FindAll(ctx, user.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(foundVaults), 1)
+		testutils.AssertEqual(t, foundVaults[0], *vault)
+	})
+}
+
+func TestVaultRepo_DefaultPaymentMethod(t *testing.T) {
+	t.Parallel()
+
+	t.Run("clears default payment method when it is deleted", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		user := testutils.CreateTestUser(t, db)
+		vaultRepo := repositories.NewVaultRepo(db)
+
+		vaultID, err := vaultRepo.CreateOne(ctx, user.ID, models.VaultRoleOwner, "some name", nil)
+		testutils.AssertNoError(t, err)
+		paymentMethod := testutils.CreateTestPaymentMethod(t, db, user.ID, vaultID)
+
+		vault, err := vaultRepo.FindOneByID(ctx, user.ID, vaultID)
+		testutils.AssertNoError(t, err)
+		vault.DefaultPaymentMethodID = paymentMethod.ID
+		testutils.AssertNoError(t, vaultRepo.UpdateOne(ctx, vault))
+
+		err = repositories.NewPaymentMethodRepo(db).DeleteOneByID(ctx, paymentMethod.ID)
+		testutils.AssertNoError(t, err)
+
+		vault, err = vaultRepo.FindOneByID(ctx, user.ID, vaultID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, vault.DefaultPaymentMethodID, "")
+	})
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/permissions"
+	"github.com/kkstas/tr-backend/internal/repositories"
+	"github.com/kkstas/tr-backend/internal/tracing"
+)
+
+var ErrAccountNotFound = errors.New("account not found")
+var ErrAccountWithThatNameAlreadyExists = errors.New("account with that name already exists")
+var ErrAccountInUse = errors.New("account is used by expenses, incomes or transfers")
+
+type AccountService struct {
+	accountRepo  *repositories.AccountRepo
+	vaultService *VaultService
+}
+
+func NewAccountService(accountRepo *repositories.AccountRepo, vaultService *VaultService) *AccountService {
+	return &AccountService{
+		accountRepo:  accountRepo,
+		vaultService: vaultService,
+	}
+}
+
+func (s *AccountService) CreateOne(ctx context.Context, userID string, account models.Account) error {
+	ctx, span := tracing.Start(ctx, "AccountService.CreateOne")
+	defer span.End()
+
+	vault, err := s.vaultService.FindOneByID(ctx, userID, account.VaultID)
+	if err != nil {
+		return err
+	}
+
+	if !permissions.Can(vault.UserRole, permissions.ActionManageAccounts) {
+		return ErrInsufficientVaultPermissions
+	}
+
+	accounts, err := s.accountRepo.FindAll(ctx, vault.ID)
+	if err != nil {
+		return fmt.Errorf("failed to find existing accounts in vault %s before creating one: %w", vault.ID, err)
+	}
+
+	if accountNameTaken(accounts, account.Name, "") {
+		return ErrAccountWithThatNameAlreadyExists
+	}
+
+	account.CreatedBy = userID
+
+	_, err = s.accountRepo.CreateOne(ctx, account)
+	if err != nil {
+		return fmt.Errorf("failed to create account in vault %s: %w", vault.ID, err)
+	}
+
+	return nil
+}
+
+func (s *AccountService) FindAll(ctx context.Context, userID, vaultID string) ([]models.Account, error) {
+	ctx, span := tracing.Start(ctx, "AccountService.FindAll")
+	defer span.End()
+
+	vault, err := s.vaultService.FindOneByID(ctx, userID, vaultID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !permissions.Can(vault.UserRole, permissions.ActionRead) {
+		return nil, ErrInsufficientVaultPermissions
+	}
+
+	accounts, err := s.accountRepo.FindAll(ctx, vault.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find accounts for vault %s & user %s: %w", vault.ID, userID, err)
+	}
+
+	return accounts, nil
+}
+
+func (s *AccountService) FindOneByID(ctx context.Context, userID, accountID string) (*models.Account, error) {
+	ctx, span := tracing.Start(ctx, "AccountService.FindOneByID")
+	defer span.End()
+
+	account, _, err := s.findOneWithRole(ctx, userID, accountID)
+	return account, err
+}
+
+func (s *AccountService) UpdateOne(ctx context.Context, userID, accountID string, update models.AccountUpdate) (*models.Account, error) {
+	ctx, span := tracing.Start(ctx, "AccountService.UpdateOne")
+	defer span.End()
+
+	account, err := s.findOneForManagement(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Name != nil && *update.Name != account.Name {
+		accounts, err := s.accountRepo.FindAll(ctx, account.VaultID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find existing accounts in vault %s before renaming one: %w", account.VaultID, err)
+		}
+		if accountNameTaken(accounts, *update.Name, account.ID) {
+			return nil, ErrAccountWithThatNameAlreadyExists
+		}
+		account.Name = *update.Name
+	}
+	if update.Type != nil {
+		account.Type = *update.Type
+	}
+	if update.OpeningBalance != nil {
+		account.Balance += *update.OpeningBalance - account.OpeningBalance
+		account.OpeningBalance = *update.OpeningBalance
+	}
+
+	err = s.accountRepo.UpdateOne(ctx, account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update account %s as user %s: %w", accountID, userID, err)
+	}
+
+	return account, nil
+}
+
+func (s *AccountService) DeleteOneByID(ctx context.Context, userID, accountID string) error {
+	ctx, span := tracing.Start(ctx, "AccountService.DeleteOneByID")
+	defer span.End()
+
+	account, err := s.findOneForManagement(ctx, userID, accountID)
+	if err != nil {
+		return err
+	}
+
+	err = s.accountRepo.DeleteOneByID(ctx, account.ID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrAccountInUse) {
+			return ErrAccountInUse
+		}
+		return fmt.Errorf("failed to delete account %s as user %s: %w", accountID, userID, err)
+	}
+
+	return nil
+}
+
+// Ledger returns all entries that changed balance of the account, oldest first,
+// each with the running balance of the account after that entry.
+func (s *AccountService) Ledger(ctx context.Context, userID, accountID string) (*models.AccountLedger, error) {
+	ctx, span := tracing.Start(ctx, "AccountService.Ledger")
+	defer span.End()
+
+	account, err := s.FindOneByID(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.accountRepo.FindLedgerEntries(ctx, account.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find ledger entries of account %s: %w", account.ID, err)
+	}
+
+	balance := account.OpeningBalance
+	for i := range entries {
+		balance += entries[i].Amount
+		entries[i].Balance = balance
+	}
+
+	return &models.AccountLedger{Account: *account, Entries: entries}, nil
+}
+
+func (s *AccountService) findOneForManagement(ctx context.Context, userID, accountID string) (*models.Account, error) {
+	account, role, err := s.findOneWithRole(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !permissions.Can(role, permissions.ActionManageAccounts) {
+		return nil, ErrInsufficientVaultPermissions
+	}
+
+	return account, nil
+}
+
+func (s *AccountService) findOneWithRole(ctx context.Context, userID, accountID string) (*models.Account, models.VaultRole, error) {
+	account, err := s.accountRepo.FindOneByID(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrAccountNotFound) {
+			return nil, "", ErrAccountNotFound
+		}
+		return nil, "", fmt.Errorf("failed to find account %s: %w", accountID, err)
+	}
+
+	vault, err := s.vaultService.FindOneByID(ctx, userID, account.VaultID)
+	if err != nil {
+		if errors.Is(err, ErrVaultNotFound) {
+			return nil, "", ErrAccountNotFound
+		}
+		return nil, "", err
+	}
+
+	return account, vault.UserRole, nil
+}
+
+func accountNameTaken(accounts []models.Account, name, exceptID string) bool {
+	for _, account := range accounts {
+		if account.Name == name && account.ID != exceptID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestAccountService_CreateOne(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns error if account with that name already exists", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		accountService := testutils.NewTestAccountService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		account := models.Account{Name: "wallet", Type: models.AccountTypeCash, VaultID: vault.ID} // nolint: exhaustruct
+
+		err := accountService.CreateOne(ctx, user.ID, account)
+		testutils.AssertNoError(t, err)
+
+		err = accountService.CreateOne(ctx, user.ID, account)
+		want := services.ErrAccountWithThatNameAlreadyExists
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+
+	t.Run("returns error if user cannot manage accounts", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		accountService := testutils.NewTestAccountService(db)
+		_, owner, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		editor := testutils.CreateTestUser(t, db)
+		err := testutils.NewTestVaultService(db).AddUser(ctx, owner.ID, editor.ID, vault.ID, models.VaultRoleEditor)
+		testutils.AssertNoError(t, err)
+
+		err = accountService.CreateOne(ctx, editor.ID, models.Account{Name: "wallet", Type: models.AccountTypeCash, VaultID: vault.ID}) // nolint: exhaustruct
+		want := services.ErrInsufficientVaultPermissions
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+}
+
+func TestAccountService_Ledger(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns entries with running balance", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		accountService := testutils.NewTestAccountService(db)
+		transferService := testutils.NewTestTransferService(db)
+		expenseService := testutils.NewTestExpenseService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		checking := testutils.CreateTestAccount(t, db, user.ID, vault.ID, 500)
+		savings := testutils.CreateTestAccount(t, db, user.ID, vault.ID, 1000)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		paymentMethod := testutils.CreateTestPaymentMethod(t, db, user.ID, vault.ID)
+
+		err := expenseService.CreateOne(ctx, user.ID, models.Expense{ // nolint: exhaustruct
+			Name:            "rent",
+			Date:            "2025-01-10",
+			CategoryID:      category.ID,
+			Amount:          400,
+			PaymentMethodID: paymentMethod.ID,
+			AccountID:       checking.ID,
+			VaultID:         vault.ID,
+		})
+		testutils.AssertNoError(t, err)
+
+		err = transferService.CreateOne(ctx, user.ID, models.Transfer{ // nolint: exhaustruct
+			FromAccountID: savings.ID,
+			ToAccountID:   checking.ID,
+			Amount:        250,
+			Date:          "2025-01-05",
+			VaultID:       vault.ID,
+		})
+		testutils.AssertNoError(t, err)
+
+		ledger, err := accountService.Ledger(ctx, user.ID, checking.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, ledger.Account.Balance, 350.0)
+		testutils.AssertEqual(t, len(ledger.Entries), 2)
+		testutils.AssertEqual(t, ledger.Entries[0].Kind, models.LedgerEntryKindTransferIn)
+		testutils.AssertEqual(t, ledger.Entries[0].Balance, 750.0)
+		testutils.AssertEqual(t, ledger.Entries[1].Kind, models.LedgerEntryKindExpense)
+		testutils.AssertEqual(t, ledger.Entries[1].Balance, 350.0)
+	})
+
+	t.Run("returns error if user does not belong to account's vault", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		accountService := testutils.NewTestAccountService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		otherUser := testutils.CreateTestUser(t, db)
+		account := testutils.CreateTestAccount(t, db, user.ID, vault.ID, 0)
+
+		_, err := accountService.Ledger(ctx, otherUser.ID, account.ID)
+		want := services.ErrAccountNotFound
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/permissions"
+	"github.com/kkstas/tr-backend/internal/repositories"
+	"github.com/kkstas/tr-backend/internal/tracing"
+)
+
+var ErrInvalidAuditCursor = errors.New("invalid activity cursor")
+
+type AuditService struct {
+	auditRepo    *repositories.AuditRepo
+	vaultService *VaultService
+}
+
+func NewAuditService(auditRepo *repositories.AuditRepo, vaultService *VaultService) *AuditService {
+	return &AuditService{
+		auditRepo:    auditRepo,
+		vaultService: vaultService,
+	}
+}
+
+// FindAll returns a page of the vault's activity, newest first. An empty cursor starts from the newest event.
+func (s *AuditService) FindAll(ctx context.Context, userID, vaultID, entityID, cursor string, limit int) (*models.AuditEventPage, error) {
+	ctx, span := tracing.Start(ctx, "AuditService.FindAll")
+	defer span.End()
+
+	var beforeID int64
+	if cursor != "" {
+		id, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil || id < 1 {
+			return nil, ErrInvalidAuditCursor
+		}
+		beforeID = id
+	}
+
+	vault, err := s.vaultService.FindOneByID(ctx, userID, vaultID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !permissions.Can(vault.UserRole, permissions.ActionRead) {
+		return nil, ErrInsufficientVaultPermissions
+	}
+
+	events, err := s.auditRepo.FindAll(ctx, vault.ID, entityID, beforeID, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find activity of vault %s & user %s: %w", vault.ID, userID, err)
+	}
+
+	page := &models.AuditEventPage{Events: events} // nolint: exhaustruct
+	if len(events) > limit {
+		page.Events = events[:limit]
+		page.NextCursor = strconv.FormatInt(page.Events[limit-1].ID, 10)
+	}
+
+	return page, nil
+}
+
+func auditEvent(vaultID, actorID string, action models.AuditAction, entityType models.AuditEntity, entityID string) models.AuditEvent {
+	return models.AuditEvent{ // nolint: exhaustruct
+		VaultID:    vaultID,
+		ActorID:    actorID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+	}
+}
+
+// recordAuditEvent stores event with JSON snapshots of the entity before and after the change.
+// Pass nil as before for creations and as after for deletions.
+func recordAuditEvent(ctx context.Context, auditRepo *repositories.AuditRepo, event models.AuditEvent, before, after any) error {
+	var err error
+	if before != nil {
+		if event.Before, err = json.Marshal(before); err != nil {
+			return fmt.Errorf("failed to encode audit snapshot of %s %s: %w", event.EntityType, event.EntityID, err)
+		}
+	}
+	if after != nil {
+		if event.After, err = json.Marshal(after); err != nil {
+			return fmt.Errorf("failed to encode audit snapshot of %s %s: %w", event.EntityType, event.EntityID, err)
+		}
+	}
+
+	if err := auditRepo.CreateOne(ctx, event); err != nil {
+		return fmt.Errorf("failed to record %s of %s %s: %w", event.Action, event.EntityType, event.EntityID, err)
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/database"
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestAuditService_FindAll(t *testing.T) {
+	t.Parallel()
+
+	t.Run("records expense changes with before and after snapshots", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		auditService := testutils.NewTestAuditService(db)
+		expenseService := testutils.NewTestExpenseService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		name := "renamed"
+		_, err := expenseService.UpdateOne(ctx, user.ID, expense.ID, models.ExpenseUpdate{Name: &name}) // nolint: exhaustruct
+		testutils.AssertNoError(t, err)
+		testutils.AssertNoError(t, expenseService.DeleteOneByID(ctx, user.ID, expense.ID))
+
+		page, err := auditService.FindAll(ctx, user.ID, vault.ID, expense.ID, "", 10)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(page.Events), 2)
+		testutils.AssertEqual(t, page.NextCursor, "")
+
+		deleted, updated := page.Events[0], page.Events[1]
+		testutils.AssertEqual(t, deleted.Action, models.AuditActionDelete)
+		testutils.AssertEqual(t, string(deleted.After), "")
+		testutils.AssertEqual(t, updated.Action, models.AuditActionUpdate)
+		testutils.AssertEqual(t, updated.ActorID, user.ID)
+		testutils.AssertEqual(t, updated.EntityType, models.AuditEntityExpense)
+
+		var before, after models.Expense
+		testutils.AssertNoError(t, json.Unmarshal(updated.Before, &before))
+		testutils.AssertNoError(t, json.Unmarshal(updated.After, &after))
+		testutils.AssertEqual(t, before.Name, expense.Name)
+		testutils.AssertEqual(t, after.Name, "renamed")
+	})
+
+	t.Run("records membership changes", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		auditService := testutils.NewTestAuditService(db)
+		_, owner, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		member := testutils.CreateTestUser(t, db)
+
+		testutils.AssertNoError(t, testutils.NewTestVaultService(db).AddUser(ctx, owner.ID, member.ID, vault.ID, models.VaultRoleEditor))
+
+		page, err := auditService.FindAll(ctx, owner.ID, vault.ID, member.ID, "", 10)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(page.Events), 1)
+		testutils.AssertEqual(t, page.Events[0].EntityType, models.AuditEntityMember)
+		testutils.AssertEqual(t, page.Events[0].Action, models.AuditActionCreate)
+		testutils.AssertEqual(t, string(page.Events[0].After), `{"userID":"`+member.ID+`","role":"editor"}`)
+	})
+
+	t.Run("paginates events from newest to oldest", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		auditService := testutils.NewTestAuditService(db)
+		categoryService := testutils.NewTestExpenseCategoryService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+
+		for _, name := range []string{"food", "rent", "travel"} {
+			testutils.AssertNoError(t, categoryService.CreateOne(ctx, name, user.ID, vault.ID, ""))
+		}
+
+		first, err := auditService.FindAll(ctx, user.ID, vault.ID, "", "", 2)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(first.Events), 2)
+		testutils.AssertNotEmpty(t, first.NextCursor)
+
+		second, err := auditService.FindAll(ctx, user.ID, vault.ID, "", first.NextCursor, 2)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(second.Events), 1)
+		testutils.AssertEqual(t, second.NextCursor, "")
+
+		var oldest models.ExpenseCategory
+		testutils.AssertNoError(t, json.Unmarshal(second.Events[0].After, &oldest))
+		testutils.AssertEqual(t, oldest.Name, "food")
+	})
+
+	t.Run("returns error if user is not a member of the vault", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		auditService := testutils.NewTestAuditService(db)
+		_, _, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		otherUser := testutils.CreateTestUser(t, db)
+
+		_, err := auditService.FindAll(ctx, otherUser.ID, vault.ID, "", "", 10)
+		want := services.ErrVaultNotFound
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+
+	t.Run("events cannot be modified or removed", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		testutils.AssertNoError(t, testutils.NewTestExpenseCategoryService(db).CreateOne(ctx, "food", user.ID, vault.ID, ""))
+
+		if _, err := db.ExecContext(ctx, `UPDATE audit_events SET actor_id = 'someone else'`); err == nil {
+			t.Error("expected updating audit events to fail")
+		}
+		if _, err := db.ExecContext(ctx, `DELETE FROM audit_events`); err == nil {
+			t.Error("expected deleting audit events to fail")
+		}
+	})
+}
+
+func TestAuditEvents_RecordedAtomically(t *testing.T) {
+	t.Parallel()
+
+	failAuditInserts := func(t *testing.T, db *database.DB) {
+		t.Helper()
+		_, err := db.ExecContext(t.Context(), `
+			CREATE TRIGGER fail_audit_events BEFORE INSERT ON audit_events
+			BEGIN
+				SELECT RAISE(ABORT, 'audit events are unavailable');
+			END`)
+		testutils.AssertNoError(t, err)
+	}
+
+	t.Run("rolls back expense update if audit event can't be recorded", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		expenseService := testutils.NewTestExpenseService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+		failAuditInserts(t, db)
+
+		name := "renamed"
+		_, err := expenseService.UpdateOne(ctx, user.ID, expense.ID, models.ExpenseUpdate{Name: &name}) // nolint: exhaustruct
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+
+		found, err := expenseService.FindOneByID(ctx, user.ID, expense.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, found.Name, expense.Name)
+	})
+
+	t.Run("rolls back vault creation if audit event can't be recorded", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		vaultService := testutils.NewTestVaultService(db)
+		user := testutils.CreateTestUser(t, db)
+		failAuditInserts(t, db)
+
+		err := vaultService.CreateOne(ctx, user.ID, "vault", "", "")
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+
+		vaults, err := vaultService.FindAll(ctx, user.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(vaults), 0)
+
+		found, err := testutils.NewTestUserService(db).FindOneByID(ctx, user.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, found.ActiveVault, "")
+	})
+
+	t.Run("rolls back expense category merge if audit event can't be recorded", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		categoryService := testutils.NewTestExpenseCategoryService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		source := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		target := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, source.ID)
+		failAuditInserts(t, db)
+
+		err := categoryService.Merge(ctx, user.ID, source.ID, target.ID)
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+
+		_, err = categoryService.FindOneByID(ctx, user.ID, source.ID)
+		testutils.AssertNoError(t, err)
+		found, err := testutils.NewTestExpenseService(db).FindOneByID(ctx, user.ID, expense.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, found.CategoryID, source.ID)
+	})
+}
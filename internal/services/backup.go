@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kkstas/tr-backend/internal/backup"
+	"github.com/kkstas/tr-backend/internal/database"
+	"github.com/kkstas/tr-backend/internal/tracing"
+)
+
+type BackupService struct {
+	db      *database.DB
+	options backup.Options
+}
+
+func NewBackupService(db *database.DB, options backup.Options) *BackupService {
+	return &BackupService{
+		db:      db,
+		options: options,
+	}
+}
+
+func (s *BackupService) CreateOne(ctx context.Context) (*backup.Snapshot, error) {
+	ctx, span := tracing.Start(ctx, "BackupService.CreateOne")
+	defer span.End()
+
+	var dbPath string
+	err := s.db.Read.QueryRowContext(ctx, `SELECT file FROM pragma_database_list WHERE name = 'main'`).Scan(&dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find database file: %w", err)
+	}
+
+	return backup.Create(ctx, dbPath, s.options)
+}
@@ -0,0 +1,468 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/kkstas/tr-backend/internal/database"
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/permissions"
+	"github.com/kkstas/tr-backend/internal/repositories"
+	"github.com/kkstas/tr-backend/internal/splits"
+	"github.com/kkstas/tr-backend/internal/tracing"
+)
+
+const maxSearchTerms = 10
+
+var ErrExpenseNotFound = errors.New("expense not found")
+var ErrExpenseCategoryInactive = errors.New("expense category is inactive")
+
+type ExpenseService struct {
+	db                     *database.DB
+	expenseRepo            *repositories.ExpenseRepo
+	auditRepo              *repositories.AuditRepo
+	vaultService           *VaultService
+	expenseCategoryService *ExpenseCategoryService
+	paymentMethodService   *PaymentMethodService
+	accountService         *AccountService
+	tagService             *TagService
+}
+
+func NewExpenseService(
+	db *database.DB,
+	expenseRepo *repositories.ExpenseRepo,
+	auditRepo *repositories.AuditRepo,
+	vaultService *VaultService,
+	expenseCategoryService *ExpenseCategoryService,
+	paymentMethodService *PaymentMethodService,
+	accountService *AccountService,
+	tagService *TagService,
+) *ExpenseService {
+	return &ExpenseService{
+		db:                     db,
+		expenseRepo:            expenseRepo,
+		auditRepo:              auditRepo,
+		vaultService:           vaultService,
+		expenseCategoryService: expenseCategoryService,
+		paymentMethodService:   paymentMethodService,
+		accountService:         accountService,
+		tagService:             tagService,
+	}
+}
+
+func (s *ExpenseService) CreateOne(ctx context.Context, userID string, expense models.Expense) error {
+	ctx, span := tracing.Start(ctx, "ExpenseService.CreateOne")
+	defer span.End()
+
+	vault, err := s.vaultService.FindOneByID(ctx, userID, expense.VaultID)
+	if err != nil {
+		return err
+	}
+
+	if !permissions.Can(vault.UserRole, permissions.ActionWriteExpenses) {
+		return ErrInsufficientVaultPermissions
+	}
+
+	if err := s.checkCategory(ctx, userID, vault.ID, expense.CategoryID); err != nil {
+		return err
+	}
+	if err := s.checkPaymentMethod(ctx, userID, vault.ID, expense.PaymentMethodID); err != nil {
+		return err
+	}
+	if err := s.checkAccount(ctx, userID, vault.ID, expense.AccountID); err != nil {
+		return err
+	}
+	if err := s.tagService.checkTags(ctx, vault.ID, expense.TagIDs); err != nil {
+		return err
+	}
+
+	if expense.PaidBy == "" {
+		expense.PaidBy = userID
+	}
+	if err := s.checkSplit(ctx, userID, vault.ID, expense); err != nil {
+		return err
+	}
+
+	expense.CreatedBy = userID
+
+	return s.db.WithTx(ctx, func(ctx context.Context) error {
+		expenseID, err := s.expenseRepo.CreateOne(ctx, expense)
+		if err != nil {
+			return fmt.Errorf("failed to create expense in vault %s: %w", vault.ID, err)
+		}
+
+		created, err := s.expenseRepo.FindOneByID(ctx, expenseID)
+		if err != nil {
+			return fmt.Errorf("failed to find expense %s after creating it: %w", expenseID, err)
+		}
+
+		return recordAuditEvent(ctx, s.auditRepo, auditEvent(vault.ID, userID, models.AuditActionCreate, models.AuditEntityExpense, expenseID), nil, created)
+	})
+}
+
+func (s *ExpenseService) UpdateOne(ctx context.Context, userID, expenseID string, update models.ExpenseUpdate) (*models.Expense, error) {
+	ctx, span := tracing.Start(ctx, "ExpenseService.UpdateOne")
+	defer span.End()
+
+	expense, err := s.expenseRepo.FindOneByID(ctx, expenseID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrExpenseNotFound) {
+			return nil, ErrExpenseNotFound
+		}
+		return nil, fmt.Errorf("failed to find expense %s: %w", expenseID, err)
+	}
+
+	if err := s.checkWritePermission(ctx, userID, expense.VaultID); err != nil {
+		return nil, err
+	}
+
+	before := *expense
+
+	if update.Name != nil {
+		expense.Name = *update.Name
+	}
+	if update.Notes != nil {
+		expense.Notes = *update.Notes
+	}
+	if update.Date != nil {
+		expense.Date = *update.Date
+	}
+	if update.Amount != nil {
+		expense.Amount = *update.Amount
+	}
+	if update.CategoryID != nil && *update.CategoryID != expense.CategoryID {
+		if err := s.checkCategory(ctx, userID, expense.VaultID, *update.CategoryID); err != nil {
+			return nil, err
+		}
+		expense.CategoryID = *update.CategoryID
+	}
+	if update.PaymentMethodID != nil && *update.PaymentMethodID != expense.PaymentMethodID {
+		if err := s.checkPaymentMethod(ctx, userID, expense.VaultID, *update.PaymentMethodID); err != nil {
+			return nil, err
+		}
+		expense.PaymentMethodID = *update.PaymentMethodID
+	}
+	if update.AccountID != nil && *update.AccountID != expense.AccountID {
+		if err := s.checkAccount(ctx, userID, expense.VaultID, *update.AccountID); err != nil {
+			return nil, err
+		}
+		expense.AccountID = *update.AccountID
+	}
+	if update.TagIDs != nil {
+		if err := s.tagService.checkTags(ctx, expense.VaultID, *update.TagIDs); err != nil {
+			return nil, err
+		}
+		expense.TagIDs = *update.TagIDs
+	}
+	if update.PaidBy != nil {
+		expense.PaidBy = *update.PaidBy
+	}
+	switch {
+	case update.Split != nil:
+		expense.SplitMethod = update.Split.Method
+		expense.Splits, err = splits.Compute(expense.Amount, update.Split.Method, update.Split.Shares)
+		if err != nil {
+			return nil, err
+		}
+	case update.Amount != nil && len(expense.Splits) > 0:
+		expense.Splits, err = splits.Compute(expense.Amount, expense.SplitMethod, sharesOf(expense.Splits))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.checkSplit(ctx, userID, expense.VaultID, *expense); err != nil {
+		return nil, err
+	}
+
+	var updated *models.Expense
+	err = s.db.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.expenseRepo.UpdateOne(ctx, expense); err != nil {
+			return fmt.Errorf("failed to update expense %s as user %s: %w", expenseID, userID, err)
+		}
+
+		updated, err = s.expenseRepo.FindOneByID(ctx, expense.ID)
+		if err != nil {
+			return fmt.Errorf("failed to find expense %s after updating it: %w", expense.ID, err)
+		}
+
+		return recordAuditEvent(ctx, s.auditRepo, auditEvent(updated.VaultID, userID, models.AuditActionUpdate, models.AuditEntityExpense, updated.ID), before, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (s *ExpenseService) FindOneByID(ctx context.Context, userID, expenseID string) (*models.Expense, error) {
+	ctx, span := tracing.Start(ctx, "ExpenseService.FindOneByID")
+	defer span.End()
+
+	expense, err := s.expenseRepo.FindOneByID(ctx, expenseID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrExpenseNotFound) {
+			return nil, ErrExpenseNotFound
+		}
+		return nil, fmt.Errorf("failed to find expense %s: %w", expenseID, err)
+	}
+
+	vault, err := s.vaultService.FindOneByID(ctx, userID, expense.VaultID)
+	if err != nil {
+		if errors.Is(err, ErrVaultNotFound) {
+			return nil, ErrExpenseNotFound
+		}
+		return nil, err
+	}
+
+	if !permissions.Can(vault.UserRole, permissions.ActionRead) {
+		return nil, ErrInsufficientVaultPermissions
+	}
+
+	return expense, nil
+}
+
+// FindAll returns expenses of the vault. If tagIDs are given, only expenses tagged with all of them are returned.
+func (s *ExpenseService) FindAll(ctx context.Context, userID, vaultID string, tagIDs ...string) ([]models.Expense, error) {
+	ctx, span := tracing.Start(ctx, "ExpenseService.FindAll")
+	defer span.End()
+
+	vault, err := s.vaultService.FindOneByID(ctx, userID, vaultID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !permissions.Can(vault.UserRole, permissions.ActionRead) {
+		return nil, ErrInsufficientVaultPermissions
+	}
+
+	expenses, err := s.expenseRepo.FindAll(ctx, vault.ID, tagIDs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expenses for vault %s & user %s: %w", vault.ID, userID, err)
+	}
+
+	return expenses, nil
+}
+
+// Search finds expenses of the vault by name, notes and category name. Every word of
+// the query has to match, either fully or as a prefix of a longer word.
+func (s *ExpenseService) Search(ctx context.Context, userID, vaultID, query string, limit int) ([]models.ExpenseSearchResult, error) {
+	ctx, span := tracing.Start(ctx, "ExpenseService.Search")
+	defer span.End()
+
+	vault, err := s.vaultService.FindOneByID(ctx, userID, vaultID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !permissions.Can(vault.UserRole, permissions.ActionRead) {
+		return nil, ErrInsufficientVaultPermissions
+	}
+
+	match := searchMatchExpression(query)
+	if match == "" {
+		return []models.ExpenseSearchResult{}, nil
+	}
+
+	results, err := s.expenseRepo.Search(ctx, vault.ID, match, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search expenses in vault %s & user %s: %w", vault.ID, userID, err)
+	}
+
+	return results, nil
+}
+
+func (s *ExpenseService) DeleteOneByID(ctx context.Context, userID, expenseID string) error {
+	ctx, span := tracing.Start(ctx, "ExpenseService.DeleteOneByID")
+	defer span.End()
+
+	expense, err := s.expenseRepo.FindOneByID(ctx, expenseID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrExpenseNotFound) {
+			return ErrExpenseNotFound
+		}
+		return fmt.Errorf("failed to find expense %s: %w", expenseID, err)
+	}
+
+	if err := s.checkWritePermission(ctx, userID, expense.VaultID); err != nil {
+		return err
+	}
+
+	return s.db.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.expenseRepo.DeleteOneByID(ctx, expenseID); err != nil {
+			return fmt.Errorf("failed to delete expense %s as user %s: %w", expenseID, userID, err)
+		}
+		return recordAuditEvent(ctx, s.auditRepo, auditEvent(expense.VaultID, userID, models.AuditActionDelete, models.AuditEntityExpense, expense.ID), expense, nil)
+	})
+}
+
+func (s *ExpenseService) FindAllDeleted(ctx context.Context, userID, vaultID string, retention time.Duration) ([]models.Expense, error) {
+	ctx, span := tracing.Start(ctx, "ExpenseService.FindAllDeleted")
+	defer span.End()
+
+	vault, err := s.vaultService.FindOneByID(ctx, userID, vaultID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !permissions.Can(vault.UserRole, permissions.ActionRead) {
+		return nil, ErrInsufficientVaultPermissions
+	}
+
+	expenses, err := s.expenseRepo.FindAllDeleted(ctx, vault.ID, time.Now().Add(-retention))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find deleted expenses for vault %s & user %s: %w", vault.ID, userID, err)
+	}
+
+	return expenses, nil
+}
+
+func (s *ExpenseService) RestoreOneByID(ctx context.Context, userID, expenseID string, retention time.Duration) error {
+	ctx, span := tracing.Start(ctx, "ExpenseService.RestoreOneByID")
+	defer span.End()
+
+	expense, err := s.expenseRepo.FindOneDeletedByID(ctx, expenseID, time.Now().Add(-retention))
+	if err != nil {
+		if errors.Is(err, repositories.ErrExpenseNotFound) {
+			return ErrExpenseNotFound
+		}
+		return fmt.Errorf("failed to find deleted expense %s: %w", expenseID, err)
+	}
+
+	if err := s.checkWritePermission(ctx, userID, expense.VaultID); err != nil {
+		return err
+	}
+
+	before := *expense
+	expense.DeletedAt = ""
+
+	return s.db.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.expenseRepo.RestoreOneByID(ctx, expenseID); err != nil {
+			return fmt.Errorf("failed to restore expense %s as user %s: %w", expenseID, userID, err)
+		}
+		return recordAuditEvent(ctx, s.auditRepo, auditEvent(expense.VaultID, userID, models.AuditActionRestore, models.AuditEntityExpense, expense.ID), before, expense)
+	})
+}
+
+func (s *ExpenseService) checkWritePermission(ctx context.Context, userID, vaultID string) error {
+	vault, err := s.vaultService.FindOneByID(ctx, userID, vaultID)
+	if err != nil {
+		if errors.Is(err, ErrVaultNotFound) {
+			return ErrExpenseNotFound
+		}
+		return err
+	}
+
+	if !permissions.Can(vault.UserRole, permissions.ActionWriteExpenses) {
+		return ErrInsufficientVaultPermissions
+	}
+
+	return nil
+}
+
+func (s *ExpenseService) checkCategory(ctx context.Context, userID, vaultID, categoryID string) error {
+	category, err := s.expenseCategoryService.FindOneByID(ctx, userID, categoryID)
+	if err != nil {
+		return err
+	}
+	if category.VaultID != vaultID {
+		return ErrExpenseCategoryNotFound
+	}
+	if category.Status != models.ExpenseCategoryStatusActive {
+		return ErrExpenseCategoryInactive
+	}
+	return nil
+}
+
+func (s *ExpenseService) checkPaymentMethod(ctx context.Context, userID, vaultID, paymentMethodID string) error {
+	paymentMethod, err := s.paymentMethodService.FindOneByID(ctx, userID, paymentMethodID)
+	if err != nil {
+		return err
+	}
+	if paymentMethod.VaultID != vaultID {
+		return ErrPaymentMethodNotFound
+	}
+	if paymentMethod.Status != models.PaymentMethodStatusActive {
+		return ErrPaymentMethodArchived
+	}
+	return nil
+}
+
+func (s *ExpenseService) checkAccount(ctx context.Context, userID, vaultID, accountID string) error {
+	if accountID == "" {
+		return nil
+	}
+
+	account, err := s.accountService.FindOneByID(ctx, userID, accountID)
+	if err != nil {
+		return err
+	}
+	if account.VaultID != vaultID {
+		return ErrAccountNotFound
+	}
+	return nil
+}
+
+// checkSplit verifies that payer and all split participants are members of the vault,
+// and that split amounts add up to the expense amount and know how they were computed.
+func (s *ExpenseService) checkSplit(ctx context.Context, userID, vaultID string, expense models.Expense) error {
+	if expense.PaidBy == userID && len(expense.Splits) == 0 {
+		return nil
+	}
+
+	members, err := s.vaultService.FindMembers(ctx, userID, vaultID)
+	if err != nil {
+		return err
+	}
+
+	if !hasMember(members, expense.PaidBy) {
+		return ErrUserNotAssignedToVault
+	}
+
+	var total float64
+	for _, split := range expense.Splits {
+		if !hasMember(members, split.UserID) {
+			return ErrUserNotAssignedToVault
+		}
+		total += split.Amount
+	}
+	if len(expense.Splits) > 0 && expense.SplitMethod == "" {
+		return fmt.Errorf("%w: split method is required", splits.ErrInvalidSplit)
+	}
+	if len(expense.Splits) > 0 && math.Abs(total-expense.Amount) >= 0.005 {
+		return fmt.Errorf("%w: split amounts must add up to expense amount", splits.ErrInvalidSplit)
+	}
+
+	return nil
+}
+
+// sharesOf returns the shares that splits were computed from.
+func sharesOf(current []models.ExpenseSplit) []models.SplitShare {
+	shares := make([]models.SplitShare, len(current))
+	for i, split := range current {
+		shares[i] = models.SplitShare{UserID: split.UserID, Value: split.Share}
+	}
+	return shares
+}
+
+// searchMatchExpression turns free text into an FTS5 match expression of quoted prefix terms,
+// so that user input can never be interpreted as FTS5 query syntax.
+func searchMatchExpression(query string) string {
+	words := strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	if len(words) > maxSearchTerms {
+		words = words[:maxSearchTerms]
+	}
+
+	terms := make([]string, len(words))
+	for i, word := range words {
+		terms[i] = `"` + word + `"*`
+	}
+	return strings.Join(terms, " ")
+}
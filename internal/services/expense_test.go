@@ -0,0 +1,334 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kkstas/tr-backend/internal/database"
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestExpenseService_CreateOne(t *testing.T) {
+	t.Parallel()
+
+	t.Run("creates expense", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		expenseService := testutils.NewTestExpenseService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+
+		err := expenseService.CreateOne(ctx, user.ID, models.Expense{ // nolint: exhaustruct
+			Name:            "groceries",
+			Date:            "2025-02-01",
+			CategoryID:      category.ID,
+			Amount:          10,
+			PaymentMethodID: testutils.CreateTestPaymentMethod(t, db, user.ID, vault.ID).ID,
+			VaultID:         vault.ID,
+		})
+		testutils.AssertNoError(t, err)
+
+		expenses, err := expenseService.FindAll(ctx, user.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(expenses), 1)
+		testutils.AssertEqual(t, expenses[0].CreatedBy, user.ID)
+	})
+
+	t.Run("returns error if category belongs to another vault", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		expenseService := testutils.NewTestExpenseService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		_, otherUser, otherVault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, otherUser.ID, otherVault.ID)
+
+		err := expenseService.CreateOne(ctx, user.ID, models.Expense{ // nolint: exhaustruct
+			Name:            "groceries",
+			Date:            "2025-02-01",
+			CategoryID:      category.ID,
+			Amount:          10,
+			PaymentMethodID: testutils.CreateTestPaymentMethod(t, db, user.ID, vault.ID).ID,
+			VaultID:         vault.ID,
+		})
+		want := services.ErrExpenseCategoryNotFound
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+
+	t.Run("returns error if category is inactive", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		expenseService := testutils.NewTestExpenseService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+
+		status := models.ExpenseCategoryStatusInactive
+		_, err := testutils.NewTestExpenseCategoryService(db).UpdateOne(ctx, user.ID, category.ID, models.ExpenseCategoryUpdate{Status: &status}) // nolint: exhaustruct
+		testutils.AssertNoError(t, err)
+
+		err = expenseService.CreateOne(ctx, user.ID, models.Expense{ // nolint: exhaustruct
+			Name:            "groceries",
+			Date:            "2025-02-01",
+			CategoryID:      category.ID,
+			Amount:          10,
+			PaymentMethodID: testutils.CreateTestPaymentMethod(t, db, user.ID, vault.ID).ID,
+			VaultID:         vault.ID,
+		})
+		want := services.ErrExpenseCategoryInactive
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+
+	t.Run("returns error if payment method is archived", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		expenseService := testutils.NewTestExpenseService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		paymentMethod := testutils.CreateTestPaymentMethod(t, db, user.ID, vault.ID)
+
+		status := models.PaymentMethodStatusArchived
+		_, err := testutils.NewTestPaymentMethodService(db).UpdateOne(ctx, user.ID, paymentMethod.ID, models.PaymentMethodUpdate{Status: &status}) // nolint: exhaustruct
+		testutils.AssertNoError(t, err)
+
+		err = expenseService.CreateOne(ctx, user.ID, models.Expense{ // nolint: exhaustruct
+			Name:            "groceries",
+			Date:            "2025-02-01",
+			CategoryID:      category.ID,
+			Amount:          10,
+			PaymentMethodID: paymentMethod.ID,
+			VaultID:         vault.ID,
+		})
+		want := services.ErrPaymentMethodArchived
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+
+	t.Run("returns error if payment method belongs to another vault", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		expenseService := testutils.NewTestExpenseService(db)
+		vaultService := testutils.NewTestVaultService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+
+		err := vaultService.CreateOne(ctx, user.ID, "other vault", "", "")
+		testutils.AssertNoError(t, err)
+		vaults, err := vaultService.FindAll(ctx, user.ID)
+		testutils.AssertNoError(t, err)
+		otherVaultID := vaults[0].ID
+		if otherVaultID == vault.ID {
+			otherVaultID = vaults[1].ID
+		}
+		paymentMethod := testutils.CreateTestPaymentMethod(t, db, user.ID, otherVaultID)
+
+		err = expenseService.CreateOne(ctx, user.ID, models.Expense{ // nolint: exhaustruct
+			Name:            "groceries",
+			Date:            "2025-02-01",
+			CategoryID:      category.ID,
+			Amount:          10,
+			PaymentMethodID: paymentMethod.ID,
+			VaultID:         vault.ID,
+		})
+		want := services.ErrPaymentMethodNotFound
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+
+	t.Run("returns error if user cannot write expenses", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		expenseService := testutils.NewTestExpenseService(db)
+		_, owner, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		viewer := testutils.CreateTestUser(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, owner.ID, vault.ID)
+
+		err := testutils.NewTestVaultService(db).AddUser(ctx, owner.ID, viewer.ID, vault.ID, models.VaultRoleViewer)
+		testutils.AssertNoError(t, err)
+
+		err = expenseService.CreateOne(ctx, viewer.ID, models.Expense{ // nolint: exhaustruct
+			Name:            "groceries",
+			Date:            "2025-02-01",
+			CategoryID:      category.ID,
+			Amount:          10,
+			PaymentMethodID: testutils.CreateTestPaymentMethod(t, db, owner.ID, vault.ID).ID,
+			VaultID:         vault.ID,
+		})
+		want := services.ErrInsufficientVaultPermissions
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+}
+
+func TestExpenseService_DeleteOneByID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("moves expense to trash and restores it", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		expenseService := testutils.NewTestExpenseService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		err := expenseService.DeleteOneByID(ctx, user.ID, expense.ID)
+		testutils.AssertNoError(t, err)
+
+		deleted, err := expenseService.FindAllDeleted(ctx, user.ID, vault.ID, time.Hour)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(deleted), 1)
+
+		err = expenseService.RestoreOneByID(ctx, user.ID, expense.ID, time.Hour)
+		testutils.AssertNoError(t, err)
+
+		expenses, err := expenseService.FindAll(ctx, user.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(expenses), 1)
+	})
+
+	t.Run("returns error if user does not belong to expense's vault", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		expenseService := testutils.NewTestExpenseService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		otherUser := testutils.CreateTestUser(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		err := expenseService.DeleteOneByID(ctx, otherUser.ID, expense.ID)
+		want := services.ErrExpenseNotFound
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+}
+
+func TestExpenseService_Search(t *testing.T) {
+	t.Parallel()
+
+	newExpense := func(t *testing.T, db *database.DB, userID, vaultID, categoryID, name, notes string) {
+		t.Helper()
+		err := testutils.NewTestExpenseService(db).CreateOne(context.Background(), userID, models.Expense{ // nolint: exhaustruct
+			Name:            name,
+			Notes:           notes,
+			Date:            "2025-02-01",
+			CategoryID:      categoryID,
+			Amount:          10,
+			PaymentMethodID: testutils.CreateTestPaymentMethod(t, db, userID, vaultID).ID,
+			VaultID:         vaultID,
+		})
+		testutils.AssertNoError(t, err)
+	}
+
+	t.Run("ranks name matches above notes matches and highlights terms", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		expenseService := testutils.NewTestExpenseService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+
+		newExpense(t, db, user.ID, vault.ID, category.ID, "dinner", "pizza with friends after the concert")
+		newExpense(t, db, user.ID, vault.ID, category.ID, "pizza", "")
+		newExpense(t, db, user.ID, vault.ID, category.ID, "bus ticket", "")
+
+		results, err := expenseService.Search(ctx, user.ID, vault.ID, "piz", 20)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(results), 2)
+		testutils.AssertEqual(t, results[0].Name, "pizza")
+		testutils.AssertEqual(t, results[0].Highlights.Name, "<mark>pizza</mark>")
+		testutils.AssertEqual(t, results[1].Name, "dinner")
+		testutils.AssertEqual(t, results[1].Highlights.Notes, "<mark>pizza</mark> with friends after the concert")
+	})
+
+	t.Run("finds expenses by category name after category is renamed", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		expenseService := testutils.NewTestExpenseService(db)
+		categoryService := testutils.NewTestExpenseCategoryService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		newExpense(t, db, user.ID, vault.ID, category.ID, "weekly shopping", "")
+
+		name := "Groceries"
+		_, err := categoryService.UpdateOne(ctx, user.ID, category.ID, models.ExpenseCategoryUpdate{Name: &name}) // nolint: exhaustruct
+		testutils.AssertNoError(t, err)
+
+		results, err := expenseService.Search(ctx, user.ID, vault.ID, "grocer", 20)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(results), 1)
+		testutils.AssertEqual(t, results[0].Highlights.Category, "<mark>Groceries</mark>")
+	})
+
+	t.Run("keeps index in sync with updated and deleted expenses", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		expenseService := testutils.NewTestExpenseService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		notes := "refundable deposit"
+		_, err := expenseService.UpdateOne(ctx, user.ID, expense.ID, models.ExpenseUpdate{Notes: &notes}) // nolint: exhaustruct
+		testutils.AssertNoError(t, err)
+
+		results, err := expenseService.Search(ctx, user.ID, vault.ID, "deposit", 20)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(results), 1)
+
+		err = expenseService.DeleteOneByID(ctx, user.ID, expense.ID)
+		testutils.AssertNoError(t, err)
+
+		results, err = expenseService.Search(ctx, user.ID, vault.ID, "deposit", 20)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(results), 0)
+	})
+
+	t.Run("does not return expenses of other vaults", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		expenseService := testutils.NewTestExpenseService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		_, otherUser, otherVault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		newExpense(t, db, otherUser.ID, otherVault.ID, testutils.CreateTestExpenseCategory(t, db, otherUser.ID, otherVault.ID).ID, "pizza", "")
+
+		results, err := expenseService.Search(ctx, user.ID, vault.ID, "pizza", 20)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(results), 0)
+	})
+
+	t.Run("treats FTS query syntax as plain text", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		expenseService := testutils.NewTestExpenseService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		newExpense(t, db, user.ID, vault.ID, category.ID, "taxi OR train", "")
+
+		for _, query := range []string{`"taxi`, `taxi AND NOT`, `name:taxi*`, `(train`, `"""`} {
+			_, err := expenseService.Search(ctx, user.ID, vault.ID, query, 20)
+			testutils.AssertNoError(t, err)
+		}
+	})
+}
@@ -5,33 +5,49 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/kkstas/tr-backend/internal/categorytemplates"
+	"github.com/kkstas/tr-backend/internal/database"
 	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/permissions"
 	"github.com/kkstas/tr-backend/internal/repositories"
+	"github.com/kkstas/tr-backend/internal/tracing"
 )
 
 var ErrExpenseCategoryWithThatNameAlreadyExists = errors.New("expense category with that name already exists")
-var ErrUserIsNotVaultOwner = errors.New("user is not vault owner")
+var ErrExpenseCategoryNotFound = errors.New("expense category not found")
+var ErrInvalidExpenseCategoryOrder = errors.New("category order must contain every category of the vault exactly once")
+var ErrCannotMergeExpenseCategoryIntoItself = errors.New("cannot merge expense category into itself")
+var ErrInvalidExpenseCategoryParent = errors.New("parent expense category not found in this vault")
+var ErrExpenseCategoryCycle = errors.New("expense category cannot be moved under itself or its subcategory")
+var ErrCategoryTemplateNotFound = errors.New("category template not found")
 
 type ExpenseCategoryService struct {
+	db                  *database.DB
 	expenseCategoryRepo *repositories.ExpenseCategoryRepo
+	auditRepo           *repositories.AuditRepo
 	vaultService        *VaultService
 }
 
-func NewExpenseCategoryService(expenseCategoryRepo *repositories.ExpenseCategoryRepo, vaultService *VaultService) *ExpenseCategoryService {
+func NewExpenseCategoryService(db *database.DB, expenseCategoryRepo *repositories.ExpenseCategoryRepo, auditRepo *repositories.AuditRepo, vaultService *VaultService) *ExpenseCategoryService {
 	return &ExpenseCategoryService{
+		db:                  db,
 		expenseCategoryRepo: expenseCategoryRepo,
+		auditRepo:           auditRepo,
 		vaultService:        vaultService,
 	}
 }
 
-func (s *ExpenseCategoryService) CreateOne(ctx context.Context, name string, userID, vaultID string) error {
+func (s *ExpenseCategoryService) CreateOne(ctx context.Context, name string, userID, vaultID, parentID string) error {
+	ctx, span := tracing.Start(ctx, "ExpenseCategoryService.CreateOne")
+	defer span.End()
+
 	userVaultWithRole, err := s.vaultService.FindOneByID(ctx, userID, vaultID)
 	if err != nil {
 		return fmt.Errorf("failed to find vault %s for user %s: %w", vaultID, userID, err)
 	}
 
-	if userVaultWithRole.UserRole != models.VaultRoleOwner {
-		return ErrUserIsNotVaultOwner
+	if !permissions.Can(userVaultWithRole.UserRole, permissions.ActionManageCategories) {
+		return ErrInsufficientVaultPermissions
 	}
 
 	categories, err := s.expenseCategoryRepo.FindAll(ctx, userVaultWithRole.ID)
@@ -39,31 +55,46 @@ func (s *ExpenseCategoryService) CreateOne(ctx context.Context, name string, use
 		return fmt.Errorf("failed to find existing expense categories in vault %s before creating one: %w", userVaultWithRole.ID, err)
 	}
 
-	exists := false
-	for _, category := range categories {
-		if category.Name == name {
-			exists = true
-			break
-		}
-	}
-	if exists {
+	if categoryNameTaken(categories, name, "") {
 		return ErrExpenseCategoryWithThatNameAlreadyExists
 	}
 
-	_, err = s.expenseCategoryRepo.CreateOne(ctx, name, models.ExpenseCategoryStatusActive, 0, userVaultWithRole.ID, userID)
-	if err != nil {
-		return fmt.Errorf("failed to create expense category: %w", err)
+	var parent *string
+	if parentID != "" {
+		if findCategory(categories, parentID) == nil {
+			return ErrInvalidExpenseCategoryParent
+		}
+		parent = &parentID
 	}
 
-	return nil
+	return s.db.WithTx(ctx, func(ctx context.Context) error {
+		categoryID, err := s.expenseCategoryRepo.CreateOne(ctx, name, models.ExpenseCategoryStatusActive, nextPriority(categories), parent, userVaultWithRole.ID, userID)
+		if err != nil {
+			return fmt.Errorf("failed to create expense category: %w", err)
+		}
+
+		category, err := s.expenseCategoryRepo.FindOneByID(ctx, categoryID)
+		if err != nil {
+			return fmt.Errorf("failed to find expense category %s after creating it: %w", categoryID, err)
+		}
+
+		return recordAuditEvent(ctx, s.auditRepo, auditEvent(category.VaultID, userID, models.AuditActionCreate, models.AuditEntityExpenseCategory, category.ID), nil, category)
+	})
 }
 
 func (s *ExpenseCategoryService) FindAll(ctx context.Context, userID, vaultID string) ([]models.ExpenseCategory, error) {
+	ctx, span := tracing.Start(ctx, "ExpenseCategoryService.FindAll")
+	defer span.End()
+
 	vault, err := s.vaultService.FindOneByID(ctx, userID, vaultID)
 	if err != nil {
 		return nil, err
 	}
 
+	if !permissions.Can(vault.UserRole, permissions.ActionRead) {
+		return nil, ErrInsufficientVaultPermissions
+	}
+
 	categories, err := s.expenseCategoryRepo.FindAll(ctx, vault.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find expense categories for vault %s & user %s: %w", vault.ID, userID, err)
@@ -71,3 +102,322 @@ func (s *ExpenseCategoryService) FindAll(ctx context.Context, userID, vaultID st
 
 	return categories, nil
 }
+
+func (s *ExpenseCategoryService) FindTree(ctx context.Context, userID, vaultID string) ([]models.ExpenseCategoryTreeNode, error) {
+	ctx, span := tracing.Start(ctx, "ExpenseCategoryService.FindTree")
+	defer span.End()
+
+	categories, err := s.FindAll(ctx, userID, vaultID)
+	if err != nil {
+		return nil, err
+	}
+	return buildCategoryTree(categories, nil), nil
+}
+
+func (s *ExpenseCategoryService) FindOneByID(ctx context.Context, userID, categoryID string) (*models.ExpenseCategory, error) {
+	ctx, span := tracing.Start(ctx, "ExpenseCategoryService.FindOneByID")
+	defer span.End()
+
+	category, err := s.expenseCategoryRepo.FindOneByID(ctx, categoryID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrExpenseCategoryNotFound) {
+			return nil, ErrExpenseCategoryNotFound
+		}
+		return nil, fmt.Errorf("failed to find expense category %s: %w", categoryID, err)
+	}
+
+	_, err = s.vaultService.FindOneByID(ctx, userID, category.VaultID)
+	if err != nil {
+		if errors.Is(err, ErrVaultNotFound) {
+			return nil, ErrExpenseCategoryNotFound
+		}
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (s *ExpenseCategoryService) UpdateOne(ctx context.Context, userID, categoryID string, update models.ExpenseCategoryUpdate) (*models.ExpenseCategory, error) {
+	ctx, span := tracing.Start(ctx, "ExpenseCategoryService.UpdateOne")
+	defer span.End()
+
+	category, err := s.findOneForManagement(ctx, userID, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.expenseCategoryRepo.FindAll(ctx, category.VaultID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find existing expense categories in vault %s before updating one: %w", category.VaultID, err)
+	}
+
+	before := *category
+
+	if update.Name != nil && *update.Name != category.Name {
+		if categoryNameTaken(categories, *update.Name, category.ID) {
+			return nil, ErrExpenseCategoryWithThatNameAlreadyExists
+		}
+		category.Name = *update.Name
+	}
+	if update.ParentID != nil {
+		if *update.ParentID == "" {
+			category.ParentID = nil
+		} else {
+			if findCategory(categories, *update.ParentID) == nil {
+				return nil, ErrInvalidExpenseCategoryParent
+			}
+			if isDescendantOrSelf(categories, *update.ParentID, category.ID) {
+				return nil, ErrExpenseCategoryCycle
+			}
+			parentID := *update.ParentID
+			category.ParentID = &parentID
+		}
+	}
+	if update.Status != nil {
+		category.Status = *update.Status
+	}
+
+	err = s.db.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.expenseCategoryRepo.UpdateOne(ctx, category); err != nil {
+			return fmt.Errorf("failed to update expense category %s as user %s: %w", categoryID, userID, err)
+		}
+		return recordAuditEvent(ctx, s.auditRepo, auditEvent(category.VaultID, userID, models.AuditActionUpdate, models.AuditEntityExpenseCategory, category.ID), before, category)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (s *ExpenseCategoryService) Reorder(ctx context.Context, userID, vaultID string, categoryIDs []string) error {
+	ctx, span := tracing.Start(ctx, "ExpenseCategoryService.Reorder")
+	defer span.End()
+
+	vault, err := s.vaultService.FindOneByID(ctx, userID, vaultID)
+	if err != nil {
+		return err
+	}
+
+	if !permissions.Can(vault.UserRole, permissions.ActionManageCategories) {
+		return ErrInsufficientVaultPermissions
+	}
+
+	categories, err := s.expenseCategoryRepo.FindAll(ctx, vault.ID)
+	if err != nil {
+		return fmt.Errorf("failed to find expense categories in vault %s before reordering: %w", vault.ID, err)
+	}
+
+	if len(categories) != len(categoryIDs) {
+		return ErrInvalidExpenseCategoryOrder
+	}
+	remaining := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		remaining[category.ID] = true
+	}
+	for _, categoryID := range categoryIDs {
+		if !remaining[categoryID] {
+			return ErrInvalidExpenseCategoryOrder
+		}
+		delete(remaining, categoryID)
+	}
+
+	return s.db.WithTx(ctx, func(ctx context.Context) error {
+		err := s.expenseCategoryRepo.Reorder(ctx, vault.ID, categoryIDs)
+		if err != nil {
+			return fmt.Errorf("failed to reorder expense categories in vault %s: %w", vault.ID, err)
+		}
+
+		for priority, categoryID := range categoryIDs {
+			before := findCategory(categories, categoryID)
+			if before.Priority == priority {
+				continue
+			}
+			after := *before
+			after.Priority = priority
+			err := recordAuditEvent(ctx, s.auditRepo, auditEvent(vault.ID, userID, models.AuditActionUpdate, models.AuditEntityExpenseCategory, categoryID), before, after)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *ExpenseCategoryService) Merge(ctx context.Context, userID, sourceCategoryID, targetCategoryID string) error {
+	ctx, span := tracing.Start(ctx, "ExpenseCategoryService.Merge")
+	defer span.End()
+
+	if sourceCategoryID == targetCategoryID {
+		return ErrCannotMergeExpenseCategoryIntoItself
+	}
+
+	source, err := s.findOneForManagement(ctx, userID, sourceCategoryID)
+	if err != nil {
+		return err
+	}
+
+	target, err := s.FindOneByID(ctx, userID, targetCategoryID)
+	if err != nil {
+		return err
+	}
+	if target.VaultID != source.VaultID {
+		return ErrExpenseCategoryNotFound
+	}
+
+	return s.db.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.expenseCategoryRepo.Merge(ctx, source.ID, target.ID); err != nil {
+			return fmt.Errorf("failed to merge expense category %s into %s: %w", source.ID, target.ID, err)
+		}
+		return recordAuditEvent(ctx, s.auditRepo, auditEvent(source.VaultID, userID, models.AuditActionDelete, models.AuditEntityExpenseCategory, source.ID), source, nil)
+	})
+}
+
+func (s *ExpenseCategoryService) ApplyTemplate(ctx context.Context, userID, vaultID, templateName, locale string) error {
+	ctx, span := tracing.Start(ctx, "ExpenseCategoryService.ApplyTemplate")
+	defer span.End()
+
+	template, err := findCategoryTemplate(templateName, locale)
+	if err != nil {
+		return err
+	}
+
+	vault, err := s.vaultService.FindOneByID(ctx, userID, vaultID)
+	if err != nil {
+		return err
+	}
+
+	if !permissions.Can(vault.UserRole, permissions.ActionManageCategories) {
+		return ErrInsufficientVaultPermissions
+	}
+
+	existing, err := s.expenseCategoryRepo.FindAll(ctx, vault.ID)
+	if err != nil {
+		return fmt.Errorf("failed to find expense categories in vault %s before applying template: %w", vault.ID, err)
+	}
+
+	return s.db.WithTx(ctx, func(ctx context.Context) error {
+		err := s.expenseCategoryRepo.CreateFromTemplate(ctx, vault.ID, userID, template.Categories)
+		if err != nil {
+			return fmt.Errorf("failed to apply category template %q to vault %s: %w", templateName, vault.ID, err)
+		}
+
+		categories, err := s.expenseCategoryRepo.FindAll(ctx, vault.ID)
+		if err != nil {
+			return fmt.Errorf("failed to find expense categories in vault %s after applying template: %w", vault.ID, err)
+		}
+
+		for _, category := range categories {
+			if findCategory(existing, category.ID) != nil {
+				continue
+			}
+			err := recordAuditEvent(ctx, s.auditRepo, auditEvent(vault.ID, userID, models.AuditActionCreate, models.AuditEntityExpenseCategory, category.ID), nil, category)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *ExpenseCategoryService) findOneForManagement(ctx context.Context, userID, categoryID string) (*models.ExpenseCategory, error) {
+	category, err := s.expenseCategoryRepo.FindOneByID(ctx, categoryID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrExpenseCategoryNotFound) {
+			return nil, ErrExpenseCategoryNotFound
+		}
+		return nil, fmt.Errorf("failed to find expense category %s: %w", categoryID, err)
+	}
+
+	vault, err := s.vaultService.FindOneByID(ctx, userID, category.VaultID)
+	if err != nil {
+		if errors.Is(err, ErrVaultNotFound) {
+			return nil, ErrExpenseCategoryNotFound
+		}
+		return nil, err
+	}
+
+	if !permissions.Can(vault.UserRole, permissions.ActionManageCategories) {
+		return nil, ErrInsufficientVaultPermissions
+	}
+
+	return category, nil
+}
+
+func findCategoryTemplate(templateName, locale string) (*categorytemplates.Template, error) {
+	template, err := categorytemplates.Find(templateName, locale)
+	if err != nil {
+		if errors.Is(err, categorytemplates.ErrTemplateNotFound) {
+			return nil, ErrCategoryTemplateNotFound
+		}
+		return nil, err
+	}
+	return template, nil
+}
+
+func findCategory(categories []models.ExpenseCategory, categoryID string) *models.ExpenseCategory {
+	for i := range categories {
+		if categories[i].ID == categoryID {
+			return &categories[i]
+		}
+	}
+	return nil
+}
+
+// isDescendantOrSelf walks up the parent chain of categoryID and reports whether ancestorID is on it.
+func isDescendantOrSelf(categories []models.ExpenseCategory, categoryID, ancestorID string) bool {
+	visited := make(map[string]bool, len(categories))
+	for current := findCategory(categories, categoryID); current != nil && !visited[current.ID]; {
+		if current.ID == ancestorID {
+			return true
+		}
+		visited[current.ID] = true
+		if current.ParentID == nil {
+			return false
+		}
+		current = findCategory(categories, *current.ParentID)
+	}
+	return false
+}
+
+func buildCategoryTree(categories []models.ExpenseCategory, parentID *string) []models.ExpenseCategoryTreeNode {
+	nodes := []models.ExpenseCategoryTreeNode{}
+	for _, category := range categories {
+		if !sameParent(category.ParentID, parentID) {
+			continue
+		}
+		nodes = append(nodes, models.ExpenseCategoryTreeNode{
+			ExpenseCategory: category,
+			Children:        buildCategoryTree(categories, &category.ID),
+		})
+	}
+	return nodes
+}
+
+func sameParent(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func categoryNameTaken(categories []models.ExpenseCategory, name, exceptID string) bool {
+	for _, category := range categories {
+		if category.Name == name && category.ID != exceptID {
+			return true
+		}
+	}
+	return false
+}
+
+func nextPriority(categories []models.ExpenseCategory) int {
+	priority := 0
+	for _, category := range categories {
+		if category.Priority >= priority {
+			priority = category.Priority + 1
+		}
+	}
+	return priority
+}
@@ -20,7 +20,7 @@ func TestExpenseCategoryService_CreateOne(t *testing.T) {
 		expenseCategoryService := testutils.NewTestExpenseCategoryService(db)
 		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
 
-		err := expenseCategoryService.CreateOne(ctx, "category name", user.ID, vault.ID)
+		err := expenseCategoryService.CreateOne(ctx, "category name", user.ID, vault.ID, "")
 		if err != nil {
 			t.Errorf("didn't expect an error, but got one: %v", err)
 		}
@@ -35,13 +35,13 @@ func TestExpenseCategoryService_CreateOne(t *testing.T) {
 		_, _, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
 		user := testutils.CreateTestUser(t, db)
 
-		err := expenseCategoryService.CreateOne(ctx, "category name", user.ID, vault.ID)
+		err := expenseCategoryService.CreateOne(ctx, "category name", user.ID, vault.ID, "")
 		if err == nil {
 			t.Error("expected an error but didn't get one")
 		}
 	})
 
-	t.Run("returns error if user is related to that vault but is not an owner", func(t *testing.T) {
+	t.Run("returns error if user is related to that vault but cannot manage categories", func(t *testing.T) {
 		t.Parallel()
 		ctx := context.Background()
 		db := testutils.OpenTestDB(t, ctx)
@@ -54,12 +54,12 @@ func TestExpenseCategoryService_CreateOne(t *testing.T) {
 		err := vaultService.AddUser(ctx, vaultOwner.ID, user.ID, vault.ID, models.VaultRoleEditor)
 		testutils.AssertNoError(t, err)
 
-		err = expenseCategoryService.CreateOne(ctx, "category name", user.ID, vault.ID)
+		err = expenseCategoryService.CreateOne(ctx, "category name", user.ID, vault.ID, "")
 		if err == nil {
 			t.Error("expected an error but didn't get one")
 		}
-		want := services.ErrUserIsNotVaultOwner
-		if !errors.Is(err, services.ErrUserIsNotVaultOwner) {
+		want := services.ErrInsufficientVaultPermissions
+		if !errors.Is(err, want) {
 			t.Errorf("expected %q, got %v", want, err)
 		}
 	})
@@ -71,10 +71,10 @@ func TestExpenseCategoryService_CreateOne(t *testing.T) {
 		expenseCategoryService := testutils.NewTestExpenseCategoryService(db)
 		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
 
-		err := expenseCategoryService.CreateOne(ctx, "category name", user.ID, vault.ID)
+		err := expenseCategoryService.CreateOne(ctx, "category name", user.ID, vault.ID, "")
 		testutils.AssertNoError(t, err)
 
-		err = expenseCategoryService.CreateOne(ctx, "category name", user.ID, vault.ID)
+		err = expenseCategoryService.CreateOne(ctx, "category name", user.ID, vault.ID, "")
 
 		if err == nil {
 			t.Error("expected an error but didn't get one")
@@ -97,9 +97,9 @@ func TestExpenseCategoryService_FindAll(t *testing.T) {
 		expenseCategoryService := testutils.NewTestExpenseCategoryService(db)
 		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
 
-		err := expenseCategoryService.CreateOne(ctx, "category one", user.ID, vault.ID)
+		err := expenseCategoryService.CreateOne(ctx, "category one", user.ID, vault.ID, "")
 		testutils.AssertNoError(t, err)
-		err = expenseCategoryService.CreateOne(ctx, "category two", user.ID, vault.ID)
+		err = expenseCategoryService.CreateOne(ctx, "category two", user.ID, vault.ID, "")
 		testutils.AssertNoError(t, err)
 
 		foundCategories, err := expenseCategoryService.FindAll(ctx, user.ID, vault.ID)
@@ -107,11 +107,11 @@ func TestExpenseCategoryService_FindAll(t *testing.T) {
 
 		testutils.AssertEqual(t, len(foundCategories), 2)
 
-		for _, category := range foundCategories {
+		for i, category := range foundCategories {
 			testutils.AssertEqual(t, category.CreatedBy, user.ID)
 			testutils.AssertEqual(t, category.VaultID, vault.ID)
 			testutils.AssertEqual(t, category.Status, models.ExpenseCategoryStatusActive)
-			testutils.AssertEqual(t, category.Priority, 0)
+			testutils.AssertEqual(t, category.Priority, i)
 		}
 	})
 
@@ -124,7 +124,7 @@ func TestExpenseCategoryService_FindAll(t *testing.T) {
 		_, vaultOwner, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
 		user := testutils.CreateTestUser(t, db)
 
-		err := expenseCategoryService.CreateOne(ctx, "category name", vaultOwner.ID, vault.ID)
+		err := expenseCategoryService.CreateOne(ctx, "category name", vaultOwner.ID, vault.ID, "")
 		testutils.AssertNoError(t, err)
 
 		_, err = expenseCategoryService.FindAll(ctx, user.ID, vault.ID)
@@ -133,3 +133,259 @@ func TestExpenseCategoryService_FindAll(t *testing.T) {
 		}
 	})
 }
+
+func TestExpenseCategoryService_UpdateOne(t *testing.T) {
+	t.Parallel()
+
+	t.Run("renames and deactivates expense category", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		expenseCategoryService := testutils.NewTestExpenseCategoryService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+
+		name := "renamed"
+		status := models.ExpenseCategoryStatusInactive
+		updated, err := expenseCategoryService.UpdateOne(ctx, user.ID, category.ID, models.ExpenseCategoryUpdate{Name: &name, Status: &status})
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, updated.Name, name)
+		testutils.AssertEqual(t, updated.Status, status)
+	})
+
+	t.Run("returns error when another category in the vault has the new name", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		expenseCategoryService := testutils.NewTestExpenseCategoryService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		other := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+
+		_, err := expenseCategoryService.UpdateOne(ctx, user.ID, category.ID, models.ExpenseCategoryUpdate{Name: &other.Name}) // nolint: exhaustruct
+		want := services.ErrExpenseCategoryWithThatNameAlreadyExists
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+}
+
+func TestExpenseCategoryService_Reorder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns error if order does not contain every category exactly once", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		expenseCategoryService := testutils.NewTestExpenseCategoryService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		first := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+
+		err := expenseCategoryService.Reorder(ctx, user.ID, vault.ID, []string{first.ID, first.ID})
+		want := services.ErrInvalidExpenseCategoryOrder
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+}
+
+func TestExpenseCategoryService_Merge(t *testing.T) {
+	t.Parallel()
+
+	t.Run("merges category into another one", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		expenseCategoryService := testutils.NewTestExpenseCategoryService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		source := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		target := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+
+		err := expenseCategoryService.Merge(ctx, user.ID, source.ID, target.ID)
+		testutils.AssertNoError(t, err)
+
+		categories, err := expenseCategoryService.FindAll(ctx, user.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(categories), 1)
+		testutils.AssertEqual(t, categories[0].ID, target.ID)
+	})
+
+	t.Run("returns error if target category belongs to another vault", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		expenseCategoryService := testutils.NewTestExpenseCategoryService(db)
+		vaultService := testutils.NewTestVaultService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		source := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+
+		err := vaultService.CreateOne(ctx, user.ID, "other vault", "", "")
+		testutils.AssertNoError(t, err)
+		otherVaults, err := vaultService.FindAll(ctx, user.ID)
+		testutils.AssertNoError(t, err)
+		otherVaultID := otherVaults[0].ID
+		if otherVaultID == vault.ID {
+			otherVaultID = otherVaults[1].ID
+		}
+		target := testutils.CreateTestExpenseCategory(t, db, user.ID, otherVaultID)
+
+		err = expenseCategoryService.Merge(ctx, user.ID, source.ID, target.ID)
+		want := services.ErrExpenseCategoryNotFound
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+}
+
+func TestExpenseCategoryService_Hierarchy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("creates subcategory and returns it in tree under its parent", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		expenseCategoryService := testutils.NewTestExpenseCategoryService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		parent := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+
+		err := expenseCategoryService.CreateOne(ctx, "groceries", user.ID, vault.ID, parent.ID)
+		testutils.AssertNoError(t, err)
+
+		tree, err := expenseCategoryService.FindTree(ctx, user.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(tree), 1)
+		testutils.AssertEqual(t, tree[0].ID, parent.ID)
+		testutils.AssertEqual(t, len(tree[0].Children), 1)
+		testutils.AssertEqual(t, tree[0].Children[0].Name, "groceries")
+		testutils.AssertEqual(t, *tree[0].Children[0].ParentID, parent.ID)
+	})
+
+	t.Run("returns error when parent belongs to another vault", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		expenseCategoryService := testutils.NewTestExpenseCategoryService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		_, otherUser, otherVault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		foreignParent := testutils.CreateTestExpenseCategory(t, db, otherUser.ID, otherVault.ID)
+
+		err := expenseCategoryService.CreateOne(ctx, "groceries", user.ID, vault.ID, foreignParent.ID)
+		want := services.ErrInvalidExpenseCategoryParent
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+
+	t.Run("prevents moving category under its own subcategory", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		expenseCategoryService := testutils.NewTestExpenseCategoryService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		grandparent := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		parent := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		child := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+
+		_, err := expenseCategoryService.UpdateOne(ctx, user.ID, parent.ID, models.ExpenseCategoryUpdate{ParentID: &grandparent.ID}) // nolint: exhaustruct
+		testutils.AssertNoError(t, err)
+		_, err = expenseCategoryService.UpdateOne(ctx, user.ID, child.ID, models.ExpenseCategoryUpdate{ParentID: &parent.ID}) // nolint: exhaustruct
+		testutils.AssertNoError(t, err)
+
+		want := services.ErrExpenseCategoryCycle
+		for _, newParentID := range []string{grandparent.ID, child.ID} {
+			_, err = expenseCategoryService.UpdateOne(ctx, user.ID, grandparent.ID, models.ExpenseCategoryUpdate{ParentID: &newParentID}) // nolint: exhaustruct
+			if !errors.Is(err, want) {
+				t.Errorf("expected error %q when moving under %s, got %v", want, newParentID, err)
+			}
+		}
+	})
+
+	t.Run("moves category back to top level with empty parent", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		expenseCategoryService := testutils.NewTestExpenseCategoryService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		parent := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		child := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+
+		_, err := expenseCategoryService.UpdateOne(ctx, user.ID, child.ID, models.ExpenseCategoryUpdate{ParentID: &parent.ID}) // nolint: exhaustruct
+		testutils.AssertNoError(t, err)
+
+		noParent := ""
+		updated, err := expenseCategoryService.UpdateOne(ctx, user.ID, child.ID, models.ExpenseCategoryUpdate{ParentID: &noParent}) // nolint: exhaustruct
+		testutils.AssertNoError(t, err)
+		if updated.ParentID != nil {
+			t.Errorf("expected no parent, got %q", *updated.ParentID)
+		}
+	})
+
+	t.Run("merging category lifts its subcategories to its parent", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		expenseCategoryService := testutils.NewTestExpenseCategoryService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		source := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		target := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+
+		_, err := expenseCategoryService.UpdateOne(ctx, user.ID, target.ID, models.ExpenseCategoryUpdate{ParentID: &source.ID}) // nolint: exhaustruct
+		testutils.AssertNoError(t, err)
+
+		err = expenseCategoryService.Merge(ctx, user.ID, source.ID, target.ID)
+		testutils.AssertNoError(t, err)
+
+		merged, err := expenseCategoryService.FindOneByID(ctx, user.ID, target.ID)
+		testutils.AssertNoError(t, err)
+		if merged.ParentID != nil {
+			t.Errorf("expected no parent, got %q", *merged.ParentID)
+		}
+	})
+}
+
+func TestExpenseCategoryService_ApplyTemplate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("applying template twice does not duplicate categories", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		expenseCategoryService := testutils.NewTestExpenseCategoryService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		existing := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+
+		err := expenseCategoryService.ApplyTemplate(ctx, user.ID, vault.ID, "travel", "en")
+		testutils.AssertNoError(t, err)
+		categories, err := expenseCategoryService.FindAll(ctx, user.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+
+		err = expenseCategoryService.ApplyTemplate(ctx, user.ID, vault.ID, "travel", "en")
+		testutils.AssertNoError(t, err)
+		categoriesAfterSecondApply, err := expenseCategoryService.FindAll(ctx, user.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+
+		testutils.AssertEqual(t, len(categoriesAfterSecondApply), len(categories))
+		testutils.AssertEqual(t, categories[0].ID, existing.ID)
+		for i, category := range categories {
+			testutils.AssertEqual(t, category.Priority, i)
+		}
+	})
+
+	t.Run("returns error if user cannot manage categories", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		expenseCategoryService := testutils.NewTestExpenseCategoryService(db)
+		_, owner, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		editor := testutils.CreateTestUser(t, db)
+		err := testutils.NewTestVaultService(db).AddUser(ctx, owner.ID, editor.ID, vault.ID, models.VaultRoleEditor)
+		testutils.AssertNoError(t, err)
+
+		err = expenseCategoryService.ApplyTemplate(ctx, editor.ID, vault.ID, "travel", "en")
+		want := services.ErrInsufficientVaultPermissions
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+}
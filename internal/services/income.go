@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/permissions"
+	"github.com/kkstas/tr-backend/internal/repositories"
+	"github.com/kkstas/tr-backend/internal/tracing"
+)
+
+var ErrIncomeNotFound = errors.New("income not found")
+
+type IncomeService struct {
+	incomeRepo     *repositories.IncomeRepo
+	vaultService   *VaultService
+	accountService *AccountService
+}
+
+func NewIncomeService(incomeRepo *repositories.IncomeRepo, vaultService *VaultService, accountService *AccountService) *IncomeService {
+	return &IncomeService{
+		incomeRepo:     incomeRepo,
+		vaultService:   vaultService,
+		accountService: accountService,
+	}
+}
+
+func (s *IncomeService) CreateOne(ctx context.Context, userID string, income models.Income) error {
+	ctx, span := tracing.Start(ctx, "IncomeService.CreateOne")
+	defer span.End()
+
+	vault, err := s.vaultService.FindOneByID(ctx, userID, income.VaultID)
+	if err != nil {
+		return err
+	}
+
+	if !permissions.Can(vault.UserRole, permissions.ActionWriteExpenses) {
+		return ErrInsufficientVaultPermissions
+	}
+
+	if err := s.checkAccount(ctx, userID, vault.ID, income.AccountID); err != nil {
+		return err
+	}
+
+	income.CreatedBy = userID
+
+	_, err = s.incomeRepo.CreateOne(ctx, income)
+	if err != nil {
+		return fmt.Errorf("failed to create income in vault %s: %w", vault.ID, err)
+	}
+
+	return nil
+}
+
+func (s *IncomeService) FindAll(ctx context.Context, userID, vaultID string) ([]models.Income, error) {
+	ctx, span := tracing.Start(ctx, "IncomeService.FindAll")
+	defer span.End()
+
+	vault, err := s.vaultService.FindOneByID(ctx, userID, vaultID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !permissions.Can(vault.UserRole, permissions.ActionRead) {
+		return nil, ErrInsufficientVaultPermissions
+	}
+
+	incomes, err := s.incomeRepo.FindAll(ctx, vault.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find incomes for vault %s & user %s: %w", vault.ID, userID, err)
+	}
+
+	return incomes, nil
+}
+
+func (s *IncomeService) UpdateOne(ctx context.Context, userID, incomeID string, update models.IncomeUpdate) (*models.Income, error) {
+	ctx, span := tracing.Start(ctx, "IncomeService.UpdateOne")
+	defer span.End()
+
+	income, err := s.findOneForWriting(ctx, userID, incomeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Source != nil {
+		income.Source = *update.Source
+	}
+	if update.Date != nil {
+		income.Date = *update.Date
+	}
+	if update.Amount != nil {
+		income.Amount = *update.Amount
+	}
+	if update.AccountID != nil && *update.AccountID != income.AccountID {
+		if err := s.checkAccount(ctx, userID, income.VaultID, *update.AccountID); err != nil {
+			return nil, err
+		}
+		income.AccountID = *update.AccountID
+	}
+
+	err = s.incomeRepo.UpdateOne(ctx, income)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update income %s as user %s: %w", incomeID, userID, err)
+	}
+
+	return income, nil
+}
+
+func (s *IncomeService) DeleteOneByID(ctx context.Context, userID, incomeID string) error {
+	ctx, span := tracing.Start(ctx, "IncomeService.DeleteOneByID")
+	defer span.End()
+
+	income, err := s.findOneForWriting(ctx, userID, incomeID)
+	if err != nil {
+		return err
+	}
+
+	err = s.incomeRepo.DeleteOneByID(ctx, income.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete income %s as user %s: %w", incomeID, userID, err)
+	}
+
+	return nil
+}
+
+func (s *IncomeService) findOneForWriting(ctx context.Context, userID, incomeID string) (*models.Income, error) {
+	income, err := s.incomeRepo.FindOneByID(ctx, incomeID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrIncomeNotFound) {
+			return nil, ErrIncomeNotFound
+		}
+		return nil, fmt.Errorf("failed to find income %s: %w", incomeID, err)
+	}
+
+	vault, err := s.vaultService.FindOneByID(ctx, userID, income.VaultID)
+	if err != nil {
+		if errors.Is(err, ErrVaultNotFound) {
+			return nil, ErrIncomeNotFound
+		}
+		return nil, err
+	}
+
+	if !permissions.Can(vault.UserRole, permissions.ActionWriteExpenses) {
+		return nil, ErrInsufficientVaultPermissions
+	}
+
+	return income, nil
+}
+
+func (s *IncomeService) checkAccount(ctx context.Context, userID, vaultID, accountID string) error {
+	if accountID == "" {
+		return nil
+	}
+
+	account, err := s.accountService.FindOneByID(ctx, userID, accountID)
+	if err != nil {
+		return err
+	}
+	if account.VaultID != vaultID {
+		return ErrAccountNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestIncomeService_CreateOne(t *testing.T) {
+	t.Parallel()
+
+	t.Run("income increases account balance", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		incomeService := testutils.NewTestIncomeService(db)
+		accountService := testutils.NewTestAccountService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		account := testutils.CreateTestAccount(t, db, user.ID, vault.ID, 100)
+
+		err := incomeService.CreateOne(ctx, user.ID, models.Income{ // nolint: exhaustruct
+			Source:    "salary",
+			Date:      "2025-01-25",
+			Amount:    3000,
+			AccountID: account.ID,
+			VaultID:   vault.ID,
+		})
+		testutils.AssertNoError(t, err)
+
+		ledger, err := accountService.Ledger(ctx, user.ID, account.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, ledger.Account.Balance, 3100.0)
+		testutils.AssertEqual(t, len(ledger.Entries), 1)
+		testutils.AssertEqual(t, ledger.Entries[0].Kind, models.LedgerEntryKindIncome)
+		testutils.AssertEqual(t, ledger.Entries[0].Description, "salary")
+		testutils.AssertEqual(t, ledger.Entries[0].Balance, 3100.0)
+	})
+
+	t.Run("returns error when account belongs to another vault", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		incomeService := testutils.NewTestIncomeService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		_, otherUser, otherVault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		foreignAccount := testutils.CreateTestAccount(t, db, otherUser.ID, otherVault.ID, 0)
+
+		err := incomeService.CreateOne(ctx, user.ID, models.Income{ // nolint: exhaustruct
+			Source:    "salary",
+			Date:      "2025-01-25",
+			Amount:    3000,
+			AccountID: foreignAccount.ID,
+			VaultID:   vault.ID,
+		})
+		want := services.ErrAccountNotFound
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+
+	t.Run("returns error if user is a viewer", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		incomeService := testutils.NewTestIncomeService(db)
+		_, owner, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		viewer := testutils.CreateTestUser(t, db)
+		err := testutils.NewTestVaultService(db).AddUser(ctx, owner.ID, viewer.ID, vault.ID, models.VaultRoleViewer)
+		testutils.AssertNoError(t, err)
+
+		err = incomeService.CreateOne(ctx, viewer.ID, models.Income{Source: "salary", Date: "2025-01-25", Amount: 10, VaultID: vault.ID}) // nolint: exhaustruct
+		want := services.ErrInsufficientVaultPermissions
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+}
+
+func TestIncomeService_UpdateOne(t *testing.T) {
+	t.Parallel()
+
+	t.Run("updates amount and detaches account", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		incomeService := testutils.NewTestIncomeService(db)
+		accountService := testutils.NewTestAccountService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		account := testutils.CreateTestAccount(t, db, user.ID, vault.ID, 0)
+
+		err := incomeService.CreateOne(ctx, user.ID, models.Income{ // nolint: exhaustruct
+			Source:    "salary",
+			Date:      "2025-01-25",
+			Amount:    3000,
+			AccountID: account.ID,
+			VaultID:   vault.ID,
+		})
+		testutils.AssertNoError(t, err)
+
+		incomes, err := incomeService.FindAll(ctx, user.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(incomes), 1)
+
+		amount := 3500.0
+		noAccount := ""
+		updated, err := incomeService.UpdateOne(ctx, user.ID, incomes[0].ID, models.IncomeUpdate{ // nolint: exhaustruct
+			Amount:    &amount,
+			AccountID: &noAccount,
+		})
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, updated.Amount, 3500.0)
+		testutils.AssertEqual(t, updated.AccountID, "")
+		testutils.AssertEqual(t, updated.Source, "salary")
+
+		found, err := accountService.FindOneByID(ctx, user.ID, account.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, found.Balance, 0.0)
+	})
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/permissions"
+	"github.com/kkstas/tr-backend/internal/repositories"
+	"github.com/kkstas/tr-backend/internal/tracing"
+)
+
+var ErrPaymentMethodNotFound = errors.New("payment method not found")
+var ErrPaymentMethodWithThatNameAlreadyExists = errors.New("payment method with that name already exists")
+var ErrPaymentMethodArchived = errors.New("payment method is archived")
+var ErrPaymentMethodInUse = errors.New("payment method is used by expenses, archive it instead")
+
+type PaymentMethodService struct {
+	paymentMethodRepo *repositories.PaymentMethodRepo
+	vaultService      *VaultService
+}
+
+func NewPaymentMethodService(paymentMethodRepo *repositories.PaymentMethodRepo, vaultService *VaultService) *PaymentMethodService {
+	return &PaymentMethodService{
+		paymentMethodRepo: paymentMethodRepo,
+		vaultService:      vaultService,
+	}
+}
+
+func (s *PaymentMethodService) CreateOne(ctx context.Context, userID, vaultID, name string) error {
+	ctx, span := tracing.Start(ctx, "PaymentMethodService.CreateOne")
+	defer span.End()
+
+	vault, err := s.vaultService.FindOneByID(ctx, userID, vaultID)
+	if err != nil {
+		return err
+	}
+
+	if !permissions.Can(vault.UserRole, permissions.ActionManagePaymentMethods) {
+		return ErrInsufficientVaultPermissions
+	}
+
+	paymentMethods, err := s.paymentMethodRepo.FindAll(ctx, vault.ID)
+	if err != nil {
+		return fmt.Errorf("failed to find existing payment methods in vault %s before creating one: %w", vault.ID, err)
+	}
+
+	if paymentMethodNameTaken(paymentMethods, name, "") {
+		return ErrPaymentMethodWithThatNameAlreadyExists
+	}
+
+	_, err = s.paymentMethodRepo.CreateOne(ctx, name, vault.ID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to create payment method in vault %s: %w", vault.ID, err)
+	}
+
+	return nil
+}
+
+func (s *PaymentMethodService) FindAll(ctx context.Context, userID, vaultID string) ([]models.PaymentMethod, error) {
+	ctx, span := tracing.Start(ctx, "PaymentMethodService.FindAll")
+	defer span.End()
+
+	vault, err := s.vaultService.FindOneByID(ctx, userID, vaultID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !permissions.Can(vault.UserRole, permissions.ActionRead) {
+		return nil, ErrInsufficientVaultPermissions
+	}
+
+	paymentMethods, err := s.paymentMethodRepo.FindAll(ctx, vault.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find payment methods for vault %s & user %s: %w", vault.ID, userID, err)
+	}
+
+	return paymentMethods, nil
+}
+
+func (s *PaymentMethodService) FindOneByID(ctx context.Context, userID, paymentMethodID string) (*models.PaymentMethod, error) {
+	ctx, span := tracing.Start(ctx, "PaymentMethodService.FindOneByID")
+	defer span.End()
+
+	paymentMethod, _, err := s.findOneWithRole(ctx, userID, paymentMethodID)
+	return paymentMethod, err
+}
+
+func (s *PaymentMethodService) UpdateOne(ctx context.Context, userID, paymentMethodID string, update models.PaymentMethodUpdate) (*models.PaymentMethod, error) {
+	ctx, span := tracing.Start(ctx, "PaymentMethodService.UpdateOne")
+	defer span.End()
+
+	paymentMethod, err := s.findOneForManagement(ctx, userID, paymentMethodID)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Name != nil && *update.Name != paymentMethod.Name {
+		paymentMethods, err := s.paymentMethodRepo.FindAll(ctx, paymentMethod.VaultID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find existing payment methods in vault %s before renaming one: %w", paymentMethod.VaultID, err)
+		}
+		if paymentMethodNameTaken(paymentMethods, *update.Name, paymentMethod.ID) {
+			return nil, ErrPaymentMethodWithThatNameAlreadyExists
+		}
+		paymentMethod.Name = *update.Name
+	}
+	if update.Status != nil {
+		paymentMethod.Status = *update.Status
+	}
+
+	err = s.paymentMethodRepo.UpdateOne(ctx, paymentMethod)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update payment method %s as user %s: %w", paymentMethodID, userID, err)
+	}
+
+	return paymentMethod, nil
+}
+
+func (s *PaymentMethodService) DeleteOneByID(ctx context.Context, userID, paymentMethodID string) error {
+	ctx, span := tracing.Start(ctx, "PaymentMethodService.DeleteOneByID")
+	defer span.End()
+
+	paymentMethod, err := s.findOneForManagement(ctx, userID, paymentMethodID)
+	if err != nil {
+		return err
+	}
+
+	err = s.paymentMethodRepo.DeleteOneByID(ctx, paymentMethod.ID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrPaymentMethodInUse) {
+			return ErrPaymentMethodInUse
+		}
+		return fmt.Errorf("failed to delete payment method %s as user %s: %w", paymentMethodID, userID, err)
+	}
+
+	return nil
+}
+
+func (s *PaymentMethodService) findOneForManagement(ctx context.Context, userID, paymentMethodID string) (*models.PaymentMethod, error) {
+	paymentMethod, role, err := s.findOneWithRole(ctx, userID, paymentMethodID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !permissions.Can(role, permissions.ActionManagePaymentMethods) {
+		return nil, ErrInsufficientVaultPermissions
+	}
+
+	return paymentMethod, nil
+}
+
+func (s *PaymentMethodService) findOneWithRole(ctx context.Context, userID, paymentMethodID string) (*models.PaymentMethod, models.VaultRole, error) {
+	paymentMethod, err := s.paymentMethodRepo.FindOneByID(ctx, paymentMethodID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrPaymentMethodNotFound) {
+			return nil, "", ErrPaymentMethodNotFound
+		}
+		return nil, "", fmt.Errorf("failed to find payment method %s: %w", paymentMethodID, err)
+	}
+
+	vault, err := s.vaultService.FindOneByID(ctx, userID, paymentMethod.VaultID)
+	if err != nil {
+		if errors.Is(err, ErrVaultNotFound) {
+			return nil, "", ErrPaymentMethodNotFound
+		}
+		return nil, "", err
+	}
+
+	return paymentMethod, vault.UserRole, nil
+}
+
+func paymentMethodNameTaken(paymentMethods []models.PaymentMethod, name, exceptID string) bool {
+	for _, paymentMethod := range paymentMethods {
+		if paymentMethod.Name == name && paymentMethod.ID != exceptID {
+			return true
+		}
+	}
+	return false
+}
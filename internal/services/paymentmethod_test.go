@@ -0,0 +1,101 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestPaymentMethodService_CreateOne(t *testing.T) {
+	t.Parallel()
+
+	t.Run("creates payment method", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		paymentMethodService := testutils.NewTestPaymentMethodService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+
+		err := paymentMethodService.CreateOne(ctx, user.ID, vault.ID, "cash")
+		testutils.AssertNoError(t, err)
+
+		paymentMethods, err := paymentMethodService.FindAll(ctx, user.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(paymentMethods), 1)
+		testutils.AssertEqual(t, paymentMethods[0].Name, "cash")
+	})
+
+	t.Run("returns error if payment method with that name already exists", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		paymentMethodService := testutils.NewTestPaymentMethodService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+
+		err := paymentMethodService.CreateOne(ctx, user.ID, vault.ID, "cash")
+		testutils.AssertNoError(t, err)
+
+		err = paymentMethodService.CreateOne(ctx, user.ID, vault.ID, "cash")
+		want := services.ErrPaymentMethodWithThatNameAlreadyExists
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+
+	t.Run("returns error if user cannot manage payment methods", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		paymentMethodService := testutils.NewTestPaymentMethodService(db)
+		_, owner, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		editor := testutils.CreateTestUser(t, db)
+		err := testutils.NewTestVaultService(db).AddUser(ctx, owner.ID, editor.ID, vault.ID, models.VaultRoleEditor)
+		testutils.AssertNoError(t, err)
+
+		err = paymentMethodService.CreateOne(ctx, editor.ID, vault.ID, "cash")
+		want := services.ErrInsufficientVaultPermissions
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+}
+
+func TestPaymentMethodService_DeleteOneByID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns error if payment method is used by expenses", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		paymentMethodService := testutils.NewTestPaymentMethodService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		err := paymentMethodService.DeleteOneByID(ctx, user.ID, expense.PaymentMethodID)
+		want := services.ErrPaymentMethodInUse
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+
+	t.Run("returns error if user does not belong to payment method's vault", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		paymentMethodService := testutils.NewTestPaymentMethodService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		otherUser := testutils.CreateTestUser(t, db)
+		paymentMethod := testutils.CreateTestPaymentMethod(t, db, user.ID, vault.ID)
+
+		err := paymentMethodService.DeleteOneByID(ctx, otherUser.ID, paymentMethod.ID)
+		want := services.ErrPaymentMethodNotFound
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+}
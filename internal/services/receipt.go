@@ -0,0 +1,188 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+
+	"github.com/google/uuid"
+
+	"github.com/kkstas/tr-backend/internal/blobstore"
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/repositories"
+	"github.com/kkstas/tr-backend/internal/tracing"
+)
+
+var ErrReceiptNotFound = errors.New("receipt not found")
+var ErrReceiptTooLarge = errors.New("receipt is too large")
+var ErrReceiptTypeNotAllowed = errors.New("receipt type is not allowed")
+
+var allowedReceiptTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+}
+
+type ReceiptService struct {
+	receiptRepo    *repositories.ReceiptRepo
+	expenseService *ExpenseService
+	blobStore      blobstore.BlobStore
+	maxSize        int64
+}
+
+func NewReceiptService(
+	receiptRepo *repositories.ReceiptRepo,
+	expenseService *ExpenseService,
+	blobStore blobstore.BlobStore,
+	maxSize int64,
+) *ReceiptService {
+	return &ReceiptService{
+		receiptRepo:    receiptRepo,
+		expenseService: expenseService,
+		blobStore:      blobStore,
+		maxSize:        maxSize,
+	}
+}
+
+// CreateOne stores the file read from r as a receipt of the expense. Content type is
+// detected from the file contents rather than trusted from the client.
+func (s *ReceiptService) CreateOne(ctx context.Context, userID, expenseID, fileName string, r io.Reader) (*models.Receipt, error) {
+	ctx, span := tracing.Start(ctx, "ReceiptService.CreateOne")
+	defer span.End()
+
+	expense, err := s.expenseService.FindOneByID(ctx, userID, expenseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.expenseService.checkWritePermission(ctx, userID, expense.VaultID); err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, s.maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read receipt file: %w", err)
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, ErrReceiptTooLarge
+	}
+
+	contentType, _, err := mime.ParseMediaType(http.DetectContentType(data))
+	if err != nil || len(data) == 0 || !allowedReceiptTypes[contentType] {
+		return nil, ErrReceiptTypeNotAllowed
+	}
+
+	checksum := sha256.Sum256(data)
+	receiptID := uuid.New().String()
+	receipt := models.Receipt{ // nolint: exhaustruct
+		ID:          receiptID,
+		ExpenseID:   expense.ID,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		Checksum:    hex.EncodeToString(checksum[:]),
+		StorageKey:  path.Join("receipts", expense.VaultID, expense.ID, receiptID),
+		CreatedBy:   userID,
+	}
+
+	if err := s.blobStore.Put(ctx, receipt.StorageKey, bytes.NewReader(data), receipt.Size, receipt.ContentType); err != nil {
+		return nil, fmt.Errorf("failed to store receipt of expense %s: %w", expense.ID, err)
+	}
+
+	if err := s.receiptRepo.CreateOne(ctx, receipt); err != nil {
+		_ = s.blobStore.Delete(ctx, receipt.StorageKey)
+		return nil, fmt.Errorf("failed to create receipt of expense %s: %w", expense.ID, err)
+	}
+
+	return s.receiptRepo.FindOneByID(ctx, receiptID)
+}
+
+func (s *ReceiptService) FindAll(ctx context.Context, userID, expenseID string) ([]models.Receipt, error) {
+	ctx, span := tracing.Start(ctx, "ReceiptService.FindAll")
+	defer span.End()
+
+	expense, err := s.expenseService.FindOneByID(ctx, userID, expenseID)
+	if err != nil {
+		return nil, err
+	}
+
+	receipts, err := s.receiptRepo.FindAll(ctx, expense.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find receipts of expense %s & user %s: %w", expense.ID, userID, err)
+	}
+
+	return receipts, nil
+}
+
+// Open returns the receipt together with its file contents. The caller must close the returned reader.
+func (s *ReceiptService) Open(ctx context.Context, userID, receiptID string) (*models.Receipt, io.ReadCloser, error) {
+	ctx, span := tracing.Start(ctx, "ReceiptService.Open")
+	defer span.End()
+
+	receipt, _, err := s.findOne(ctx, userID, receiptID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rc, err := s.blobStore.Get(ctx, receipt.StorageKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open receipt %s: %w", receipt.ID, err)
+	}
+
+	return receipt, rc, nil
+}
+
+func (s *ReceiptService) DeleteOneByID(ctx context.Context, userID, receiptID string) error {
+	ctx, span := tracing.Start(ctx, "ReceiptService.DeleteOneByID")
+	defer span.End()
+
+	receipt, expense, err := s.findOne(ctx, userID, receiptID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.expenseService.checkWritePermission(ctx, userID, expense.VaultID); err != nil {
+		return err
+	}
+
+	// The file goes first, so that a failure leaves the receipt in place to retry deleting
+	// it rather than a file nothing refers to.
+	if err := s.blobStore.Delete(ctx, receipt.StorageKey); err != nil {
+		return fmt.Errorf("failed to delete file of receipt %s: %w", receipt.ID, err)
+	}
+
+	if err := s.receiptRepo.DeleteOneByID(ctx, receipt.ID); err != nil {
+		return fmt.Errorf("failed to delete receipt %s as user %s: %w", receipt.ID, userID, err)
+	}
+
+	return nil
+}
+
+// findOne returns the receipt and its expense if the user can read the expense.
+func (s *ReceiptService) findOne(ctx context.Context, userID, receiptID string) (*models.Receipt, *models.Expense, error) {
+	receipt, err := s.receiptRepo.FindOneByID(ctx, receiptID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrReceiptNotFound) {
+			return nil, nil, ErrReceiptNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to find receipt %s: %w", receiptID, err)
+	}
+
+	expense, err := s.expenseService.FindOneByID(ctx, userID, receipt.ExpenseID)
+	if err != nil {
+		if errors.Is(err, ErrExpenseNotFound) {
+			return nil, nil, ErrReceiptNotFound
+		}
+		return nil, nil, err
+	}
+
+	return receipt, expense, nil
+}
@@ -0,0 +1,153 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+var testPDF = []byte("%PDF-1.4\n1 0 obj << /Type /Catalog >> endobj\n%%EOF\n")
+
+func TestReceiptService_CreateOne(t *testing.T) {
+	t.Parallel()
+
+	t.Run("stores receipt with detected content type and checksum", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		receiptService := testutils.NewTestReceiptService(t, db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		receipt, err := receiptService.CreateOne(ctx, user.ID, expense.ID, "invoice.pdf", bytes.NewReader(testPDF))
+		testutils.AssertNoError(t, err)
+
+		sum := sha256.Sum256(testPDF)
+		testutils.AssertEqual(t, receipt.ContentType, "application/pdf")
+		testutils.AssertEqual(t, receipt.Checksum, hex.EncodeToString(sum[:]))
+		testutils.AssertEqual(t, receipt.Size, int64(len(testPDF)))
+		testutils.AssertEqual(t, receipt.FileName, "invoice.pdf")
+
+		_, file, err := receiptService.Open(ctx, user.ID, receipt.ID)
+		testutils.AssertNoError(t, err)
+		defer file.Close()
+		got, err := io.ReadAll(file)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, string(got), string(testPDF))
+	})
+
+	t.Run("returns error if file type is not allowed", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		receiptService := testutils.NewTestReceiptService(t, db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		_, err := receiptService.CreateOne(ctx, user.ID, expense.ID, "receipt.pdf", strings.NewReader("<html><script>alert(1)</script></html>"))
+		want := services.ErrReceiptTypeNotAllowed
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+
+	t.Run("returns error if file is too large", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		receiptService := testutils.NewTestReceiptService(t, db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		file := append(bytes.Clone(testPDF), make([]byte, testutils.TestReceiptMaxSize)...)
+		_, err := receiptService.CreateOne(ctx, user.ID, expense.ID, "receipt.pdf", bytes.NewReader(file))
+		want := services.ErrReceiptTooLarge
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+
+	t.Run("viewer cannot upload receipts", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		receiptService := testutils.NewTestReceiptService(t, db)
+		_, owner, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		viewer := testutils.CreateTestUser(t, db)
+		testutils.AssertNoError(t, testutils.NewTestVaultService(db).AddUser(ctx, owner.ID, viewer.ID, vault.ID, models.VaultRoleViewer))
+		category := testutils.CreateTestExpenseCategory(t, db, owner.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, owner.ID, vault.ID, category.ID)
+
+		_, err := receiptService.CreateOne(ctx, viewer.ID, expense.ID, "invoice.pdf", bytes.NewReader(testPDF))
+		want := services.ErrInsufficientVaultPermissions
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+}
+
+func TestReceiptService_Open(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns error if user is not a member of the expense vault", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		receiptService := testutils.NewTestReceiptService(t, db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		otherUser := testutils.CreateTestUser(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		receipt, err := receiptService.CreateOne(ctx, user.ID, expense.ID, "invoice.pdf", bytes.NewReader(testPDF))
+		testutils.AssertNoError(t, err)
+
+		_, _, err = receiptService.Open(ctx, otherUser.ID, receipt.ID)
+		want := services.ErrReceiptNotFound
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+}
+
+func TestReceiptService_DeleteOneByID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("removes receipt and its file", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		receiptService := testutils.NewTestReceiptService(t, db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		receipt, err := receiptService.CreateOne(ctx, user.ID, expense.ID, "invoice.pdf", bytes.NewReader(testPDF))
+		testutils.AssertNoError(t, err)
+
+		err = receiptService.DeleteOneByID(ctx, user.ID, receipt.ID)
+		testutils.AssertNoError(t, err)
+
+		receipts, err := receiptService.FindAll(ctx, user.ID, expense.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(receipts), 0)
+
+		_, _, err = receiptService.Open(ctx, user.ID, receipt.ID)
+		want := services.ErrReceiptNotFound
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+}
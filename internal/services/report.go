@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/permissions"
+	"github.com/kkstas/tr-backend/internal/repositories"
+	"github.com/kkstas/tr-backend/internal/tracing"
+)
+
+type ReportService struct {
+	reportRepo             *repositories.ReportRepo
+	vaultService           *VaultService
+	expenseCategoryService *ExpenseCategoryService
+	paymentMethodService   *PaymentMethodService
+	tagService             *TagService
+}
+
+func NewReportService(
+	reportRepo *repositories.ReportRepo,
+	vaultService *VaultService,
+	expenseCategoryService *ExpenseCategoryService,
+	paymentMethodService *PaymentMethodService,
+	tagService *TagService,
+) *ReportService {
+	return &ReportService{
+		reportRepo:             reportRepo,
+		vaultService:           vaultService,
+		expenseCategoryService: expenseCategoryService,
+		paymentMethodService:   paymentMethodService,
+		tagService:             tagService,
+	}
+}
+
+// CategoryTotals returns expense totals as a category tree, where RolledUpTotal of
+// each category includes the totals of all its subcategories.
+func (s *ReportService) CategoryTotals(ctx context.Context, userID, vaultID, from, to string) ([]models.CategoryTotal, error) {
+	ctx, span := tracing.Start(ctx, "ReportService.CategoryTotals")
+	defer span.End()
+
+	tree, err := s.expenseCategoryService.FindTree(ctx, userID, vaultID)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.reportRepo.SumExpensesByCategory(ctx, vaultID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum expenses by category in vault %s: %w", vaultID, err)
+	}
+
+	return rollUpCategoryTotals(tree, totals), nil
+}
+
+func (s *ReportService) PaymentMethodTotals(ctx context.Context, userID, vaultID, from, to string) ([]models.PaymentMethodTotal, error) {
+	ctx, span := tracing.Start(ctx, "ReportService.PaymentMethodTotals")
+	defer span.End()
+
+	paymentMethods, err := s.paymentMethodService.FindAll(ctx, userID, vaultID)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.reportRepo.SumExpensesByPaymentMethod(ctx, vaultID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum expenses by payment method in vault %s: %w", vaultID, err)
+	}
+
+	result := make([]models.PaymentMethodTotal, 0, len(paymentMethods))
+	for _, paymentMethod := range paymentMethods {
+		result = append(result, models.PaymentMethodTotal{
+			PaymentMethodID: paymentMethod.ID,
+			Name:            paymentMethod.Name,
+			Status:          paymentMethod.Status,
+			Total:           totals[paymentMethod.ID],
+		})
+	}
+	return result, nil
+}
+
+// TagTotals returns count and total of expenses for every tag in the vault. Expense with
+// several tags counts towards each of them, so totals may add up to more than all expenses.
+func (s *ReportService) TagTotals(ctx context.Context, userID, vaultID, from, to string) ([]models.TagTotal, error) {
+	ctx, span := tracing.Start(ctx, "ReportService.TagTotals")
+	defer span.End()
+
+	tags, err := s.tagService.FindAll(ctx, userID, vaultID)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.reportRepo.SumExpensesByTag(ctx, vaultID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum expenses by tag in vault %s: %w", vaultID, err)
+	}
+
+	result := make([]models.TagTotal, 0, len(tags))
+	for _, tag := range tags {
+		result = append(result, models.TagTotal{
+			TagID: tag.ID,
+			Name:  tag.Name,
+			Count: totals[tag.ID].Count,
+			Total: totals[tag.ID].Total,
+		})
+	}
+	return result, nil
+}
+
+// Cashflow returns income, expenses and net cashflow of the vault for every month
+// that has at least one income or expense.
+func (s *ReportService) Cashflow(ctx context.Context, userID, vaultID, from, to string) ([]models.MonthlyCashflow, error) {
+	ctx, span := tracing.Start(ctx, "ReportService.Cashflow")
+	defer span.End()
+
+	vault, err := s.vaultService.FindOneByID(ctx, userID, vaultID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !permissions.Can(vault.UserRole, permissions.ActionRead) {
+		return nil, ErrInsufficientVaultPermissions
+	}
+
+	cashflow, err := s.reportRepo.SumCashflowByMonth(ctx, vault.ID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum cashflow by month in vault %s: %w", vault.ID, err)
+	}
+
+	return cashflow, nil
+}
+
+func rollUpCategoryTotals(nodes []models.ExpenseCategoryTreeNode, totals map[string]float64) []models.CategoryTotal {
+	result := make([]models.CategoryTotal, 0, len(nodes))
+	for _, node := range nodes {
+		categoryTotal := models.CategoryTotal{
+			CategoryID:    node.ID,
+			Name:          node.Name,
+			Total:         totals[node.ID],
+			RolledUpTotal: totals[node.ID],
+			Subcategories: rollUpCategoryTotals(node.Children, totals),
+		}
+		for _, subcategory := range categoryTotal.Subcategories {
+			categoryTotal.RolledUpTotal += subcategory.RolledUpTotal
+		}
+		result = append(result, categoryTotal)
+	}
+	return result
+}
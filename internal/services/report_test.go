@@ -0,0 +1,93 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestReportService_CategoryTotals(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rolls subcategory totals up into parents", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		reportService := testutils.NewTestReportService(db)
+		expenseCategoryService := testutils.NewTestExpenseCategoryService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		food := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		groceries := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		restaurants := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+
+		for _, category := range []*models.ExpenseCategory{groceries, restaurants} {
+			_, err := expenseCategoryService.UpdateOne(ctx, user.ID, category.ID, models.ExpenseCategoryUpdate{ParentID: &food.ID}) // nolint: exhaustruct
+			testutils.AssertNoError(t, err)
+		}
+
+		testutils.CreateTestExpense(t, db, user.ID, vault.ID, food.ID)
+		testutils.CreateTestExpense(t, db, user.ID, vault.ID, groceries.ID)
+		testutils.CreateTestExpense(t, db, user.ID, vault.ID, groceries.ID)
+		testutils.CreateTestExpense(t, db, user.ID, vault.ID, restaurants.ID)
+
+		totals, err := reportService.CategoryTotals(ctx, user.ID, vault.ID, "", "")
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(totals), 1)
+		testutils.AssertEqual(t, totals[0].CategoryID, food.ID)
+		testutils.AssertEqual(t, totals[0].Total, 12.5)
+		testutils.AssertEqual(t, totals[0].RolledUpTotal, 50.0)
+		testutils.AssertEqual(t, len(totals[0].Subcategories), 2)
+		subtotals := map[string]float64{}
+		for _, subcategory := range totals[0].Subcategories {
+			subtotals[subcategory.CategoryID] = subcategory.RolledUpTotal
+		}
+		testutils.AssertEqual(t, subtotals[groceries.ID], 25.0)
+		testutils.AssertEqual(t, subtotals[restaurants.ID], 12.5)
+	})
+
+	t.Run("only sums expenses within date range", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		reportService := testutils.NewTestReportService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		totals, err := reportService.CategoryTotals(ctx, user.ID, vault.ID, "2025-02-01", "")
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, totals[0].RolledUpTotal, 0.0)
+
+		totals, err = reportService.CategoryTotals(ctx, user.ID, vault.ID, "2025-01-01", "2025-01-31")
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, totals[0].RolledUpTotal, 12.5)
+	})
+}
+
+func TestReportService_PaymentMethodTotals(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sums expenses per payment method including unused ones", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		reportService := testutils.NewTestReportService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+		unused := testutils.CreateTestPaymentMethod(t, db, user.ID, vault.ID)
+
+		totals, err := reportService.PaymentMethodTotals(ctx, user.ID, vault.ID, "", "")
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(totals), 2)
+
+		byID := map[string]float64{}
+		for _, total := range totals {
+			byID[total.PaymentMethodID] = total.Total
+		}
+		testutils.AssertEqual(t, byID[expense.PaymentMethodID], 12.5)
+		testutils.AssertEqual(t, byID[unused.ID], 0.0)
+	})
+}
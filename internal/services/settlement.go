@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/permissions"
+	"github.com/kkstas/tr-backend/internal/repositories"
+	"github.com/kkstas/tr-backend/internal/splits"
+	"github.com/kkstas/tr-backend/internal/tracing"
+)
+
+var ErrSettlementNotFound = errors.New("settlement not found")
+var ErrSettlementWithSelf = errors.New("cannot settle up with yourself")
+
+type SettlementService struct {
+	settlementRepo *repositories.SettlementRepo
+	vaultService   *VaultService
+}
+
+func NewSettlementService(settlementRepo *repositories.SettlementRepo, vaultService *VaultService) *SettlementService {
+	return &SettlementService{
+		settlementRepo: settlementRepo,
+		vaultService:   vaultService,
+	}
+}
+
+func (s *SettlementService) CreateOne(ctx context.Context, userID string, settlement models.Settlement) error {
+	ctx, span := tracing.Start(ctx, "SettlementService.CreateOne")
+	defer span.End()
+
+	if settlement.FromUserID == settlement.ToUserID {
+		return ErrSettlementWithSelf
+	}
+
+	vault, err := s.vaultService.FindOneByID(ctx, userID, settlement.VaultID)
+	if err != nil {
+		return err
+	}
+
+	if !permissions.Can(vault.UserRole, permissions.ActionWriteExpenses) {
+		return ErrInsufficientVaultPermissions
+	}
+
+	members, err := s.vaultService.FindMembers(ctx, userID, vault.ID)
+	if err != nil {
+		return err
+	}
+	if !hasMember(members, settlement.FromUserID) || !hasMember(members, settlement.ToUserID) {
+		return ErrUserNotAssignedToVault
+	}
+
+	settlement.CreatedBy = userID
+
+	_, err = s.settlementRepo.CreateOne(ctx, settlement)
+	if err != nil {
+		return fmt.Errorf("failed to create settlement in vault %s: %w", vault.ID, err)
+	}
+
+	return nil
+}
+
+func (s *SettlementService) FindAll(ctx context.Context, userID, vaultID string) ([]models.Settlement, error) {
+	ctx, span := tracing.Start(ctx, "SettlementService.FindAll")
+	defer span.End()
+
+	vault, err := s.vaultService.FindOneByID(ctx, userID, vaultID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !permissions.Can(vault.UserRole, permissions.ActionRead) {
+		return nil, ErrInsufficientVaultPermissions
+	}
+
+	settlements, err := s.settlementRepo.FindAll(ctx, vault.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find settlements for vault %s & user %s: %w", vault.ID, userID, err)
+	}
+
+	return settlements, nil
+}
+
+func (s *SettlementService) DeleteOneByID(ctx context.Context, userID, settlementID string) error {
+	ctx, span := tracing.Start(ctx, "SettlementService.DeleteOneByID")
+	defer span.End()
+
+	settlement, err := s.settlementRepo.FindOneByID(ctx, settlementID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrSettlementNotFound) {
+			return ErrSettlementNotFound
+		}
+		return fmt.Errorf("failed to find settlement %s: %w", settlementID, err)
+	}
+
+	vault, err := s.vaultService.FindOneByID(ctx, userID, settlement.VaultID)
+	if err != nil {
+		if errors.Is(err, ErrVaultNotFound) {
+			return ErrSettlementNotFound
+		}
+		return err
+	}
+
+	if !permissions.Can(vault.UserRole, permissions.ActionWriteExpenses) {
+		return ErrInsufficientVaultPermissions
+	}
+
+	err = s.settlementRepo.DeleteOneByID(ctx, settlement.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete settlement %s as user %s: %w", settlementID, userID, err)
+	}
+
+	return nil
+}
+
+// Balances returns balance of every vault member, rounded to cents.
+func (s *SettlementService) Balances(ctx context.Context, userID, vaultID string) ([]models.MemberBalance, error) {
+	ctx, span := tracing.Start(ctx, "SettlementService.Balances")
+	defer span.End()
+
+	members, err := s.vaultService.FindMembers(ctx, userID, vaultID)
+	if err != nil {
+		return nil, err
+	}
+
+	sums, err := s.settlementRepo.SumBalances(ctx, vaultID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum balances in vault %s: %w", vaultID, err)
+	}
+
+	balances := make([]models.MemberBalance, 0, len(members))
+	for _, member := range members {
+		balances = append(balances, models.MemberBalance{
+			UserID:    member.UserID,
+			FirstName: member.FirstName,
+			LastName:  member.LastName,
+			Balance:   math.Round(sums[member.UserID]*100) / 100,
+		})
+	}
+	return balances, nil
+}
+
+// Suggestions returns transfers between members that settle all balances in the vault.
+func (s *SettlementService) Suggestions(ctx context.Context, userID, vaultID string) ([]models.SettlementSuggestion, error) {
+	ctx, span := tracing.Start(ctx, "SettlementService.Suggestions")
+	defer span.End()
+
+	balances, err := s.Balances(ctx, userID, vaultID)
+	if err != nil {
+		return nil, err
+	}
+	return splits.Settle(balances), nil
+}
+
+func hasMember(members []models.VaultMember, userID string) bool {
+	for _, member := range members {
+		if member.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestSettlementService_Balances(t *testing.T) {
+	t.Parallel()
+
+	t.Run("computes balances from split expenses and settlements", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		settlementService := testutils.NewTestSettlementService(db)
+		expenseService := testutils.NewTestExpenseService(db)
+		vaultService := testutils.NewTestVaultService(db)
+		_, alice, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		bob := testutils.CreateTestUser(t, db)
+		carol := testutils.CreateTestUser(t, db)
+		testutils.AssertNoError(t, vaultService.AddUser(ctx, alice.ID, bob.ID, vault.ID, models.VaultRoleEditor))
+		testutils.AssertNoError(t, vaultService.AddUser(ctx, alice.ID, carol.ID, vault.ID, models.VaultRoleEditor))
+		category := testutils.CreateTestExpenseCategory(t, db, alice.ID, vault.ID)
+		paymentMethod := testutils.CreateTestPaymentMethod(t, db, alice.ID, vault.ID)
+
+		err := expenseService.CreateOne(ctx, alice.ID, models.Expense{ // nolint: exhaustruct
+			Name:            "dinner",
+			Date:            "2025-03-01",
+			CategoryID:      category.ID,
+			Amount:          90,
+			PaymentMethodID: paymentMethod.ID,
+			SplitMethod:     models.SplitMethodEqual,
+			Splits: []models.ExpenseSplit{
+				{UserID: alice.ID, Amount: 30},
+				{UserID: bob.ID, Amount: 30},
+				{UserID: carol.ID, Amount: 30},
+			},
+			VaultID: vault.ID,
+		})
+		testutils.AssertNoError(t, err)
+
+		err = settlementService.CreateOne(ctx, bob.ID, models.Settlement{ // nolint: exhaustruct
+			FromUserID: bob.ID,
+			ToUserID:   alice.ID,
+			Amount:     30,
+			Date:       "2025-03-02",
+			VaultID:    vault.ID,
+		})
+		testutils.AssertNoError(t, err)
+
+		balances, err := settlementService.Balances(ctx, alice.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(balances), 3)
+		byUser := map[string]float64{}
+		for _, balance := range balances {
+			byUser[balance.UserID] = balance.Balance
+		}
+		testutils.AssertEqual(t, byUser[alice.ID], 30.0)
+		testutils.AssertEqual(t, byUser[bob.ID], 0.0)
+		testutils.AssertEqual(t, byUser[carol.ID], -30.0)
+
+		suggestions, err := settlementService.Suggestions(ctx, alice.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(suggestions), 1)
+		testutils.AssertEqual(t, suggestions[0], models.SettlementSuggestion{FromUserID: carol.ID, ToUserID: alice.ID, Amount: 30})
+	})
+
+	t.Run("ignores deleted expenses", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		settlementService := testutils.NewTestSettlementService(db)
+		expenseService := testutils.NewTestExpenseService(db)
+		_, alice, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		bob := testutils.CreateTestUser(t, db)
+		testutils.AssertNoError(t, testutils.NewTestVaultService(db).AddUser(ctx, alice.ID, bob.ID, vault.ID, models.VaultRoleEditor))
+		category := testutils.CreateTestExpenseCategory(t, db, alice.ID, vault.ID)
+		paymentMethod := testutils.CreateTestPaymentMethod(t, db, alice.ID, vault.ID)
+
+		err := expenseService.CreateOne(ctx, alice.ID, models.Expense{ // nolint: exhaustruct
+			Name:            "dinner",
+			Date:            "2025-03-01",
+			CategoryID:      category.ID,
+			Amount:          10,
+			PaymentMethodID: paymentMethod.ID,
+			SplitMethod:     models.SplitMethodEqual,
+			Splits:          []models.ExpenseSplit{{UserID: alice.ID, Amount: 5}, {UserID: bob.ID, Amount: 5}},
+			VaultID:         vault.ID,
+		})
+		testutils.AssertNoError(t, err)
+
+		expenses, err := expenseService.FindAll(ctx, alice.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertNoError(t, expenseService.DeleteOneByID(ctx, alice.ID, expenses[0].ID))
+
+		balances, err := settlementService.Balances(ctx, alice.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		for _, balance := range balances {
+			testutils.AssertEqual(t, balance.Balance, 0.0)
+		}
+	})
+}
+
+func TestSettlementService_CreateOne(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns error if user is not a vault member", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		settlementService := testutils.NewTestSettlementService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		stranger := testutils.CreateTestUser(t, db)
+
+		err := settlementService.CreateOne(ctx, user.ID, models.Settlement{ // nolint: exhaustruct
+			FromUserID: stranger.ID,
+			ToUserID:   user.ID,
+			Amount:     10,
+			Date:       "2025-03-02",
+			VaultID:    vault.ID,
+		})
+		want := services.ErrUserNotAssignedToVault
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+
+	t.Run("returns error when settling with yourself", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		settlementService := testutils.NewTestSettlementService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+
+		err := settlementService.CreateOne(ctx, user.ID, models.Settlement{ // nolint: exhaustruct
+			FromUserID: user.ID,
+			ToUserID:   user.ID,
+			Amount:     10,
+			Date:       "2025-03-02",
+			VaultID:    vault.ID,
+		})
+		want := services.ErrSettlementWithSelf
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/permissions"
+	"github.com/kkstas/tr-backend/internal/repositories"
+	"github.com/kkstas/tr-backend/internal/tracing"
+)
+
+var ErrTagNotFound = errors.New("tag not found")
+var ErrTagWithThatNameAlreadyExists = errors.New("tag with that name already exists")
+
+type TagService struct {
+	tagRepo      *repositories.TagRepo
+	vaultService *VaultService
+}
+
+func NewTagService(tagRepo *repositories.TagRepo, vaultService *VaultService) *TagService {
+	return &TagService{
+		tagRepo:      tagRepo,
+		vaultService: vaultService,
+	}
+}
+
+func (s *TagService) CreateOne(ctx context.Context, userID, vaultID, name string) error {
+	ctx, span := tracing.Start(ctx, "TagService.CreateOne")
+	defer span.End()
+
+	vault, err := s.vaultService.FindOneByID(ctx, userID, vaultID)
+	if err != nil {
+		return err
+	}
+
+	if !permissions.Can(vault.UserRole, permissions.ActionManageTags) {
+		return ErrInsufficientVaultPermissions
+	}
+
+	tags, err := s.tagRepo.FindAll(ctx, vault.ID)
+	if err != nil {
+		return fmt.Errorf("failed to find existing tags in vault %s before creating one: %w", vault.ID, err)
+	}
+
+	if tagNameTaken(tags, name, "") {
+		return ErrTagWithThatNameAlreadyExists
+	}
+
+	_, err = s.tagRepo.CreateOne(ctx, name, vault.ID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to create tag in vault %s: %w", vault.ID, err)
+	}
+
+	return nil
+}
+
+func (s *TagService) FindAll(ctx context.Context, userID, vaultID string) ([]models.Tag, error) {
+	ctx, span := tracing.Start(ctx, "TagService.FindAll")
+	defer span.End()
+
+	vault, err := s.vaultService.FindOneByID(ctx, userID, vaultID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !permissions.Can(vault.UserRole, permissions.ActionRead) {
+		return nil, ErrInsufficientVaultPermissions
+	}
+
+	tags, err := s.tagRepo.FindAll(ctx, vault.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tags for vault %s & user %s: %w", vault.ID, userID, err)
+	}
+
+	return tags, nil
+}
+
+func (s *TagService) Rename(ctx context.Context, userID, tagID, name string) (*models.Tag, error) {
+	ctx, span := tracing.Start(ctx, "TagService.Rename")
+	defer span.End()
+
+	tag, err := s.findOneForManagement(ctx, userID, tagID)
+	if err != nil {
+		return nil, err
+	}
+
+	if name == tag.Name {
+		return tag, nil
+	}
+
+	tags, err := s.tagRepo.FindAll(ctx, tag.VaultID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find existing tags in vault %s before renaming one: %w", tag.VaultID, err)
+	}
+	if tagNameTaken(tags, name, tag.ID) {
+		return nil, ErrTagWithThatNameAlreadyExists
+	}
+	tag.Name = name
+
+	err = s.tagRepo.UpdateOne(ctx, tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rename tag %s as user %s: %w", tagID, userID, err)
+	}
+
+	return tag, nil
+}
+
+func (s *TagService) DeleteOneByID(ctx context.Context, userID, tagID string) error {
+	ctx, span := tracing.Start(ctx, "TagService.DeleteOneByID")
+	defer span.End()
+
+	tag, err := s.findOneForManagement(ctx, userID, tagID)
+	if err != nil {
+		return err
+	}
+
+	err = s.tagRepo.DeleteOneByID(ctx, tag.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag %s as user %s: %w", tagID, userID, err)
+	}
+
+	return nil
+}
+
+// checkTags returns ErrTagNotFound unless every tag exists in the vault.
+func (s *TagService) checkTags(ctx context.Context, vaultID string, tagIDs []string) error {
+	if len(tagIDs) == 0 {
+		return nil
+	}
+
+	tags, err := s.tagRepo.FindAll(ctx, vaultID)
+	if err != nil {
+		return fmt.Errorf("failed to find tags of vault %s: %w", vaultID, err)
+	}
+
+	for _, tagID := range tagIDs {
+		found := false
+		for _, tag := range tags {
+			if tag.ID == tagID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ErrTagNotFound
+		}
+	}
+	return nil
+}
+
+func (s *TagService) findOneForManagement(ctx context.Context, userID, tagID string) (*models.Tag, error) {
+	tag, err := s.tagRepo.FindOneByID(ctx, tagID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrTagNotFound) {
+			return nil, ErrTagNotFound
+		}
+		return nil, fmt.Errorf("failed to find tag %s: %w", tagID, err)
+	}
+
+	vault, err := s.vaultService.FindOneByID(ctx, userID, tag.VaultID)
+	if err != nil {
+		if errors.Is(err, ErrVaultNotFound) {
+			return nil, ErrTagNotFound
+		}
+		return nil, err
+	}
+
+	if !permissions.Can(vault.UserRole, permissions.ActionManageTags) {
+		return nil, ErrInsufficientVaultPermissions
+	}
+
+	return tag, nil
+}
+
+func tagNameTaken(tags []models.Tag, name, exceptID string) bool {
+	for _, tag := range tags {
+		if tag.Name == name && tag.ID != exceptID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestTagService_CreateOne(t *testing.T) {
+	t.Parallel()
+
+	t.Run("editor can create tags", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		tagService := testutils.NewTestTagService(db)
+		_, owner, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		editor := testutils.CreateTestUser(t, db)
+		testutils.AssertNoError(t, testutils.NewTestVaultService(db).AddUser(ctx, owner.ID, editor.ID, vault.ID, models.VaultRoleEditor))
+
+		err := tagService.CreateOne(ctx, editor.ID, vault.ID, "vacation 2026")
+		testutils.AssertNoError(t, err)
+
+		tags, err := tagService.FindAll(ctx, owner.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(tags), 1)
+		testutils.AssertEqual(t, tags[0].CreatedBy, editor.ID)
+	})
+
+	t.Run("returns error if tag with that name already exists", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		tagService := testutils.NewTestTagService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+
+		testutils.AssertNoError(t, tagService.CreateOne(ctx, user.ID, vault.ID, "tax deductible"))
+
+		err := tagService.CreateOne(ctx, user.ID, vault.ID, "tax deductible")
+		want := services.ErrTagWithThatNameAlreadyExists
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+}
+
+func TestTagService_DeleteOneByID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("removes tag from expenses", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		tagService := testutils.NewTestTagService(db)
+		expenseService := testutils.NewTestExpenseService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		tag := testutils.CreateTestTag(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		_, err := expenseService.UpdateOne(ctx, user.ID, expense.ID, models.ExpenseUpdate{TagIDs: &[]string{tag.ID}}) // nolint: exhaustruct
+		testutils.AssertNoError(t, err)
+
+		err = tagService.DeleteOneByID(ctx, user.ID, tag.ID)
+		testutils.AssertNoError(t, err)
+
+		expenses, err := expenseService.FindAll(ctx, user.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(expenses[0].TagIDs), 0)
+	})
+
+	t.Run("returns error if user is a viewer", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		tagService := testutils.NewTestTagService(db)
+		_, owner, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		viewer := testutils.CreateTestUser(t, db)
+		testutils.AssertNoError(t, testutils.NewTestVaultService(db).AddUser(ctx, owner.ID, viewer.ID, vault.ID, models.VaultRoleViewer))
+		tag := testutils.CreateTestTag(t, db, owner.ID, vault.ID)
+
+		err := tagService.DeleteOneByID(ctx, viewer.ID, tag.ID)
+		want := services.ErrInsufficientVaultPermissions
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+}
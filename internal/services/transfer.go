@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/permissions"
+	"github.com/kkstas/tr-backend/internal/repositories"
+	"github.com/kkstas/tr-backend/internal/tracing"
+)
+
+var ErrTransferNotFound = errors.New("transfer not found")
+var ErrTransferToSameAccount = errors.New("cannot transfer money to the same account")
+
+type TransferService struct {
+	transferRepo   *repositories.TransferRepo
+	vaultService   *VaultService
+	accountService *AccountService
+}
+
+func NewTransferService(transferRepo *repositories.TransferRepo, vaultService *VaultService, accountService *AccountService) *TransferService {
+	return &TransferService{
+		transferRepo:   transferRepo,
+		vaultService:   vaultService,
+		accountService: accountService,
+	}
+}
+
+func (s *TransferService) CreateOne(ctx context.Context, userID string, transfer models.Transfer) error {
+	ctx, span := tracing.Start(ctx, "TransferService.CreateOne")
+	defer span.End()
+
+	if transfer.FromAccountID == transfer.ToAccountID {
+		return ErrTransferToSameAccount
+	}
+
+	vault, err := s.vaultService.FindOneByID(ctx, userID, transfer.VaultID)
+	if err != nil {
+		return err
+	}
+
+	if !permissions.Can(vault.UserRole, permissions.ActionWriteExpenses) {
+		return ErrInsufficientVaultPermissions
+	}
+
+	for _, accountID := range []string{transfer.FromAccountID, transfer.ToAccountID} {
+		account, err := s.accountService.FindOneByID(ctx, userID, accountID)
+		if err != nil {
+			return err
+		}
+		if account.VaultID != vault.ID {
+			return ErrAccountNotFound
+		}
+	}
+
+	transfer.CreatedBy = userID
+
+	_, err = s.transferRepo.CreateOne(ctx, transfer)
+	if err != nil {
+		return fmt.Errorf("failed to create transfer in vault %s: %w", vault.ID, err)
+	}
+
+	return nil
+}
+
+func (s *TransferService) FindAll(ctx context.Context, userID, vaultID string) ([]models.Transfer, error) {
+	ctx, span := tracing.Start(ctx, "TransferService.FindAll")
+	defer span.End()
+
+	vault, err := s.vaultService.FindOneByID(ctx, userID, vaultID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !permissions.Can(vault.UserRole, permissions.ActionRead) {
+		return nil, ErrInsufficientVaultPermissions
+	}
+
+	transfers, err := s.transferRepo.FindAll(ctx, vault.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find transfers for vault %s & user %s: %w", vault.ID, userID, err)
+	}
+
+	return transfers, nil
+}
+
+func (s *TransferService) DeleteOneByID(ctx context.Context, userID, transferID string) error {
+	ctx, span := tracing.Start(ctx, "TransferService.DeleteOneByID")
+	defer span.End()
+
+	transfer, err := s.transferRepo.FindOneByID(ctx, transferID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrTransferNotFound) {
+			return ErrTransferNotFound
+		}
+		return fmt.Errorf("failed to find transfer %s: %w", transferID, err)
+	}
+
+	vault, err := s.vaultService.FindOneByID(ctx, userID, transfer.VaultID)
+	if err != nil {
+		if errors.Is(err, ErrVaultNotFound) {
+			return ErrTransferNotFound
+		}
+		return err
+	}
+
+	if !permissions.Can(vault.UserRole, permissions.ActionWriteExpenses) {
+		return ErrInsufficientVaultPermissions
+	}
+
+	err = s.transferRepo.DeleteOneByID(ctx, transfer.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete transfer %s as user %s: %w", transferID, userID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestTransferService_CreateOne(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns error when transferring to the same account", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		transferService := testutils.NewTestTransferService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		account := testutils.CreateTestAccount(t, db, user.ID, vault.ID, 100)
+
+		err := transferService.CreateOne(ctx, user.ID, models.Transfer{ // nolint: exhaustruct
+			FromAccountID: account.ID,
+			ToAccountID:   account.ID,
+			Amount:        10,
+			Date:          "2025-01-05",
+			VaultID:       vault.ID,
+		})
+		want := services.ErrTransferToSameAccount
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+
+	t.Run("returns error when account belongs to another vault", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		transferService := testutils.NewTestTransferService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		_, otherUser, otherVault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		account := testutils.CreateTestAccount(t, db, user.ID, vault.ID, 100)
+		foreignAccount := testutils.CreateTestAccount(t, db, otherUser.ID, otherVault.ID, 100)
+
+		err := transferService.CreateOne(ctx, user.ID, models.Transfer{ // nolint: exhaustruct
+			FromAccountID: account.ID,
+			ToAccountID:   foreignAccount.ID,
+			Amount:        10,
+			Date:          "2025-01-05",
+			VaultID:       vault.ID,
+		})
+		want := services.ErrAccountNotFound
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+}
+
+func TestTransferService_DeleteOneByID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("deleting transfer restores account balances", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		transferService := testutils.NewTestTransferService(db)
+		accountService := testutils.NewTestAccountService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		from := testutils.CreateTestAccount(t, db, user.ID, vault.ID, 100)
+		to := testutils.CreateTestAccount(t, db, user.ID, vault.ID, 0)
+
+		err := transferService.CreateOne(ctx, user.ID, models.Transfer{ // nolint: exhaustruct
+			FromAccountID: from.ID,
+			ToAccountID:   to.ID,
+			Amount:        40,
+			Date:          "2025-01-05",
+			VaultID:       vault.ID,
+		})
+		testutils.AssertNoError(t, err)
+
+		transfers, err := transferService.FindAll(ctx, user.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(transfers), 1)
+
+		err = transferService.DeleteOneByID(ctx, user.ID, transfers[0].ID)
+		testutils.AssertNoError(t, err)
+
+		accounts, err := accountService.FindAll(ctx, user.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		for _, account := range accounts {
+			testutils.AssertEqual(t, account.Balance, account.OpeningBalance)
+		}
+	})
+}
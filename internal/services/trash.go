@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kkstas/tr-backend/internal/blobstore"
+	"github.com/kkstas/tr-backend/internal/repositories"
+	"github.com/kkstas/tr-backend/internal/tracing"
+)
+
+// TrashService permanently removes soft deleted expenses and vaults together with files
+// of their receipts.
+type TrashService struct {
+	expenseRepo *repositories.ExpenseRepo
+	vaultRepo   *repositories.VaultRepo
+	receiptRepo *repositories.ReceiptRepo
+	blobStore   blobstore.BlobStore
+}
+
+func NewTrashService(
+	expenseRepo *repositories.ExpenseRepo,
+	vaultRepo *repositories.VaultRepo,
+	receiptRepo *repositories.ReceiptRepo,
+	blobStore blobstore.BlobStore,
+) *TrashService {
+	return &TrashService{
+		expenseRepo: expenseRepo,
+		vaultRepo:   vaultRepo,
+		receiptRepo: receiptRepo,
+		blobStore:   blobStore,
+	}
+}
+
+// PurgeDeleted removes expenses and vaults deleted more than retention ago and returns how
+// many of them were removed. Receipt files are deleted first, as their rows are removed by
+// the cascade. If any file can't be deleted, nothing is purged and the next run retries.
+func (s *TrashService) PurgeDeleted(ctx context.Context, retention time.Duration) (int64, error) {
+	ctx, span := tracing.Start(ctx, "TrashService.PurgeDeleted")
+	defer span.End()
+
+	deletedBefore := time.Now().Add(-retention)
+
+	keys, err := s.receiptRepo.FindStorageKeysOfDeleted(ctx, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+
+	var errs []error
+	for _, key := range keys {
+		if err := s.blobStore.Delete(ctx, key); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete receipt file %s: %w", key, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return 0, err
+	}
+
+	expenses, err := s.expenseRepo.PurgeDeleted(ctx, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+
+	vaults, err := s.vaultRepo.PurgeDeleted(ctx, deletedBefore)
+	if err != nil {
+		return expenses, err
+	}
+
+	return expenses + vaults, nil
+}
@@ -0,0 +1,94 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kkstas/tr-backend/internal/blobstore"
+	"github.com/kkstas/tr-backend/internal/database"
+	"github.com/kkstas/tr-backend/internal/repositories"
+	"github.com/kkstas/tr-backend/internal/services"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestTrashService_PurgeDeleted(t *testing.T) {
+	t.Parallel()
+
+	newServices := func(t *testing.T, db *database.DB) (*services.TrashService, *services.ReceiptService, blobstore.BlobStore) {
+		t.Helper()
+		blobStore, err := blobstore.NewLocalStore(t.TempDir())
+		testutils.AssertNoError(t, err)
+		receiptRepo := repositories.NewReceiptRepo(db)
+		trashService := services.NewTrashService(repositories.NewExpenseRepo(db), repositories.NewVaultRepo(db), receiptRepo, blobStore)
+		receiptService := services.NewReceiptService(receiptRepo, testutils.NewTestExpenseService(db), blobStore, testutils.TestReceiptMaxSize)
+		return trashService, receiptService, blobStore
+	}
+
+	t.Run("removes receipt files of purged expenses", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		trashService, receiptService, blobStore := newServices(t, db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		deleted := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+		kept := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		deletedReceipt, err := receiptService.CreateOne(ctx, user.ID, deleted.ID, "invoice.pdf", bytes.NewReader(testPDF))
+		testutils.AssertNoError(t, err)
+		keptReceipt, err := receiptService.CreateOne(ctx, user.ID, kept.ID, "invoice.pdf", bytes.NewReader(testPDF))
+		testutils.AssertNoError(t, err)
+
+		err = testutils.NewTestExpenseService(db).DeleteOneByID(ctx, user.ID, deleted.ID)
+		testutils.AssertNoError(t, err)
+
+		purged, err := trashService.PurgeDeleted(ctx, -time.Hour)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, purged, 1)
+
+		_, err = blobStore.Get(ctx, deletedReceipt.StorageKey)
+		if !errors.Is(err, blobstore.ErrNotFound) {
+			t.Errorf("expected error %q, got %v", blobstore.ErrNotFound, err)
+		}
+
+		file, err := blobStore.Get(ctx, keptReceipt.StorageKey)
+		testutils.AssertNoError(t, err)
+		file.Close()
+	})
+
+	t.Run("removes receipt files of purged vaults", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		trashService, receiptService, blobStore := newServices(t, db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		category := testutils.CreateTestExpenseCategory(t, db, user.ID, vault.ID)
+		expense := testutils.CreateTestExpense(t, db, user.ID, vault.ID, category.ID)
+
+		receipt, err := receiptService.CreateOne(ctx, user.ID, expense.ID, "invoice.pdf", bytes.NewReader(testPDF))
+		testutils.AssertNoError(t, err)
+
+		err = testutils.NewTestVaultService(db).DeleteOneByID(ctx, user.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+
+		purged, err := trashService.PurgeDeleted(ctx, time.Hour)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, purged, 0)
+
+		file, err := blobStore.Get(ctx, receipt.StorageKey)
+		testutils.AssertNoError(t, err)
+		file.Close()
+
+		purged, err = trashService.PurgeDeleted(ctx, -time.Hour)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, purged, 1)
+
+		_, err = blobStore.Get(ctx, receipt.StorageKey)
+		if !errors.Is(err, blobstore.ErrNotFound) {
+			t.Errorf("expected error %q, got %v", blobstore.ErrNotFound, err)
+		}
+	})
+}
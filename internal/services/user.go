@@ -8,6 +8,7 @@ import (
 	"github.com/kkstas/tr-backend/internal/auth"
 	"github.com/kkstas/tr-backend/internal/models"
 	"github.com/kkstas/tr-backend/internal/repositories"
+	"github.com/kkstas/tr-backend/internal/tracing"
 )
 
 var ErrUserNotFound = errors.New("user not found")
@@ -22,10 +23,16 @@ func NewUserService(userRepo *repositories.UserRepo) *UserService {
 }
 
 func (s *UserService) FindAll(ctx context.Context) ([]models.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.FindAll")
+	defer span.End()
+
 	return s.userRepo.FindAll(ctx)
 }
 
 func (s *UserService) CreateOne(ctx context.Context, firstName, lastName, email, password string) error {
+	ctx, span := tracing.Start(ctx, "UserService.CreateOne")
+	defer span.End()
+
 	passwordHash, err := auth.HashPassword(password)
 	if err != nil {
 		return fmt.Errorf("failed to hash password: %w", err)
@@ -43,6 +50,9 @@ func (s *UserService) CreateOne(ctx context.Context, firstName, lastName, email,
 }
 
 func (s *UserService) FindPasswordHashAndUserIDForEmail(ctx context.Context, email string) (passwordHash, userID string, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.FindPasswordHashAndUserIDForEmail")
+	defer span.End()
+
 	passwordHash, userID, err = s.userRepo.FindPasswordHashAndUserIDForEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, repositories.ErrUserNotFound) {
@@ -54,6 +64,9 @@ func (s *UserService) FindPasswordHashAndUserIDForEmail(ctx context.Context, ema
 }
 
 func (s *UserService) FindOneByID(ctx context.Context, id string) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.FindOneByID")
+	defer span.End()
+
 	user, err := s.userRepo.FindOneByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, repositories.ErrUserNotFound) {
@@ -66,6 +79,9 @@ func (s *UserService) FindOneByID(ctx context.Context, id string) (*models.User,
 }
 
 func (s *UserService) FindOneByEmail(ctx context.Context, email string) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.FindOneByEmail")
+	defer span.End()
+
 	user, err := s.userRepo.FindOneByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, repositories.ErrUserNotFound) {
@@ -78,5 +94,8 @@ func (s *UserService) FindOneByEmail(ctx context.Context, email string) (*models
 }
 
 func (s *UserService) AssignActiveVault(ctx context.Context, userID, vaultID string) error {
+	ctx, span := tracing.Start(ctx, "UserService.AssignActiveVault")
+	defer span.End()
+
 	return s.userRepo.AssignActiveVault(ctx, userID, vaultID)
 }
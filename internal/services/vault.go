@@ -4,26 +4,60 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/kkstas/tr-backend/internal/database"
 	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/permissions"
 	"github.com/kkstas/tr-backend/internal/repositories"
+	"github.com/kkstas/tr-backend/internal/tracing"
 )
 
 var ErrVaultNotFound = errors.New("vault not found")
 var ErrInsufficientVaultPermissions = errors.New("insufficient permissions to perform this vault operation")
 var ErrUserAlreadyAssignedToVault = errors.New("user is already assigned to this vault")
 var ErrVaultWithThatNameAlreadyExists = errors.New("vault with that name already exists")
+var ErrUserNotAssignedToVault = errors.New("user is not assigned to this vault")
+var ErrUserAlreadyVaultOwner = errors.New("user is already an owner of this vault")
 
 type VaultService struct {
-	vaultRepo   *repositories.VaultRepo
-	userService *UserService
+	db                *database.DB
+	vaultRepo         *repositories.VaultRepo
+	auditRepo         *repositories.AuditRepo
+	paymentMethodRepo *repositories.PaymentMethodRepo
+	userService       *UserService
 }
 
-func NewVaultService(vaultRepo *repositories.VaultRepo, userService *UserService) *VaultService {
-	return &VaultService{vaultRepo: vaultRepo, userService: userService}
+func NewVaultService(
+	db *database.DB,
+	vaultRepo *repositories.VaultRepo,
+	auditRepo *repositories.AuditRepo,
+	paymentMethodRepo *repositories.PaymentMethodRepo,
+	userService *UserService,
+) *VaultService {
+	return &VaultService{db: db, vaultRepo: vaultRepo, auditRepo: auditRepo, paymentMethodRepo: paymentMethodRepo, userService: userService}
 }
 
-func (s *VaultService) CreateOne(ctx context.Context, userID, vaultName string) error {
+type memberSnapshot struct {
+	UserID string           `json:"userID"`
+	Role   models.VaultRole `json:"role"`
+}
+
+// CreateOne creates a vault owned by the user. If templateName is not empty, categories from that
+// template, translated to locale, are created together with the vault.
+func (s *VaultService) CreateOne(ctx context.Context, userID, vaultName, templateName, locale string) error {
+	ctx, span := tracing.Start(ctx, "VaultService.CreateOne")
+	defer span.End()
+
+	var categories []models.CategoryTemplate
+	if templateName != "" {
+		template, err := findCategoryTemplate(templateName, locale)
+		if err != nil {
+			return err
+		}
+		categories = template.Categories
+	}
+
 	user, err := s.userService.FindOneByID(ctx, userID)
 	if err != nil {
 		return fmt.Errorf("failed to find user %s before creating vault: %w", userID, err)
@@ -37,26 +71,39 @@ func (s *VaultService) CreateOne(ctx context.Context, userID, vaultName string)
 		return fmt.Errorf("failed to find vault by name %q for user %q before creating one: %w", vaultName, user.ID, err)
 	}
 
-	vaultID, err := s.vaultRepo.CreateOne(ctx, userID, models.VaultRoleOwner, vaultName)
-	if err != nil {
-		return fmt.Errorf("failed to create new vault: %w", err)
-	}
+	return s.db.WithTx(ctx, func(ctx context.Context) error {
+		vaultID, err := s.vaultRepo.CreateOne(ctx, userID, models.VaultRoleOwner, vaultName, categories)
+		if err != nil {
+			return fmt.Errorf("failed to create new vault: %w", err)
+		}
+
+		if user.ActiveVault == "" {
+			err := s.userService.AssignActiveVault(ctx, userID, vaultID)
+			if err != nil {
+				return fmt.Errorf("failed to assign active vault %s to user %s after creating new vault: %w", vaultID, userID, err)
+			}
+		}
 
-	if user.ActiveVault == "" {
-		err := s.userService.AssignActiveVault(ctx, userID, vaultID)
+		vault, err := s.vaultRepo.FindOneByID(ctx, userID, vaultID)
 		if err != nil {
-			return fmt.Errorf("failed to assign active vault %s to user %s after creating new vault: %w", vaultID, userID, err)
+			return fmt.Errorf("failed to find vault %s after creating it: %w", vaultID, err)
 		}
-	}
 
-	return nil
+		return recordAuditEvent(ctx, s.auditRepo, auditEvent(vaultID, userID, models.AuditActionCreate, models.AuditEntityVault, vaultID), nil, vault)
+	})
 }
 
 func (s *VaultService) FindAll(ctx context.Context, userID string) ([]models.UserVaultWithRole, error) {
+	ctx, span := tracing.Start(ctx, "VaultService.FindAll")
+	defer span.End()
+
 	return s.vaultRepo.FindAll(ctx, userID)
 }
 
 func (s *VaultService) FindOneByID(ctx context.Context, userID, vaultID string) (*models.UserVaultWithRole, error) {
+	ctx, span := tracing.Start(ctx, "VaultService.FindOneByID")
+	defer span.End()
+
 	vault, err := s.vaultRepo.FindOneByID(ctx, userID, vaultID)
 	if err != nil {
 		if errors.Is(err, repositories.ErrVaultNotFound) {
@@ -67,7 +114,100 @@ func (s *VaultService) FindOneByID(ctx context.Context, userID, vaultID string)
 	return vault, nil
 }
 
+func (s *VaultService) UpdateOne(ctx context.Context, userID, vaultID string, update models.VaultUpdate) (*models.UserVaultWithRole, error) {
+	ctx, span := tracing.Start(ctx, "VaultService.UpdateOne")
+	defer span.End()
+
+	vault, err := s.FindOneByID(ctx, userID, vaultID)
+	if err != nil {
+		return nil, err
+	}
+	if !permissions.Can(vault.UserRole, permissions.ActionManageVault) {
+		return nil, ErrInsufficientVaultPermissions
+	}
+
+	before := *vault
+
+	renamed := update.Name != nil && *update.Name != vault.Name
+	if renamed {
+		vault.Name = *update.Name
+	}
+	if update.Description != nil {
+		vault.Description = *update.Description
+	}
+	if update.Icon != nil {
+		vault.Icon = *update.Icon
+	}
+	if update.Color != nil {
+		vault.Color = *update.Color
+	}
+	if update.BaseCurrency != nil {
+		vault.BaseCurrency = *update.BaseCurrency
+	}
+	checkPaymentMethod := false
+	if update.DefaultPaymentMethodID != nil && *update.DefaultPaymentMethodID != vault.DefaultPaymentMethodID {
+		checkPaymentMethod = *update.DefaultPaymentMethodID != ""
+		vault.DefaultPaymentMethodID = *update.DefaultPaymentMethodID
+	}
+
+	err = s.db.WithTx(ctx, func(ctx context.Context) error {
+		if renamed {
+			if err := s.checkNameAvailable(ctx, vault.ID, vault.Name); err != nil {
+				return err
+			}
+		}
+		if checkPaymentMethod {
+			if err := s.checkDefaultPaymentMethod(ctx, vault.ID, vault.DefaultPaymentMethodID); err != nil {
+				return err
+			}
+		}
+		if err := s.vaultRepo.UpdateOne(ctx, vault); err != nil {
+			return fmt.Errorf("failed to update vault %s as user %s: %w", vaultID, userID, err)
+		}
+		return recordAuditEvent(ctx, s.auditRepo, auditEvent(vault.ID, userID, models.AuditActionUpdate, models.AuditEntityVault, vault.ID), before, vault)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return vault, nil
+}
+
+// checkNameAvailable makes sure that no member of the vault has another vault with the name.
+func (s *VaultService) checkNameAvailable(ctx context.Context, vaultID, name string) error {
+	taken, err := s.vaultRepo.NameTakenByMember(ctx, vaultID, name)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrVaultWithThatNameAlreadyExists
+	}
+	return nil
+}
+
+// checkDefaultPaymentMethod makes sure that the payment method belongs to the vault and can
+// still be used for new expenses.
+func (s *VaultService) checkDefaultPaymentMethod(ctx context.Context, vaultID, paymentMethodID string) error {
+	paymentMethod, err := s.paymentMethodRepo.FindOneByID(ctx, paymentMethodID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrPaymentMethodNotFound) {
+			return ErrPaymentMethodNotFound
+		}
+		return fmt.Errorf("failed to find payment method %s: %w", paymentMethodID, err)
+	}
+	if paymentMethod.VaultID != vaultID {
+		return ErrPaymentMethodNotFound
+	}
+	if paymentMethod.Status != models.PaymentMethodStatusActive {
+		return ErrPaymentMethodArchived
+	}
+	return nil
+}
+
 func (s *VaultService) DeleteOneByID(ctx context.Context, userID, vaultID string) error {
+	ctx, span := tracing.Start(ctx, "VaultService.DeleteOneByID")
+	defer span.End()
+
 	foundVault, err := s.vaultRepo.FindOneByID(ctx, userID, vaultID)
 	if err != nil {
 		if errors.Is(err, repositories.ErrVaultNotFound) {
@@ -75,19 +215,43 @@ func (s *VaultService) DeleteOneByID(ctx context.Context, userID, vaultID string
 		}
 		return err
 	}
-	if foundVault.UserRole != models.VaultRoleOwner {
+	if !permissions.Can(foundVault.UserRole, permissions.ActionDeleteVault) {
 		return ErrInsufficientVaultPermissions
 	}
 
-	err = s.vaultRepo.DeleteOneByID(ctx, vaultID)
+	return s.db.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.vaultRepo.DeleteOneByID(ctx, vaultID); err != nil {
+			return fmt.Errorf("failed to delete vault %s as user %s: %w", vaultID, userID, err)
+		}
+		return recordAuditEvent(ctx, s.auditRepo, auditEvent(vaultID, userID, models.AuditActionDelete, models.AuditEntityVault, vaultID), foundVault, nil)
+	})
+}
+
+func (s *VaultService) FindMembers(ctx context.Context, userID, vaultID string) ([]models.VaultMember, error) {
+	ctx, span := tracing.Start(ctx, "VaultService.FindMembers")
+	defer span.End()
+
+	vault, err := s.FindOneByID(ctx, userID, vaultID)
 	if err != nil {
-		return fmt.Errorf("failed to delete vault %s as user %s: %w", vaultID, userID, err)
+		return nil, err
 	}
-	return nil
 
+	if !permissions.Can(vault.UserRole, permissions.ActionRead) {
+		return nil, ErrInsufficientVaultPermissions
+	}
+
+	members, err := s.vaultRepo.FindMembers(ctx, vault.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find members of vault %s: %w", vault.ID, err)
+	}
+
+	return members, nil
 }
 
 func (s *VaultService) AddUser(ctx context.Context, userID, invitedUserID, vaultID string, userRole models.VaultRole) error {
+	ctx, span := tracing.Start(ctx, "VaultService.AddUser")
+	defer span.End()
+
 	_, err := s.vaultRepo.FindOneByID(ctx, invitedUserID, vaultID)
 	if err == nil {
 		return ErrUserAlreadyAssignedToVault
@@ -101,9 +265,115 @@ func (s *VaultService) AddUser(ctx context.Context, userID, invitedUserID, vault
 		return fmt.Errorf("failed to find user vault: %w", err)
 	}
 
-	if userVaultWithRole.UserRole != models.VaultRoleOwner {
+	if !permissions.CanAssignRole(userVaultWithRole.UserRole, userRole) {
+		return ErrInsufficientVaultPermissions
+	}
+
+	return s.db.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.vaultRepo.AddUser(ctx, userVaultWithRole.ID, invitedUserID, userRole); err != nil {
+			return err
+		}
+		return recordAuditEvent(ctx, s.auditRepo, auditEvent(userVaultWithRole.ID, userID, models.AuditActionCreate, models.AuditEntityMember, invitedUserID),
+			nil, memberSnapshot{UserID: invitedUserID, Role: userRole})
+	})
+}
+
+func (s *VaultService) TransferOwnership(ctx context.Context, userID, newOwnerID, vaultID string, demoteTo models.VaultRole) error {
+	ctx, span := tracing.Start(ctx, "VaultService.TransferOwnership")
+	defer span.End()
+
+	userVaultWithRole, err := s.vaultRepo.FindOneByID(ctx, userID, vaultID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrVaultNotFound) {
+			return ErrVaultNotFound
+		}
+		return fmt.Errorf("failed to find user vault: %w", err)
+	}
+
+	if !permissions.Can(userVaultWithRole.UserRole, permissions.ActionTransferOwnership) {
+		return ErrInsufficientVaultPermissions
+	}
+
+	newOwnerVaultWithRole, err := s.vaultRepo.FindOneByID(ctx, newOwnerID, vaultID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrVaultNotFound) {
+			return ErrUserNotAssignedToVault
+		}
+		return fmt.Errorf("failed to find vault %s for new owner %s: %w", vaultID, newOwnerID, err)
+	}
+
+	if newOwnerVaultWithRole.UserRole == models.VaultRoleOwner {
+		return ErrUserAlreadyVaultOwner
+	}
+
+	if demoteTo == "" {
+		demoteTo = models.VaultRoleOwner
+	}
+
+	return s.db.WithTx(ctx, func(ctx context.Context) error {
+		err := s.vaultRepo.TransferOwnership(ctx, vaultID, userID, newOwnerID, demoteTo)
+		if err != nil {
+			return fmt.Errorf("failed to transfer ownership of vault %s from user %s to user %s: %w", vaultID, userID, newOwnerID, err)
+		}
+
+		err = recordAuditEvent(ctx, s.auditRepo, auditEvent(vaultID, userID, models.AuditActionUpdate, models.AuditEntityMember, newOwnerID),
+			memberSnapshot{UserID: newOwnerID, Role: newOwnerVaultWithRole.UserRole}, memberSnapshot{UserID: newOwnerID, Role: models.VaultRoleOwner})
+		if err != nil {
+			return err
+		}
+
+		if demoteTo == models.VaultRoleOwner {
+			return nil
+		}
+		return recordAuditEvent(ctx, s.auditRepo, auditEvent(vaultID, userID, models.AuditActionUpdate, models.AuditEntityMember, userID),
+			memberSnapshot{UserID: userID, Role: models.VaultRoleOwner}, memberSnapshot{UserID: userID, Role: demoteTo})
+	})
+}
+
+func (s *VaultService) FindAllDeleted(ctx context.Context, userID string, retention time.Duration) ([]models.UserVaultWithRole, error) {
+	ctx, span := tracing.Start(ctx, "VaultService.FindAllDeleted")
+	defer span.End()
+
+	vaults, err := s.vaultRepo.FindAllDeleted(ctx, userID, time.Now().Add(-retention))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find deleted vaults for user %s: %w", userID, err)
+	}
+
+	restorable := []models.UserVaultWithRole{}
+	for _, vault := range vaults {
+		if permissions.Can(vault.UserRole, permissions.ActionDeleteVault) {
+			restorable = append(restorable, vault)
+		}
+	}
+
+	return restorable, nil
+}
+
+func (s *VaultService) RestoreOneByID(ctx context.Context, userID, vaultID string, retention time.Duration) error {
+	ctx, span := tracing.Start(ctx, "VaultService.RestoreOneByID")
+	defer span.End()
+
+	foundVault, err := s.vaultRepo.FindOneDeletedByID(ctx, userID, vaultID, time.Now().Add(-retention))
+	if err != nil {
+		if errors.Is(err, repositories.ErrVaultNotFound) {
+			return ErrVaultNotFound
+		}
+		return fmt.Errorf("failed to find deleted vault %s for user %s: %w", vaultID, userID, err)
+	}
+	if !permissions.Can(foundVault.UserRole, permissions.ActionDeleteVault) {
 		return ErrInsufficientVaultPermissions
 	}
 
-	return s.vaultRepo.AddUser(ctx, userVaultWithRole.ID, invitedUserID, userRole)
+	before := *foundVault
+	foundVault.DeletedAt = ""
+
+	return s.db.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.checkNameAvailable(ctx, vaultID, foundVault.Name); err != nil {
+			return err
+		}
+		if err := s.vaultRepo.RestoreOneByID(ctx, vaultID); err != nil {
+			return fmt.Errorf("failed to restore vault %s as user %s: %w", vaultID, userID, err)
+		}
+		return recordAuditEvent(ctx, s.auditRepo, auditEvent(vaultID, userID, models.AuditActionRestore, models.AuditEntityVault, vaultID), before, foundVault)
+	})
 }
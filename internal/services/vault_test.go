@@ -3,9 +3,15 @@ package services_test
 import (
 	"context"
 	"errors"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/kkstas/tr-backend/internal/models"
 	"github.com/kkstas/tr-backend/internal/services"
 	"github.com/kkstas/tr-backend/internal/testutils"
@@ -23,7 +29,7 @@ func TestVaultService_FindOneByID(t *testing.T) {
 
 		vaultName := "some vault"
 
-		err := vaultService.CreateOne(ctx, createdUser.ID, vaultName)
+		err := vaultService.CreateOne(ctx, createdUser.ID, vaultName, "", "")
 		testutils.AssertNoError(t, err)
 
 		vaults, err := vaultService.FindAll(ctx, createdUser.ID)
@@ -70,7 +76,7 @@ func TestVaultService_CreateOne(t *testing.T) {
 
 		vaultName := "some vault"
 
-		err := vaultService.CreateOne(ctx, createdUser.ID, vaultName)
+		err := vaultService.CreateOne(ctx, createdUser.ID, vaultName, "", "")
 		testutils.AssertNoError(t, err)
 
 		vaults, err := vaultService.FindAll(ctx, createdUser.ID)
@@ -101,10 +107,10 @@ func TestVaultService_CreateOne(t *testing.T) {
 
 		vaultName := "some vault"
 
-		err := vaultService.CreateOne(ctx, createdUser.ID, vaultName)
+		err := vaultService.CreateOne(ctx, createdUser.ID, vaultName, "", "")
 		testutils.AssertNoError(t, err)
 
-		err = vaultService.CreateOne(ctx, createdUser.ID, vaultName)
+		err = vaultService.CreateOne(ctx, createdUser.ID, vaultName, "", "")
 		if err == nil {
 			t.Error("expected an error but didn't get one")
 		}
@@ -114,6 +120,43 @@ func TestVaultService_CreateOne(t *testing.T) {
 			t.Errorf("expected error %q, got %v", want, err)
 		}
 	})
+
+	t.Run("creates categories from chosen template in given locale", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		vaultService := testutils.NewTestVaultService(db)
+		createdUser := testutils.CreateTestUser(t, db)
+
+		err := vaultService.CreateOne(ctx, createdUser.ID, "some vault", "household", "pl-PL")
+		testutils.AssertNoError(t, err)
+
+		vaults, err := vaultService.FindAll(ctx, createdUser.ID)
+		testutils.AssertNoError(t, err)
+
+		tree, err := testutils.NewTestExpenseCategoryService(db).FindTree(ctx, createdUser.ID, vaults[0].ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, tree[0].Name, "Mieszkanie")
+		testutils.AssertEqual(t, tree[0].Children[0].Name, "Czynsz")
+	})
+
+	t.Run("does not create vault if template does not exist", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		vaultService := testutils.NewTestVaultService(db)
+		createdUser := testutils.CreateTestUser(t, db)
+
+		err := vaultService.CreateOne(ctx, createdUser.ID, "some vault", "unknown", "en")
+		want := services.ErrCategoryTemplateNotFound
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+
+		vaults, err := vaultService.FindAll(ctx, createdUser.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(vaults), 0)
+	})
 }
 
 func TestVaultService_DeleteOneByID(t *testing.T) {
@@ -127,7 +170,7 @@ func TestVaultService_DeleteOneByID(t *testing.T) {
 		userService := testutils.NewTestUserService(db)
 		vaultService := testutils.NewTestVaultService(db)
 
-		err := vaultService.CreateOne(ctx, createdUser.ID, "vault name")
+		err := vaultService.CreateOne(ctx, createdUser.ID, "vault name", "", "")
 		testutils.AssertNoError(t, err)
 
 		foundVaults, err := vaultService.FindAll(ctx, createdUser.ID)
@@ -165,7 +208,7 @@ func TestVaultService_AddUser(t *testing.T) {
 		invitee := testutils.CreateTestUser(t, db)
 		vaultService := testutils.NewTestVaultService(db)
 
-		err := vaultService.CreateOne(ctx, vaultOwner.ID, "vault name")
+		err := vaultService.CreateOne(ctx, vaultOwner.ID, "vault name", "", "")
 		testutils.AssertNoError(t, err)
 
 		foundVaults, err := vaultService.FindAll(ctx, vaultOwner.ID)
@@ -192,7 +235,7 @@ func TestVaultService_AddUser(t *testing.T) {
 		invitee := testutils.CreateTestUser(t, db)
 		vaultService := testutils.NewTestVaultService(db)
 
-		err := vaultService.CreateOne(ctx, vaultOwner.ID, "vault name")
+		err := vaultService.CreateOne(ctx, vaultOwner.ID, "vault name", "", "")
 		testutils.AssertNoError(t, err)
 
 		foundVaults, err := vaultService.FindAll(ctx, vaultOwner.ID)
@@ -213,6 +256,31 @@ func TestVaultService_AddUser(t *testing.T) {
 		}
 	})
 
+	t.Run("allows admin to add members but not owners", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		vaultService := testutils.NewTestVaultService(db)
+		_, vaultOwner, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		admin := testutils.CreateTestUser(t, db)
+		invitee := testutils.CreateTestUser(t, db)
+
+		err := vaultService.AddUser(ctx, vaultOwner.ID, admin.ID, vault.ID, models.VaultRoleAdmin)
+		testutils.AssertNoError(t, err)
+
+		err = vaultService.AddUser(ctx, admin.ID, invitee.ID, vault.ID, models.VaultRoleOwner)
+		if !errors.Is(err, services.ErrInsufficientVaultPermissions) {
+			t.Errorf("expected error %q, got %v", services.ErrInsufficientVaultPermissions, err)
+		}
+
+		err = vaultService.AddUser(ctx, admin.ID, invitee.ID, vault.ID, models.VaultRoleViewer)
+		testutils.AssertNoError(t, err)
+
+		inviteeVault, err := vaultService.FindOneByID(ctx, invitee.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, inviteeVault.UserRole, models.VaultRoleViewer)
+	})
+
 	t.Run("returns error if inviter does not belong to this vault", func(t *testing.T) {
 		t.Parallel()
 		ctx := context.Background()
@@ -222,7 +290,7 @@ func TestVaultService_AddUser(t *testing.T) {
 		invitee := testutils.CreateTestUser(t, db)
 		vaultService := testutils.NewTestVaultService(db)
 
-		err := vaultService.CreateOne(ctx, vaultOwner.ID, "vault name")
+		err := vaultService.CreateOne(ctx, vaultOwner.ID, "vault name", "", "")
 		testutils.AssertNoError(t, err)
 
 		foundVaults, err := vaultService.FindAll(ctx, vaultOwner.ID)
@@ -240,3 +308,331 @@ func TestVaultService_AddUser(t *testing.T) {
 		}
 	})
 }
+
+func TestVaultService_TransferOwnership(t *testing.T) {
+	t.Parallel()
+
+	t.Run("transfers ownership and keeps previous owner as owner if no demotion role is given", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		vaultService := testutils.NewTestVaultService(db)
+		_, owner, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		member := testutils.CreateTestUser(t, db)
+
+		err := vaultService.AddUser(ctx, owner.ID, member.ID, vault.ID, models.VaultRoleEditor)
+		testutils.AssertNoError(t, err)
+
+		err = vaultService.TransferOwnership(ctx, owner.ID, member.ID, vault.ID, "")
+		testutils.AssertNoError(t, err)
+
+		memberVault, err := vaultService.FindOneByID(ctx, member.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, memberVault.UserRole, models.VaultRoleOwner)
+
+		ownerVault, err := vaultService.FindOneByID(ctx, owner.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, ownerVault.UserRole, models.VaultRoleOwner)
+	})
+
+	t.Run("returns error if user is not an owner", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		vaultService := testutils.NewTestVaultService(db)
+		_, owner, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		admin := testutils.CreateTestUser(t, db)
+
+		err := vaultService.AddUser(ctx, owner.ID, admin.ID, vault.ID, models.VaultRoleAdmin)
+		testutils.AssertNoError(t, err)
+
+		err = vaultService.TransferOwnership(ctx, admin.ID, admin.ID, vault.ID, "")
+		want := services.ErrInsufficientVaultPermissions
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+
+	t.Run("returns error if new owner is not assigned to the vault", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		vaultService := testutils.NewTestVaultService(db)
+		_, owner, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		outsider := testutils.CreateTestUser(t, db)
+
+		err := vaultService.TransferOwnership(ctx, owner.ID, outsider.ID, vault.ID, models.VaultRoleEditor)
+		want := services.ErrUserNotAssignedToVault
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+}
+
+func TestVaultService_RestoreOneByID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("restores deleted vault within retention window", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		vaultService := testutils.NewTestVaultService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+
+		err := vaultService.DeleteOneByID(ctx, user.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+
+		deletedVaults, err := vaultService.FindAllDeleted(ctx, user.ID, time.Hour)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(deletedVaults), 1)
+
+		err = vaultService.RestoreOneByID(ctx, user.ID, vault.ID, time.Hour)
+		testutils.AssertNoError(t, err)
+
+		_, err = vaultService.FindOneByID(ctx, user.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+	})
+
+	t.Run("gives restored vault back to members who had it active", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		userService := testutils.NewTestUserService(db)
+		vaultService := testutils.NewTestVaultService(db)
+		_, owner, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		member := testutils.CreateTestUser(t, db)
+
+		err := vaultService.AddUser(ctx, owner.ID, member.ID, vault.ID, models.VaultRoleEditor)
+		testutils.AssertNoError(t, err)
+		for _, userID := range []string{owner.ID, member.ID} {
+			err = userService.AssignActiveVault(ctx, userID, vault.ID)
+			testutils.AssertNoError(t, err)
+		}
+
+		err = vaultService.DeleteOneByID(ctx, owner.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+
+		for _, userID := range []string{owner.ID, member.ID} {
+			user, err := userService.FindOneByID(ctx, userID)
+			testutils.AssertNoError(t, err)
+			testutils.AssertEqual(t, user.ActiveVault, "")
+		}
+
+		err = vaultService.RestoreOneByID(ctx, owner.ID, vault.ID, time.Hour)
+		testutils.AssertNoError(t, err)
+
+		for _, userID := range []string{owner.ID, member.ID} {
+			user, err := userService.FindOneByID(ctx, userID)
+			testutils.AssertNoError(t, err)
+			testutils.AssertEqual(t, user.ActiveVault, vault.ID)
+		}
+	})
+
+	t.Run("returns error if vault was deleted outside of retention window", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		vaultService := testutils.NewTestVaultService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+
+		err := vaultService.DeleteOneByID(ctx, user.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+
+		err = vaultService.RestoreOneByID(ctx, user.ID, vault.ID, -time.Hour)
+		want := services.ErrVaultNotFound
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+
+	t.Run("returns error if user is not allowed to delete the vault", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		vaultService := testutils.NewTestVaultService(db)
+		_, owner, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		editor := testutils.CreateTestUser(t, db)
+
+		err := vaultService.AddUser(ctx, owner.ID, editor.ID, vault.ID, models.VaultRoleEditor)
+		testutils.AssertNoError(t, err)
+		err = vaultService.DeleteOneByID(ctx, owner.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+
+		deletedVaults, err := vaultService.FindAllDeleted(ctx, editor.ID, time.Hour)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, len(deletedVaults), 0)
+
+		err = vaultService.RestoreOneByID(ctx, editor.ID, vault.ID, time.Hour)
+		want := services.ErrInsufficientVaultPermissions
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+
+	t.Run("returns error if active vault with the same name already exists", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		vaultService := testutils.NewTestVaultService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+
+		err := vaultService.DeleteOneByID(ctx, user.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		err = vaultService.CreateOne(ctx, user.ID, vault.Name, "", "")
+		testutils.AssertNoError(t, err)
+
+		err = vaultService.RestoreOneByID(ctx, user.ID, vault.ID, time.Hour)
+		want := services.ErrVaultWithThatNameAlreadyExists
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+}
+
+func TestVaultService_UpdateOne(t *testing.T) {
+	t.Parallel()
+
+	t.Run("updates only provided fields", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		vaultService := testutils.NewTestVaultService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+
+		description := "shared flat"
+		currency := "PLN"
+		updated, err := vaultService.UpdateOne(ctx, user.ID, vault.ID, models.VaultUpdate{ // nolint: exhaustruct
+			Description:  &description,
+			BaseCurrency: &currency,
+		})
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, updated.Name, vault.Name)
+		testutils.AssertEqual(t, updated.Description, description)
+		testutils.AssertEqual(t, updated.BaseCurrency, currency)
+
+		found, err := vaultService.FindOneByID(ctx, user.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, *found, *updated)
+	})
+
+	t.Run("returns error if user already has vault with new name", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		vaultService := testutils.NewTestVaultService(db)
+		_, user, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+
+		otherName := "other vault"
+		err := vaultService.CreateOne(ctx, user.ID, otherName, "", "")
+		testutils.AssertNoError(t, err)
+
+		_, err = vaultService.UpdateOne(ctx, user.ID, vault.ID, models.VaultUpdate{Name: &otherName}) // nolint: exhaustruct
+		want := services.ErrVaultWithThatNameAlreadyExists
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+
+	t.Run("returns error if another member already has vault with new name", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		vaultService := testutils.NewTestVaultService(db)
+		_, owner, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		member := testutils.CreateTestUser(t, db)
+
+		err := vaultService.AddUser(ctx, owner.ID, member.ID, vault.ID, models.VaultRoleEditor)
+		testutils.AssertNoError(t, err)
+		memberVaultName := "member's vault"
+		err = vaultService.CreateOne(ctx, member.ID, memberVaultName, "", "")
+		testutils.AssertNoError(t, err)
+
+		_, err = vaultService.UpdateOne(ctx, owner.ID, vault.ID, models.VaultUpdate{Name: &memberVaultName}) // nolint: exhaustruct
+		want := services.ErrVaultWithThatNameAlreadyExists
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+
+		found, err := vaultService.FindOneByID(ctx, owner.ID, vault.ID)
+		testutils.AssertNoError(t, err)
+		testutils.AssertEqual(t, found.Name, vault.Name)
+	})
+
+	t.Run("returns error if user cannot manage the vault", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		db := testutils.OpenTestDB(t, ctx)
+		vaultService := testutils.NewTestVaultService(db)
+		_, owner, vault := testutils.CreateTestUserWithTokenAndVault(t, db)
+		editor := testutils.CreateTestUser(t, db)
+
+		err := vaultService.AddUser(ctx, owner.ID, editor.ID, vault.ID, models.VaultRoleEditor)
+		testutils.AssertNoError(t, err)
+
+		newName := "new name"
+		_, err = vaultService.UpdateOne(ctx, editor.ID, vault.ID, models.VaultUpdate{Name: &newName}) // nolint: exhaustruct
+		want := services.ErrInsufficientVaultPermissions
+		if !errors.Is(err, want) {
+			t.Errorf("expected error %q, got %v", want, err)
+		}
+	})
+}
+
+func TestVaultService_CreateOne_Tracing(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := testutils.OpenTestDB(t, ctx)
+	vaultService := testutils.NewTestVaultService(db)
+	createdUser := testutils.CreateTestUser(t, db)
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ctx, root := provider.Tracer("test").Start(ctx, "root")
+	err := vaultService.CreateOne(ctx, createdUser.ID, "traced vault", "", "")
+	root.End()
+	testutils.AssertNoError(t, err)
+
+	spans := recorder.Ended()
+	byID := map[trace.SpanID]sdktrace.ReadOnlySpan{}
+	for _, span := range spans {
+		byID[span.SpanContext().SpanID()] = span
+	}
+	parentName := func(span sdktrace.ReadOnlySpan) string {
+		if parent, ok := byID[span.Parent().SpanID()]; ok {
+			return parent.Name()
+		}
+		return ""
+	}
+
+	var createSpan sdktrace.ReadOnlySpan
+	var children []string
+	var queries int
+	for _, span := range spans {
+		if span.Name() == "VaultService.CreateOne" {
+			createSpan = span
+		}
+		if parentName(span) == "VaultService.CreateOne" {
+			children = append(children, span.Name())
+		}
+		if span.Name() == "sql.query" || span.Name() == "sql.exec" {
+			queries++
+			if parentName(span) == "" {
+				t.Errorf("expected %s span to have a recorded parent", span.Name())
+			}
+		}
+	}
+
+	if createSpan == nil {
+		t.Fatal("expected VaultService.CreateOne span")
+	}
+	testutils.AssertEqual(t, parentName(createSpan), "root")
+	if !slices.Contains(children, "UserService.FindOneByID") {
+		t.Errorf("expected UserService.FindOneByID to be a child of VaultService.CreateOne, got %v", children)
+	}
+	if !slices.Contains(children, "sql.query") {
+		t.Errorf("expected repository queries to be children of VaultService.CreateOne, got %v", children)
+	}
+	if queries == 0 {
+		t.Error("expected sql spans")
+	}
+}
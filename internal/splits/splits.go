@@ -0,0 +1,162 @@
+package splits
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/kkstas/tr-backend/internal/models"
+)
+
+var ErrInvalidSplit = errors.New("invalid split")
+
+// Compute divides amount between participants according to method. All calculations are done
+// in cents, and cents that cannot be divided evenly go to participants with the largest remainders.
+func Compute(amount float64, method models.SplitMethod, shares []models.SplitShare) ([]models.ExpenseSplit, error) {
+	if len(shares) == 0 {
+		return nil, fmt.Errorf("%w: at least one participant is required", ErrInvalidSplit)
+	}
+
+	seen := make(map[string]bool, len(shares))
+	for _, share := range shares {
+		if share.UserID == "" {
+			return nil, fmt.Errorf("%w: participant user ID is required", ErrInvalidSplit)
+		}
+		if seen[share.UserID] {
+			return nil, fmt.Errorf("%w: participant %s appears more than once", ErrInvalidSplit, share.UserID)
+		}
+		seen[share.UserID] = true
+		if method != models.SplitMethodEqual && share.Value <= 0 {
+			return nil, fmt.Errorf("%w: split values must be positive", ErrInvalidSplit)
+		}
+	}
+
+	total := toCents(amount)
+	weights := make([]float64, len(shares))
+
+	switch method {
+	case models.SplitMethodEqual:
+		for i := range shares {
+			weights[i] = 1
+		}
+	case models.SplitMethodPercent:
+		var sum float64
+		for i, share := range shares {
+			weights[i] = share.Value
+			sum += share.Value
+		}
+		if math.Abs(sum-100) > 1e-6 {
+			return nil, fmt.Errorf("%w: percentages must add up to 100", ErrInvalidSplit)
+		}
+	case models.SplitMethodShares:
+		for i, share := range shares {
+			weights[i] = share.Value
+		}
+	case models.SplitMethodExact:
+		var sum int64
+		for i, share := range shares {
+			weights[i] = float64(toCents(share.Value))
+			sum += toCents(share.Value)
+		}
+		if sum != total {
+			return nil, fmt.Errorf("%w: exact amounts must add up to %.2f", ErrInvalidSplit, amount)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown split method %q", ErrInvalidSplit, method)
+	}
+
+	cents := allocate(total, weights)
+
+	result := make([]models.ExpenseSplit, len(shares))
+	for i, share := range shares {
+		result[i] = models.ExpenseSplit{UserID: share.UserID, Amount: fromCents(cents[i]), Share: share.Value}
+	}
+	return result, nil
+}
+
+// Settle suggests transfers that bring all balances to zero, greedily matching the largest
+// debtor with the largest creditor. Members with zero balance are left out.
+func Settle(balances []models.MemberBalance) []models.SettlementSuggestion {
+	type member struct {
+		userID string
+		cents  int64
+	}
+
+	var creditors, debtors []member
+	for _, balance := range balances {
+		cents := toCents(balance.Balance)
+		if cents > 0 {
+			creditors = append(creditors, member{userID: balance.UserID, cents: cents})
+		} else if cents < 0 {
+			debtors = append(debtors, member{userID: balance.UserID, cents: -cents})
+		}
+	}
+
+	byAmountDesc := func(a, b member) int {
+		if c := cmp.Compare(b.cents, a.cents); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.userID, b.userID)
+	}
+	slices.SortFunc(creditors, byAmountDesc)
+	slices.SortFunc(debtors, byAmountDesc)
+
+	suggestions := []models.SettlementSuggestion{}
+	for i, j := 0, 0; i < len(debtors) && j < len(creditors); {
+		amount := min(debtors[i].cents, creditors[j].cents)
+		suggestions = append(suggestions, models.SettlementSuggestion{
+			FromUserID: debtors[i].userID,
+			ToUserID:   creditors[j].userID,
+			Amount:     fromCents(amount),
+		})
+		debtors[i].cents -= amount
+		creditors[j].cents -= amount
+		if debtors[i].cents == 0 {
+			i++
+		}
+		if creditors[j].cents == 0 {
+			j++
+		}
+	}
+	return suggestions
+}
+
+func allocate(total int64, weights []float64) []int64 {
+	var sum float64
+	for _, weight := range weights {
+		sum += weight
+	}
+
+	cents := make([]int64, len(weights))
+	remainders := make([]float64, len(weights))
+	allocated := int64(0)
+	for i, weight := range weights {
+		exact := float64(total) * weight / sum
+		cents[i] = int64(math.Floor(exact))
+		remainders[i] = exact - float64(cents[i])
+		allocated += cents[i]
+	}
+
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(remainders[b], remainders[a])
+	})
+	for k := 0; allocated < total; k++ {
+		cents[order[k%len(order)]]++
+		allocated++
+	}
+	return cents
+}
+
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+func fromCents(cents int64) float64 {
+	return float64(cents) / 100
+}
@@ -0,0 +1,120 @@
+package splits_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/splits"
+	"github.com/kkstas/tr-backend/internal/testutils"
+)
+
+func TestCompute(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		amount float64
+		method models.SplitMethod
+		shares []models.SplitShare
+		want   []models.ExpenseSplit
+	}{
+		{
+			name:   "equal split distributes leftover cents",
+			amount: 100,
+			method: models.SplitMethodEqual,
+			shares: []models.SplitShare{{UserID: "a"}, {UserID: "b"}, {UserID: "c"}},
+			want:   []models.ExpenseSplit{{UserID: "a", Amount: 33.34}, {UserID: "b", Amount: 33.33}, {UserID: "c", Amount: 33.33}},
+		},
+		{
+			name:   "percent split",
+			amount: 80,
+			method: models.SplitMethodPercent,
+			shares: []models.SplitShare{{UserID: "a", Value: 75}, {UserID: "b", Value: 25}},
+			want:   []models.ExpenseSplit{{UserID: "a", Amount: 60, Share: 75}, {UserID: "b", Amount: 20, Share: 25}},
+		},
+		{
+			name:   "exact split",
+			amount: 50.5,
+			method: models.SplitMethodExact,
+			shares: []models.SplitShare{{UserID: "a", Value: 20.25}, {UserID: "b", Value: 30.25}},
+			want:   []models.ExpenseSplit{{UserID: "a", Amount: 20.25, Share: 20.25}, {UserID: "b", Amount: 30.25, Share: 30.25}},
+		},
+		{
+			name:   "shares split",
+			amount: 10,
+			method: models.SplitMethodShares,
+			shares: []models.SplitShare{{UserID: "a", Value: 1}, {UserID: "b", Value: 2}},
+			want:   []models.ExpenseSplit{{UserID: "a", Amount: 3.33, Share: 1}, {UserID: "b", Amount: 6.67, Share: 2}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := splits.Compute(tc.amount, tc.method, tc.shares)
+			testutils.AssertNoError(t, err)
+			testutils.AssertEqual(t, len(got), len(tc.want))
+			for i := range tc.want {
+				testutils.AssertEqual(t, got[i], tc.want[i])
+			}
+		})
+	}
+}
+
+func TestComputeInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		method models.SplitMethod
+		shares []models.SplitShare
+	}{
+		{name: "no participants", method: models.SplitMethodEqual, shares: nil},
+		{name: "duplicated participant", method: models.SplitMethodEqual, shares: []models.SplitShare{{UserID: "a"}, {UserID: "a"}}},
+		{name: "percentages not adding up to 100", method: models.SplitMethodPercent, shares: []models.SplitShare{{UserID: "a", Value: 50}, {UserID: "b", Value: 40}}},
+		{name: "exact amounts not adding up to total", method: models.SplitMethodExact, shares: []models.SplitShare{{UserID: "a", Value: 50}, {UserID: "b", Value: 40}}},
+		{name: "non positive shares", method: models.SplitMethodShares, shares: []models.SplitShare{{UserID: "a", Value: 0}, {UserID: "b", Value: 1}}},
+		{name: "unknown method", method: models.SplitMethod("random"), shares: []models.SplitShare{{UserID: "a", Value: 1}}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := splits.Compute(100, tc.method, tc.shares)
+			want := splits.ErrInvalidSplit
+			if !errors.Is(err, want) {
+				t.Errorf("expected error %q, got %v", want, err)
+			}
+		})
+	}
+}
+
+func TestSettle(t *testing.T) {
+	t.Parallel()
+
+	t.Run("suggests minimal transfers", func(t *testing.T) {
+		t.Parallel()
+		got := splits.Settle([]models.MemberBalance{
+			{UserID: "a", Balance: 60},  // nolint: exhaustruct
+			{UserID: "b", Balance: -40}, // nolint: exhaustruct
+			{UserID: "c", Balance: -20}, // nolint: exhaustruct
+			{UserID: "d", Balance: 0},   // nolint: exhaustruct
+		})
+
+		want := []models.SettlementSuggestion{
+			{FromUserID: "b", ToUserID: "a", Amount: 40},
+			{FromUserID: "c", ToUserID: "a", Amount: 20},
+		}
+		testutils.AssertEqual(t, len(got), len(want))
+		for i := range want {
+			testutils.AssertEqual(t, got[i], want[i])
+		}
+	})
+
+	t.Run("returns no suggestions when everyone is settled", func(t *testing.T) {
+		t.Parallel()
+		got := splits.Settle([]models.MemberBalance{{UserID: "a", Balance: 0}}) // nolint: exhaustruct
+		testutils.AssertEqual(t, len(got), 0)
+	})
+}
@@ -3,10 +3,11 @@ package testutils
 import (
 	"bytes"
 	"context"
-	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"log/slog"
 	"math/rand"
 	"net/http"
@@ -15,41 +16,55 @@ import (
 	"testing"
 	"time"
 
+	"go.opentelemetry.io/otel/trace/noop"
 	_ "modernc.org/sqlite" // nolint: revive
 
 	"github.com/kkstas/tr-backend/internal/app"
 	"github.com/kkstas/tr-backend/internal/auth"
+	"github.com/kkstas/tr-backend/internal/blobstore"
 	"github.com/kkstas/tr-backend/internal/config"
 	"github.com/kkstas/tr-backend/internal/database"
 	"github.com/kkstas/tr-backend/internal/models"
+	"github.com/kkstas/tr-backend/internal/problem"
 	"github.com/kkstas/tr-backend/internal/repositories"
 	"github.com/kkstas/tr-backend/internal/services"
 )
 
 var jwtKey = []byte("secret-key")
 
-func NewTestAppWithConfig(t testing.TB, config *config.Config) (newApp http.Handler, db *sql.DB) {
+const TestReceiptMaxSize = 1 << 20
+
+func NewTestAppWithConfig(t testing.TB, config *config.Config) (newApp http.Handler, db *database.DB) {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 
 	db = OpenTestDB(t, ctx)
 	t.Cleanup(cancel)
 
+	blobStore, err := blobstore.NewLocalStore(t.TempDir())
+	AssertNoError(t, err)
+
 	// nolint: exhaustruct
-	newApp = app.NewApplication(config, db, slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelWarn})))
+	newApp = app.NewApplication(config, db, slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelWarn})), blobStore, noop.NewTracerProvider())
 
-	return newApp, db
+	return validateResponses(t, newApp), db
+}
 
+func NewTestApplication(t testing.TB) (newApp http.Handler, db *database.DB) {
+	return NewTestAppWithConfig(t, NewTestConfig())
 }
 
-func NewTestApplication(t testing.TB) (newApp http.Handler, db *sql.DB) {
-	config := &config.Config{
+// NewTestConfig returns the config used by NewTestApplication, whose JWT key matches
+// tokens created by CreateTestUserWithToken.
+func NewTestConfig() *config.Config {
+	return &config.Config{ // nolint: exhaustruct
 		EnableRegister: true,
 		JWTSecretKey:   jwtKey,
+		TrashRetention: 30 * 24 * time.Hour,
+		ReceiptMaxSize: TestReceiptMaxSize,
 	}
-	return NewTestAppWithConfig(t, config)
 }
 
-func OpenTestDB(t testing.TB, ctx context.Context) (db *sql.DB) { // nolint: revive
+func OpenTestDB(t testing.TB, ctx context.Context) (db *database.DB) { // nolint: revive
 	dbName := fmt.Sprintf("test-%s.db", RandomString(32))
 	db, err := database.OpenDB(ctx, dbName)
 	if err != nil {
@@ -61,24 +76,86 @@ func OpenTestDB(t testing.TB, ctx context.Context) (db *sql.DB) { // nolint: rev
 		if err := os.Remove(dbName); err != nil {
 			t.Fatalf("failed to remove test database file %s: %v", dbName, err)
 		}
+		for _, suffix := range []string{"-wal", "-shm"} {
+			if err := os.Remove(dbName + suffix); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				t.Fatalf("failed to remove test database file %s: %v", dbName+suffix, err)
+			}
+		}
 	})
 
 	return db
 }
 
-func NewTestUserService(db *sql.DB) *services.UserService {
+func NewTestUserService(db *database.DB) *services.UserService {
 	return services.NewUserService(repositories.NewUserRepo(db))
 }
 
-func NewTestVaultService(db *sql.DB) *services.VaultService {
-	return services.NewVaultService(repositories.NewVaultRepo(db), NewTestUserService(db))
+func NewTestVaultService(db *database.DB) *services.VaultService {
+	return services.NewVaultService(db, repositories.NewVaultRepo(db), repositories.NewAuditRepo(db), repositories.NewPaymentMethodRepo(db), NewTestUserService(db))
+}
+
+func NewTestAuditService(db *database.DB) *services.AuditService {
+	return services.NewAuditService(repositories.NewAuditRepo(db), NewTestVaultService(db))
+}
+
+func NewTestExpenseCategoryService(db *database.DB) *services.ExpenseCategoryService {
+	return services.NewExpenseCategoryService(db, repositories.NewExpenseCategoryRepo(db), repositories.NewAuditRepo(db), NewTestVaultService(db))
+}
+
+func NewTestPaymentMethodService(db *database.DB) *services.PaymentMethodService {
+	return services.NewPaymentMethodService(repositories.NewPaymentMethodRepo(db), NewTestVaultService(db))
+}
+
+func NewTestAccountService(db *database.DB) *services.AccountService {
+	return services.NewAccountService(repositories.NewAccountRepo(db), NewTestVaultService(db))
 }
 
-func NewTestExpenseCategoryService(db *sql.DB) *services.ExpenseCategoryService {
-	return services.NewExpenseCategoryService(repositories.NewExpenseCategoryRepo(db), NewTestVaultService(db))
+func NewTestTransferService(db *database.DB) *services.TransferService {
+	return services.NewTransferService(repositories.NewTransferRepo(db), NewTestVaultService(db), NewTestAccountService(db))
+}
+
+func NewTestTagService(db *database.DB) *services.TagService {
+	return services.NewTagService(repositories.NewTagRepo(db), NewTestVaultService(db))
+}
+
+func NewTestIncomeService(db *database.DB) *services.IncomeService {
+	return services.NewIncomeService(repositories.NewIncomeRepo(db), NewTestVaultService(db), NewTestAccountService(db))
+}
+
+func NewTestExpenseService(db *database.DB) *services.ExpenseService {
+	return services.NewExpenseService(
+		db,
+		repositories.NewExpenseRepo(db),
+		repositories.NewAuditRepo(db),
+		NewTestVaultService(db),
+		NewTestExpenseCategoryService(db),
+		NewTestPaymentMethodService(db),
+		NewTestAccountService(db),
+		NewTestTagService(db),
+	)
+}
+
+func NewTestSettlementService(db *database.DB) *services.SettlementService {
+	return services.NewSettlementService(repositories.NewSettlementRepo(db), NewTestVaultService(db))
+}
+
+func NewTestReportService(db *database.DB) *services.ReportService {
+	return services.NewReportService(
+		repositories.NewReportRepo(db),
+		NewTestVaultService(db),
+		NewTestExpenseCategoryService(db),
+		NewTestPaymentMethodService(db),
+		NewTestTagService(db),
+	)
+}
+
+func NewTestReceiptService(t testing.TB, db *database.DB) *services.ReceiptService {
+	blobStore, err := blobstore.NewLocalStore(t.TempDir())
+	AssertNoError(t, err)
+	return services.NewReceiptService(repositories.NewReceiptRepo(db), NewTestExpenseService(db), blobStore, TestReceiptMaxSize)
 }
 
-func CreateTestUser(t testing.TB, db *sql.DB) *models.User {
+func CreateTestUser(t testing.TB, db *database.DB) *models.User {
 	userRepo := repositories.NewUserRepo(db)
 	userEmail := RandomString(16) + "@email.com"
 	err := userRepo.CreateOne(context.Background(), "firstName_"+RandomString(8), "lastName_"+RandomString(8), userEmail, "password")
@@ -89,7 +166,7 @@ func CreateTestUser(t testing.TB, db *sql.DB) *models.User {
 	return createdUser
 }
 
-func CreateTestUserWithToken(t testing.TB, db *sql.DB) (token string, user *models.User) {
+func CreateTestUserWithToken(t testing.TB, db *database.DB) (token string, user *models.User) {
 	createdUser := CreateTestUser(t, db)
 
 	tkn, err := auth.CreateToken(jwtKey, createdUser.ID)
@@ -98,15 +175,15 @@ func CreateTestUserWithToken(t testing.TB, db *sql.DB) (token string, user *mode
 	return tkn.Token, createdUser
 }
 
-func CreateTestUserWithTokenAndVault(t testing.TB, db *sql.DB) (token string, user *models.User, vault *models.UserVaultWithRole) {
+func CreateTestUserWithTokenAndVault(t testing.TB, db *database.DB) (token string, user *models.User, vault *models.UserVaultWithRole) {
 	token, user = CreateTestUserWithToken(t, db)
-	vault = createTestVault(t, db, user.ID)
+	vault = CreateTestVault(t, db, user.ID)
 	return token, user, vault
 }
 
-func createTestVault(t testing.TB, db *sql.DB, userID string) *models.UserVaultWithRole {
+func CreateTestVault(t testing.TB, db *database.DB, userID string) *models.UserVaultWithRole {
 	vaultRepo := repositories.NewVaultRepo(db)
-	vaultID, err := vaultRepo.CreateOne(t.Context(), userID, models.VaultRoleOwner, "vaultName_"+RandomString(8))
+	vaultID, err := vaultRepo.CreateOne(t.Context(), userID, models.VaultRoleOwner, "vaultName_"+RandomString(8), nil)
 	AssertNoError(t, err)
 	vault, err := vaultRepo.FindOneByID(t.Context(), userID, vaultID)
 	AssertNoError(t, err)
@@ -140,6 +217,17 @@ func DecodeJSON[T any](t testing.TB, body io.Reader) T {
 	return result
 }
 
+// DecodeFieldErrors decodes a problem response and returns its field-level errors keyed by field.
+func DecodeFieldErrors(t testing.TB, body io.Reader) map[string]string {
+	t.Helper()
+	p := DecodeJSON[problem.Problem](t, body)
+	errs := make(map[string]string, len(p.Errors))
+	for _, fieldErr := range p.Errors {
+		errs[fieldErr.Field] = fieldErr.Message
+	}
+	return errs
+}
+
 func AssertStatus(t testing.TB, got, want int) {
 	t.Helper()
 	if got != want {
@@ -191,3 +279,64 @@ func AssertNotEmpty[T any](t testing.TB, got T) {
 		return
 	}
 }
+
+func CreateTestExpenseCategory(t testing.TB, db *database.DB, userID, vaultID string) *models.ExpenseCategory {
+	categoryRepo := repositories.NewExpenseCategoryRepo(db)
+	categoryID, err := categoryRepo.CreateOne(t.Context(), "category_"+RandomString(8), models.ExpenseCategoryStatusActive, 0, nil, vaultID, userID)
+	AssertNoError(t, err)
+	category, err := categoryRepo.FindOneByID(t.Context(), categoryID)
+	AssertNoError(t, err)
+	return category
+}
+
+func CreateTestPaymentMethod(t testing.TB, db *database.DB, userID, vaultID string) *models.PaymentMethod {
+	paymentMethodRepo := repositories.NewPaymentMethodRepo(db)
+	paymentMethodID, err := paymentMethodRepo.CreateOne(t.Context(), "payment_method_"+RandomString(8), vaultID, userID)
+	AssertNoError(t, err)
+	paymentMethod, err := paymentMethodRepo.FindOneByID(t.Context(), paymentMethodID)
+	AssertNoError(t, err)
+	return paymentMethod
+}
+
+func CreateTestTag(t testing.TB, db *database.DB, userID, vaultID string) *models.Tag {
+	tagRepo := repositories.NewTagRepo(db)
+	tagID, err := tagRepo.CreateOne(t.Context(), "tag_"+RandomString(8), vaultID, userID)
+	AssertNoError(t, err)
+	tag, err := tagRepo.FindOneByID(t.Context(), tagID)
+	AssertNoError(t, err)
+	return tag
+}
+
+func CreateTestAccount(t testing.TB, db *database.DB, userID, vaultID string, openingBalance float64) *models.Account {
+	accountRepo := repositories.NewAccountRepo(db)
+	accountID, err := accountRepo.CreateOne(t.Context(), models.Account{ // nolint: exhaustruct
+		Name:           "account_" + RandomString(8),
+		Type:           models.AccountTypeChecking,
+		OpeningBalance: openingBalance,
+		VaultID:        vaultID,
+		CreatedBy:      userID,
+	})
+	AssertNoError(t, err)
+	account, err := accountRepo.FindOneByID(t.Context(), accountID)
+	AssertNoError(t, err)
+	return account
+}
+
+// CreateTestExpense creates an expense of 12.5 dated 2025-01-15 paid with a newly created payment method.
+func CreateTestExpense(t testing.TB, db *database.DB, userID, vaultID, categoryID string) *models.Expense {
+	paymentMethod := CreateTestPaymentMethod(t, db, userID, vaultID)
+	expenseRepo := repositories.NewExpenseRepo(db)
+	expenseID, err := expenseRepo.CreateOne(t.Context(), models.Expense{ // nolint: exhaustruct
+		Name:            "expense_" + RandomString(8),
+		Date:            "2025-01-15",
+		CategoryID:      categoryID,
+		Amount:          12.5,
+		PaymentMethodID: paymentMethod.ID,
+		VaultID:         vaultID,
+		CreatedBy:       userID,
+	})
+	AssertNoError(t, err)
+	expense, err := expenseRepo.FindOneByID(t.Context(), expenseID)
+	AssertNoError(t, err)
+	return expense
+}
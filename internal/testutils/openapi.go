@@ -0,0 +1,26 @@
+package testutils
+
+import (
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// validateResponses checks every response of next against the OpenAPI document, so
+// that handler tests fail when the document drifts from the actual API.
+func validateResponses(t testing.TB, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := httptest.NewRecorder()
+		next.ServeHTTP(recorder, r)
+
+		err := ValidateResponse(r.Method, r.URL.Path, recorder.Code, recorder.Header(), recorder.Body.Bytes())
+		if err != nil {
+			t.Errorf("response does not match OpenAPI document: %v", err)
+		}
+
+		maps.Copy(w.Header(), recorder.Header())
+		w.WriteHeader(recorder.Code)
+		_, _ = w.Write(recorder.Body.Bytes())
+	})
+}
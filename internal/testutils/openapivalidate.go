@@ -0,0 +1,282 @@
+package testutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/kkstas/tr-backend/internal/handlers/openapi"
+)
+
+type spec struct {
+	Paths      map[string]map[string]operation `json:"paths"`
+	Components struct {
+		Responses map[string]response `json:"responses"`
+		Schemas   map[string]*schema  `json:"schemas"`
+	} `json:"components"`
+}
+
+type operation struct {
+	Responses map[string]response `json:"responses"`
+}
+
+type response struct {
+	Ref     string               `json:"$ref"`
+	Content map[string]mediaType `json:"content"`
+}
+
+type mediaType struct {
+	Schema *schema `json:"schema"`
+}
+
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Format               string             `json:"format"`
+	Nullable             bool               `json:"nullable"`
+	Enum                 []any              `json:"enum"`
+	Properties           map[string]*schema `json:"properties"`
+	Required             []string           `json:"required"`
+	AdditionalProperties bool               `json:"additionalProperties"`
+	Items                *schema            `json:"items"`
+	AllOf                []*schema          `json:"allOf"`
+}
+
+var loadSpec = sync.OnceValues(func() (*spec, error) {
+	var s spec
+	if err := json.Unmarshal(openapi.JSON(), &s); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI document: %w", err)
+	}
+	return &s, nil
+})
+
+// ValidateResponse checks that a response to the request is documented and that its
+// body matches the documented schema. Objects must not contain undocumented properties
+// unless their schema allows additional properties, so that new fields can't be added
+// without documenting them.
+func ValidateResponse(method, path string, status int, header http.Header, body []byte) error {
+	s, err := loadSpec()
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.findResponse(method, path, status)
+	if err != nil {
+		return err
+	}
+
+	if len(resp.Content) == 0 {
+		if len(body) > 0 {
+			return fmt.Errorf("%s %s: %d response must not have a body, got %q", method, path, status, body)
+		}
+		return nil
+	}
+
+	contentType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	media, ok := resp.Content[contentType]
+	if !ok {
+		return fmt.Errorf("%s %s: %d response has undocumented content type %q", method, path, status, contentType)
+	}
+	if media.Schema == nil || !strings.Contains(contentType, "json") {
+		return nil
+	}
+
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return fmt.Errorf("%s %s: %d response is not valid JSON: %w", method, path, status, err)
+	}
+	if err := s.validate(media.Schema, v, "body"); err != nil {
+		return fmt.Errorf("%s %s: %d response: %w", method, path, status, err)
+	}
+	return nil
+}
+
+func (s *spec) findResponse(method, path string, status int) (response, error) {
+	var op *operation
+	if template, ok := s.findPath(path); ok {
+		if o, ok := s.Paths[template][strings.ToLower(method)]; ok {
+			op = &o
+		}
+	}
+
+	if op == nil {
+		// unknown routes fall through to the not found handler
+		if status == http.StatusNotFound {
+			return s.resolveResponse(response{Ref: "#/components/responses/NotFound"}) // nolint: exhaustruct
+		}
+		return response{}, fmt.Errorf("%s %s is not documented", method, path) // nolint: exhaustruct
+	}
+
+	resp, ok := op.Responses[strconv.Itoa(status)]
+	if !ok {
+		return response{}, fmt.Errorf("%s %s: status %d is not documented", method, path, status) // nolint: exhaustruct
+	}
+	return s.resolveResponse(resp)
+}
+
+// findPath returns the path template matching path, preferring templates with
+// more literal segments, e.g. /vaults/trash over /vaults/{vaultID}.
+func (s *spec) findPath(path string) (string, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	best, bestLiterals := "", -1
+	for template := range s.Paths {
+		templateSegments := strings.Split(strings.Trim(template, "/"), "/")
+		if len(templateSegments) != len(segments) {
+			continue
+		}
+
+		literals := 0
+		matches := true
+		for i, segment := range templateSegments {
+			if strings.HasPrefix(segment, "{") {
+				continue
+			}
+			if segment != segments[i] {
+				matches = false
+				break
+			}
+			literals++
+		}
+		if matches && literals > bestLiterals {
+			best, bestLiterals = template, literals
+		}
+	}
+	return best, bestLiterals >= 0
+}
+
+func (s *spec) resolveResponse(resp response) (response, error) {
+	if resp.Ref == "" {
+		return resp, nil
+	}
+	resolved, ok := s.Components.Responses[strings.TrimPrefix(resp.Ref, "#/components/responses/")]
+	if !ok {
+		return response{}, fmt.Errorf("unknown response %q", resp.Ref) // nolint: exhaustruct
+	}
+	return resolved, nil
+}
+
+func (s *spec) resolveSchema(sch *schema) (*schema, error) {
+	if sch.Ref == "" {
+		return sch, nil
+	}
+	resolved, ok := s.Components.Schemas[strings.TrimPrefix(sch.Ref, "#/components/schemas/")]
+	if !ok {
+		return nil, fmt.Errorf("unknown schema %q", sch.Ref)
+	}
+	return resolved, nil
+}
+
+// mergeAllOf combines allOf object schemas into one object schema.
+func (s *spec) mergeAllOf(sch *schema) (*schema, error) {
+	merged := &schema{Type: "object", Properties: map[string]*schema{}} // nolint: exhaustruct
+	for _, part := range sch.AllOf {
+		part, err := s.resolveSchema(part)
+		if err != nil {
+			return nil, err
+		}
+		if len(part.AllOf) > 0 {
+			if part, err = s.mergeAllOf(part); err != nil {
+				return nil, err
+			}
+		}
+		for name, prop := range part.Properties {
+			merged.Properties[name] = prop
+		}
+		merged.Required = append(merged.Required, part.Required...)
+		merged.AdditionalProperties = merged.AdditionalProperties || part.AdditionalProperties
+	}
+	return merged, nil
+}
+
+func (s *spec) validate(sch *schema, v any, at string) error {
+	sch, err := s.resolveSchema(sch)
+	if err != nil {
+		return err
+	}
+	if len(sch.AllOf) > 0 {
+		if sch, err = s.mergeAllOf(sch); err != nil {
+			return err
+		}
+	}
+
+	if v == nil {
+		if sch.Nullable || sch.Type == "" {
+			return nil
+		}
+		return fmt.Errorf("%s: must not be null", at)
+	}
+
+	if len(sch.Enum) > 0 && !slices.Contains(sch.Enum, v) {
+		return fmt.Errorf("%s: %v is not one of %v", at, v, sch.Enum)
+	}
+
+	switch sch.Type {
+	case "":
+		return nil
+	case "string":
+		if _, ok := v.(string); !ok {
+			return fmt.Errorf("%s: expected string, got %T", at, v)
+		}
+	case "number":
+		if _, ok := v.(float64); !ok {
+			return fmt.Errorf("%s: expected number, got %T", at, v)
+		}
+	case "integer":
+		if f, ok := v.(float64); !ok || f != math.Trunc(f) {
+			return fmt.Errorf("%s: expected integer, got %v", at, v)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s: expected boolean, got %T", at, v)
+		}
+	case "array":
+		items, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("%s: expected array, got %T", at, v)
+		}
+		for i, item := range items {
+			if err := s.validate(sch.Items, item, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+				return err
+			}
+		}
+	case "object":
+		return s.validateObject(sch, v, at)
+	default:
+		return fmt.Errorf("%s: unsupported schema type %q", at, sch.Type)
+	}
+	return nil
+}
+
+func (s *spec) validateObject(sch *schema, v any, at string) error {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return fmt.Errorf("%s: expected object, got %T", at, v)
+	}
+
+	for _, name := range sch.Required {
+		if _, ok := obj[name]; !ok {
+			return fmt.Errorf("%s: missing required property %q", at, name)
+		}
+	}
+
+	for name, value := range obj {
+		prop, ok := sch.Properties[name]
+		if !ok {
+			if sch.AdditionalProperties || sch.Properties == nil {
+				continue
+			}
+			return fmt.Errorf("%s: undocumented property %q", at, name)
+		}
+		if err := s.validate(prop, value, at+"."+name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
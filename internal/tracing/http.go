@@ -0,0 +1,45 @@
+package tracing
+
+import (
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kkstas/tr-backend/internal/logging"
+	"github.com/kkstas/tr-backend/internal/utils"
+)
+
+// Middleware starts a server span for every request. The span is renamed after the matched
+// route pattern once next returns, that is after the ServeMux has set r.Pattern. Other
+// middlewares may run in between, as long as the ServeMux receives the same request.
+func Middleware(provider trace.TracerProvider, next http.Handler) http.Handler {
+	tracer := provider.Tracer(instrumentationName)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := tracer.Start(r.Context(), r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("url.path", r.URL.Path),
+				attribute.String("request.id", logging.RequestID(r.Context())),
+			),
+		)
+		defer span.End()
+
+		r = r.WithContext(ctx)
+		recorder := utils.NewResponseRecorder(w)
+		next.ServeHTTP(recorder, r)
+
+		if _, route, found := strings.Cut(r.Pattern, " "); found {
+			span.SetName(r.Method + " " + route)
+			span.SetAttributes(attribute.String("http.route", route))
+		}
+		span.SetAttributes(attribute.Int("http.response.status_code", recorder.Status))
+		if recorder.Status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(recorder.Status))
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package tracing_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/kkstas/tr-backend/internal/testutils"
+	"github.com/kkstas/tr-backend/internal/tracing"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /vaults/{vaultID}", func(w http.ResponseWriter, r *http.Request) {
+		_, span := tracing.Start(r.Context(), "VaultService.FindOneByID")
+		span.End()
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	handler := tracing.Middleware(provider, mux)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/vaults/123", nil))
+
+	spans := recorder.Ended()
+	testutils.AssertEqual(t, len(spans), 2)
+	child, server := spans[0], spans[1]
+
+	testutils.AssertEqual(t, server.Name(), "GET /vaults/{vaultID}")
+	testutils.AssertEqual(t, server.Status().Code, codes.Error)
+	testutils.AssertEqual(t, child.Name(), "VaultService.FindOneByID")
+	testutils.AssertEqual(t, child.Parent().SpanID(), server.SpanContext().SpanID())
+
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, attr := range server.Attributes() {
+		attrs[attr.Key] = attr.Value
+	}
+	testutils.AssertEqual(t, attrs["http.route"].AsString(), "/vaults/{vaultID}")
+	testutils.AssertEqual(t, attrs["http.response.status_code"].AsInt64(), int64(http.StatusInternalServerError))
+}
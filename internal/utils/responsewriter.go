@@ -0,0 +1,31 @@
+package utils
+
+import "net/http"
+
+// ResponseRecorder passes a response through to the wrapped ResponseWriter and captures
+// its status code and size, so that middlewares can report them once the handler returns.
+type ResponseRecorder struct {
+	http.ResponseWriter
+	Status int
+	Size   int
+}
+
+func NewResponseRecorder(w http.ResponseWriter) *ResponseRecorder {
+	return &ResponseRecorder{ResponseWriter: w, Status: http.StatusOK, Size: 0}
+}
+
+func (r *ResponseRecorder) WriteHeader(status int) {
+	r.Status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *ResponseRecorder) Write(b []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(b)
+	r.Size += n
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the wrapped ResponseWriter.
+func (r *ResponseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}